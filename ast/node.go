@@ -67,12 +67,17 @@ type Node struct {
 	CodeBlockFenceOffset int    `json:",omitempty"`
 	CodeBlockOpenFence   []byte `json:",omitempty"`
 	CodeBlockInfo        []byte `json:",omitempty"`
+	CodeBlockInfoAttrs   []byte `json:",omitempty"` // 信息字符串中语言后的属性部分
 	CodeBlockCloseFence  []byte `json:",omitempty"`
 
 	// HTML 块
 
 	HtmlBlockType int `json:",omitempty"` // 规范中定义的 HTML 块类型（1-7）
 
+	// 媒体嵌入
+
+	MediaEmbedLink []byte `json:",omitempty"` // 自动嵌入的 iframe、video 和 audio 对应的原始链接 Markdown
+
 	// 列表、列表项
 
 	ListData *ListData `json:",omitempty"`
@@ -92,6 +97,7 @@ type Node struct {
 
 	LinkType     int    `json:",omitempty"` // 链接类型，0：内联链接 [foo](/bar)，1：链接引用定义 [foo]: /bar，2：自动链接，3：链接引用 [foo]
 	LinkRefLabel []byte `json:",omitempty"` // 链接引用 label，[label] 或者 [text][label] 形式，[label] 情况下 text 和 label 相同
+	LinkSubtype  string `json:",omitempty"` // 链接子类型，由自定义自动链接规则 Linkifier 生成的链接为规则名称，比如 issue
 
 	// 标题
 
@@ -0,0 +1,30 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"github.com/88250/lute/render"
+)
+
+// Md2Slack 将 Markdown 转换为 Slack mrkdwn。
+func (lute *Lute) Md2Slack(markdown string) (mrkdwn string) {
+	return lute.md2(markdown, render.NewSlackRenderer)
+}
+
+// Md2Telegram 将 Markdown 转换为 Telegram MarkdownV2。
+func (lute *Lute) Md2Telegram(markdown string) (markdownV2 string) {
+	return lute.md2(markdown, render.NewTelegramRenderer)
+}
+
+// Md2Discord 将 Markdown 转换为 Discord 消息格式。
+func (lute *Lute) Md2Discord(markdown string) (message string) {
+	return lute.md2(markdown, render.NewDiscordRenderer)
+}
@@ -0,0 +1,437 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/html/atom"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+// EMLAttachment 描述了邮件中的一个附件或者内嵌资源。
+type EMLAttachment struct {
+	Name        string // 文件名，Markdown 中使用该名称引用附件
+	ContentType string // MIME 类型
+	ContentID   string // 内嵌资源的 Content-ID（不包含尖括号），普通附件为空
+	Data        []byte // 解码后的内容
+}
+
+// eml 描述了解析后的邮件正文和附件。
+type eml struct {
+	html, plain string
+	flowed      bool // 纯文本正文是否为 format=flowed
+	attachments []*EMLAttachment
+}
+
+// EML2Markdown 将 RFC 5322/MIME 格式的邮件转换为 Markdown。
+//
+// 优先使用 text/html 正文，没有时使用 text/plain 正文；发件人、收件人、日期和主题写入 YAML Front Matter，引用的回复内容转换为引述块。
+// 附件通过 attachments 返回，正文中的 cid: 内嵌图片改写为对应附件的文件名，调用方需要将附件保存到 Markdown 文件所在的目录。
+func (lute *Lute) EML2Markdown(r io.Reader) (markdown string, attachments []*EMLAttachment, err error) {
+	msg, err := mail.ReadMessage(r)
+	if nil != err {
+		return
+	}
+
+	e := &eml{}
+	if err = e.parsePart(textproto.MIMEHeader(msg.Header), msg.Body); nil != err {
+		return
+	}
+	e.nameAttachments()
+
+	var htmlStr string
+	if "" != strings.TrimSpace(e.html) {
+		htmlStr = e.html
+	} else {
+		htmlStr = emlPlainHTML(e.plain, e.flowed)
+	}
+	referenced := map[*EMLAttachment]bool{}
+	root := lute.parseHTML(htmlStr)
+	if nil != root {
+		e.rewriteCIDs(root, referenced)
+		emlQuotes(root)
+		buf := &bytes.Buffer{}
+		for c := root.FirstChild; nil != c; c = c.NextSibling {
+			if err = html.Render(buf, c); nil != err {
+				return
+			}
+		}
+		htmlStr = buf.String()
+	}
+
+	var links []*EMLAttachment
+	for _, attachment := range e.attachments {
+		if !referenced[attachment] {
+			links = append(links, attachment)
+		}
+	}
+	if 0 < len(links) {
+		// 没有在正文中引用的附件以列表的形式放在最后
+		buf := &bytes.Buffer{}
+		buf.WriteString("<ul>")
+		for _, attachment := range links {
+			buf.WriteString("<li><a href=\"" + html.EscapeHTMLStr(url.PathEscape(attachment.Name)) + "\">" + html.EscapeHTMLStr(attachment.Name) + "</a></li>")
+		}
+		buf.WriteString("</ul>")
+		htmlStr += buf.String()
+	}
+	tree := lute.HTML2Tree(htmlStr)
+	if nil == tree {
+		tree = &parse.Tree{Name: "", Root: &ast.Node{Type: ast.NodeDocument}, Context: &parse.Context{ParseOption: lute.ParseOptions}}
+	}
+
+	decoder := &mime.WordDecoder{CharsetReader: emlCharsetReader}
+	date := msg.Header.Get("Date")
+	if t, parseErr := msg.Header.Date(); nil == parseErr {
+		date = t.Format(time.RFC3339)
+	}
+	subject, decodeErr := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if nil != decodeErr {
+		subject = msg.Header.Get("Subject")
+	}
+	frontMatter := yamlFrontMatter([][2]string{
+		{"from", emlAddresses(msg.Header.Get("From"), decoder)},
+		{"to", emlAddresses(msg.Header.Get("To"), decoder)},
+		{"date", date},
+		{"subject", strings.TrimSpace(subject)},
+	})
+	if nil != frontMatter {
+		tree.Root.PrependChild(frontMatter)
+	}
+	markdown = lute.formatHTML2MdTree(tree)
+	attachments = e.attachments
+	return
+}
+
+// parsePart 解析头为 header、内容为 body 的 MIME 部分，multipart 会递归解析其中的各个部分。
+func (e *eml) parsePart(header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if nil != err {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if io.EOF == err {
+				return nil
+			}
+			if nil != err {
+				return err
+			}
+			if err = e.parsePart(part.Header, part); nil != err {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(emlDecodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+	if nil != err {
+		return err
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	name := dispositionParams["filename"]
+	if "" == name {
+		name = params["name"]
+	}
+	if "attachment" != disposition && "" == name {
+		switch mediaType {
+		case "text/html":
+			if "" == e.html {
+				e.html = emlDecodeCharset(params["charset"], data)
+			}
+			return nil
+		case "text/plain":
+			if "" == e.plain {
+				e.plain = emlDecodeCharset(params["charset"], data)
+				e.flowed = strings.EqualFold(params["format"], "flowed")
+			}
+			return nil
+		}
+	}
+
+	decoder := &mime.WordDecoder{CharsetReader: emlCharsetReader}
+	if decoded, err := decoder.DecodeHeader(name); nil == err {
+		name = decoded
+	}
+	contentID := strings.TrimSpace(header.Get("Content-ID"))
+	contentID = strings.TrimSuffix(strings.TrimPrefix(contentID, "<"), ">")
+	e.attachments = append(e.attachments, &EMLAttachment{Name: name, ContentType: mediaType, ContentID: contentID, Data: data})
+	return nil
+}
+
+// emlExtensions 是常见 MIME 类型对应的扩展名，mime.ExtensionsByType 的结果依赖系统配置并且没有优先顺序。
+var emlExtensions = map[string]string{
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"image/svg+xml":   ".svg",
+	"application/pdf": ".pdf",
+	"text/plain":      ".txt",
+	"text/html":       ".html",
+	"text/calendar":   ".ics",
+	"message/rfc822":  ".eml",
+}
+
+// nameAttachments 为附件生成不重复的文件名，没有文件名的附件根据 Content-ID 或者 MIME 类型命名。
+func (e *eml) nameAttachments() {
+	names := map[string]bool{}
+	for i, attachment := range e.attachments {
+		name := path.Base(strings.ReplaceAll(attachment.Name, "\\", "/"))
+		name = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`/\:*?"<>|`, r) || 0x20 > r {
+				return '_'
+			}
+			return r
+		}, strings.TrimSpace(name))
+		if "" == name || "." == name || ".." == name || "/" == name {
+			name = "attachment-" + strconv.Itoa(i+1)
+			if "" != attachment.ContentID {
+				name = strings.Split(attachment.ContentID, "@")[0]
+			}
+			if ext, ok := emlExtensions[attachment.ContentType]; ok && "" == path.Ext(name) {
+				name += ext
+			} else if exts, _ := mime.ExtensionsByType(attachment.ContentType); 0 < len(exts) && "" == path.Ext(name) {
+				name += exts[0]
+			}
+		}
+
+		ext := path.Ext(name)
+		base := strings.TrimSuffix(name, ext)
+		for n := 2; names[strings.ToLower(name)]; n++ {
+			name = base + "-" + strconv.Itoa(n) + ext
+		}
+		names[strings.ToLower(name)] = true
+		attachment.Name = name
+	}
+}
+
+// rewriteCIDs 将 DOM n 中值为 cid:<Content-ID> 的 src 属性改写为附件文件名，被引用的附件记录到 referenced 中。
+func (e *eml) rewriteCIDs(n *html.Node, referenced map[*EMLAttachment]bool) {
+	if html.ElementNode == n.Type {
+		for i, attr := range n.Attr {
+			if "src" != attr.Key {
+				continue
+			}
+			val := strings.TrimSpace(attr.Val)
+			if 4 > len(val) || !strings.EqualFold("cid:", val[:4]) {
+				continue
+			}
+			cid, err := url.PathUnescape(val[4:])
+			if nil != err {
+				cid = val[4:]
+			}
+			for _, attachment := range e.attachments {
+				if "" != attachment.ContentID && cid == attachment.ContentID {
+					n.Attr[i].Val = url.PathEscape(attachment.Name)
+					referenced[attachment] = true
+					break
+				}
+			}
+		}
+	}
+	for c := n.FirstChild; nil != c; c = c.NextSibling {
+		e.rewriteCIDs(c, referenced)
+	}
+}
+
+// emlDecodeTransfer 按照 Content-Transfer-Encoding 解码 body。
+func emlDecodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &emlBase64Reader{r: body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+	return body
+}
+
+// emlBase64Reader 去掉 base64 内容中的空白，base64.NewDecoder 只能忽略换行。
+type emlBase64Reader struct {
+	r io.Reader
+}
+
+func (r *emlBase64Reader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	j := 0
+	for _, b := range p[:n] {
+		if ' ' != b && '\t' != b {
+			p[j] = b
+			j++
+		}
+	}
+	return j, err
+}
+
+// emlDecodeCharset 将字符集为 charset 的 data 转换为 UTF-8 字符串。
+func emlDecodeCharset(charset string, data []byte) string {
+	reader, err := emlCharsetReader(charset, bytes.NewReader(data))
+	if nil != err {
+		return string(data)
+	}
+	ret, err := io.ReadAll(reader)
+	if nil != err {
+		return string(data)
+	}
+	return string(ret)
+}
+
+// emlAddresses 解码地址列表头 value，多个地址使用逗号分隔。
+func emlAddresses(value string, decoder *mime.WordDecoder) string {
+	if "" == strings.TrimSpace(value) {
+		return ""
+	}
+
+	parser := &mail.AddressParser{WordDecoder: decoder}
+	addresses, err := parser.ParseList(value)
+	if nil != err {
+		if decoded, err := decoder.DecodeHeader(value); nil == err {
+			return strings.TrimSpace(decoded)
+		}
+		return strings.TrimSpace(value)
+	}
+
+	var ret []string
+	for _, address := range addresses {
+		if "" == address.Name {
+			ret = append(ret, address.Address)
+		} else {
+			ret = append(ret, address.Name+" <"+address.Address+">")
+		}
+	}
+	return strings.Join(ret, ", ")
+}
+
+var emlQuoteHeader = regexp.MustCompile(`(?m)^\s*(-{2,}\s*(Original Message|Forwarded message|原始邮件|转发的邮件)\s*-{2,}|_{10,})\s*$`)
+
+// emlPlainHTML 将纯文本正文 plain 转换为 HTML，以 > 开头的引用行转换为嵌套的引述块，flowed 为 true 时合并 format=flowed 的软换行。
+func emlPlainHTML(plain string, flowed bool) string {
+	plain = strings.ReplaceAll(plain, "\r\n", "\n")
+	if loc := emlQuoteHeader.FindStringIndex(plain); nil != loc {
+		// Outlook 等客户端不使用 > 标记引用，分隔线之后的内容都是被引用的原始邮件
+		var quoted []string
+		for _, line := range strings.Split(strings.TrimLeft(plain[loc[1]:], "\n"), "\n") {
+			quoted = append(quoted, "> "+line)
+		}
+		plain = plain[:loc[0]] + strings.Join(quoted, "\n")
+	}
+
+	type line struct {
+		depth int
+		text  string
+	}
+	var lines []*line
+	for _, text := range strings.Split(plain, "\n") {
+		depth := 0
+		for strings.HasPrefix(text, ">") {
+			depth++
+			text = strings.TrimPrefix(strings.TrimPrefix(text, ">"), " ")
+		}
+		if flowed {
+			text = strings.TrimPrefix(text, " ") // 空格填充
+			if last := len(lines) - 1; 0 <= last && lines[last].depth == depth && strings.HasSuffix(lines[last].text, " ") && "-- " != lines[last].text {
+				lines[last].text += text
+				continue
+			}
+		}
+		lines = append(lines, &line{depth: depth, text: text})
+	}
+
+	buf := &bytes.Buffer{}
+	depth := 0
+	var paragraph []string
+	flush := func() {
+		if 0 < len(paragraph) {
+			buf.WriteString("<p>" + strings.Join(paragraph, "<br>") + "</p>")
+			paragraph = nil
+		}
+	}
+	for _, l := range lines {
+		if l.depth != depth {
+			flush()
+			for ; depth < l.depth; depth++ {
+				buf.WriteString("<blockquote>")
+			}
+			for ; depth > l.depth; depth-- {
+				buf.WriteString("</blockquote>")
+			}
+		}
+		if "" == strings.TrimSpace(l.text) {
+			flush()
+			continue
+		}
+		paragraph = append(paragraph, html.EscapeHTMLStr(strings.TrimRight(l.text, " ")))
+	}
+	flush()
+	for ; 0 < depth; depth-- {
+		buf.WriteString("</blockquote>")
+	}
+	return buf.String()
+}
+
+// emlQuotes 将 HTML 正文 root 中各邮件客户端使用 div 标记的引用内容转换为引述块。
+func emlQuotes(root *html.Node) {
+	var quotes, wrappers []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			switch {
+			case atom.Blockquote == c.DataAtom:
+				continue
+			case hasClass(c, "gmail_quote") && nil != domFind(c, func(q *html.Node) bool { return atom.Blockquote == q.DataAtom }):
+				wrappers = append(wrappers, c) // Gmail：引用说明和引述块的包裹
+			case hasClass(c, "yahoo_quoted") || hasClass(c, "gmail_quote"):
+				quotes = append(quotes, c)
+				continue
+			case "divRplyFwdMsg" == util.DomAttrValue(c, "id") || "appendonsend" == util.DomAttrValue(c, "id"):
+				// Outlook：回复分隔之后的内容都是被引用的原始邮件
+				quote := &html.Node{Type: html.ElementNode, DataAtom: atom.Blockquote, Data: atom.Blockquote.String()}
+				for s := c; nil != s; {
+					next := s.NextSibling
+					s.Unlink()
+					quote.AppendChild(s)
+					s = next
+				}
+				n.AppendChild(quote)
+				return
+			}
+			walk(c)
+		}
+	}
+	walk(root)
+
+	for _, n := range quotes {
+		n.DataAtom, n.Data, n.Attr = atom.Blockquote, atom.Blockquote.String(), nil
+	}
+	for _, n := range wrappers {
+		unwrapDOM(n)
+	}
+}
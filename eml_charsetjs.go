@@ -0,0 +1,39 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+//go:build javascript
+// +build javascript
+
+package lute
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// emlCharsetReader 返回将字符集为 charset 的 input 转换为 UTF-8 的 Reader，不支持的字符集原样返回。
+//
+// JS 版只支持 UTF-8 和 ISO-8859-1，因为引入 golang.org/x/text/encoding 后打包体积太大。
+func emlCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "iso-8859-1", "latin1", "l1":
+		data, err := io.ReadAll(input)
+		if nil != err {
+			return nil, err
+		}
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return bytes.NewReader([]byte(string(runes))), nil
+	}
+	return input, nil
+}
@@ -0,0 +1,454 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/html/atom"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/render"
+	"github.com/88250/lute/util"
+)
+
+// EPUBMeta 描述了 EPUB 电子书的元数据。
+type EPUBMeta struct {
+	Identifier string    // 唯一标识，为空时自动生成 urn:uuid
+	Title      string    // 书名
+	Language   string    // 语言，为空时使用 zh-CN
+	Authors    []string  // 作者
+	Modified   time.Time // 修改时间，为零值时使用当前时间
+	BaseDir    string    // 本地图片相对路径的基础目录
+}
+
+// epubChapter 描述了 EPUB 中的一个章节。
+type epubChapter struct {
+	href     string
+	title    string
+	xhtml    []byte
+	headings []*render.Heading
+	remote   bool // 是否引用了远程图片
+	mathML   bool // 是否包含 MathML
+}
+
+// epubImage 描述了 EPUB 中内嵌的一张图片。
+type epubImage struct {
+	href      string
+	mediaType string
+	data      []byte
+}
+
+// Markdown2EPUB 将一篇或多篇 Markdown 文档转换为 EPUB 3 电子书。
+//
+// 每篇文档在一级标题处拆分为章节，目录根据各章节的标题生成，文档中引用的本地图片会被打包进电子书。
+func (lute *Lute) Markdown2EPUB(docs []string, meta *EPUBMeta) (epub []byte, err error) {
+	if nil == meta {
+		meta = &EPUBMeta{}
+	}
+	lang := meta.Language
+	if "" == lang {
+		lang = "zh-CN"
+	}
+
+	options := *lute.RenderOptions
+	options.HeadingID = true // 导航文档需要链接到标题
+	options.HeadingAnchor = false
+	options.ImageLazyLoading = ""
+	options.LinkBase = ""
+	options.LinkPrefix = ""
+	options.MathML = true // 阅读器不执行脚本，公式需要预先转换
+
+	var chapters []*epubChapter
+	var images []*epubImage
+	imageHrefs := map[string]string{}
+	for _, doc := range docs {
+		tree := parse.Parse("", []byte(doc), lute.ParseOptions)
+		for _, chapterTree := range epubSplitChapters(tree) {
+			var remote bool
+			if remote, err = epubEmbedImages(chapterTree, meta.BaseDir, imageHrefs, &images); nil != err {
+				return
+			}
+
+			renderer := render.NewHtmlRenderer(chapterTree, &options)
+			for nodeType, rendererFunc := range lute.Md2HTMLRendererFuncs {
+				renderer.ExtRendererFuncs[nodeType] = rendererFunc
+			}
+			var body []byte
+			if body, err = polyglotXHTML(renderer.Render()); nil != err {
+				return
+			}
+
+			chapter := &epubChapter{href: "chapter-" + strconv.Itoa(len(chapters)+1) + ".xhtml", headings: renderer.Headings(), remote: remote}
+			chapter.mathML = bytes.Contains(body, []byte("<math "))
+			if h1 := chapterTree.Root.ChildByType(ast.NodeHeading); nil != h1 && 1 == h1.HeadingLevel {
+				chapter.title = epubHeadingText(h1)
+			}
+			if "" == chapter.title {
+				chapter.title = meta.Title
+			}
+			chapter.xhtml = epubXHTML(chapter.title, lang, body)
+			chapters = append(chapters, chapter)
+		}
+	}
+
+	var nav []byte
+	if nav, err = epubNav(meta.Title, lang, chapters); nil != err {
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	// mimetype 必须是第一个条目且不能压缩
+	entry, err := w.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if nil != err {
+		return
+	}
+	if _, err = entry.Write([]byte("application/epub+zip")); nil != err {
+		return
+	}
+	add := func(name string, data []byte) (err error) {
+		entry, err := w.Create(name)
+		if nil != err {
+			return
+		}
+		_, err = entry.Write(data)
+		return
+	}
+	if err = add("META-INF/container.xml", []byte(epubContainer)); nil != err {
+		return
+	}
+	if err = add("OEBPS/content.opf", epubPackage(meta, lang, chapters, images)); nil != err {
+		return
+	}
+	if err = add("OEBPS/nav.xhtml", nav); nil != err {
+		return
+	}
+	for _, chapter := range chapters {
+		if err = add("OEBPS/"+chapter.href, chapter.xhtml); nil != err {
+			return
+		}
+	}
+	for _, image := range images {
+		if err = add("OEBPS/"+image.href, image.data); nil != err {
+			return
+		}
+	}
+	if err = w.Close(); nil != err {
+		return
+	}
+	epub = buf.Bytes()
+	return
+}
+
+// epubSplitChapters 在一级标题处将 tree 拆分为多个章节树。
+//
+// 脚注定义会被移动到第一个引用它的章节中，链接引用定义在解析时已经展开，不再需要。
+func epubSplitChapters(tree *parse.Tree) (ret []*parse.Tree) {
+	var defs []*ast.Node
+	var blocks []*ast.Node
+	for c := tree.Root.FirstChild; nil != c; c = c.Next {
+		switch c.Type {
+		case ast.NodeFootnotesDefBlock:
+			for def := c.FirstChild; nil != def; def = def.Next {
+				defs = append(defs, def)
+			}
+		case ast.NodeLinkRefDefBlock:
+		default:
+			blocks = append(blocks, c)
+		}
+	}
+
+	newChapter := func() *parse.Tree {
+		chapter := &parse.Tree{Name: tree.Name, Context: tree.Context}
+		chapter.Root = &ast.Node{Type: ast.NodeDocument}
+		ret = append(ret, chapter)
+		return chapter
+	}
+	var chapter *parse.Tree
+	for _, block := range blocks {
+		if nil == chapter || (ast.NodeHeading == block.Type && 1 == block.HeadingLevel && nil != chapter.Root.FirstChild) {
+			chapter = newChapter()
+		}
+		chapter.Root.AppendChild(block)
+	}
+	if 1 > len(ret) {
+		newChapter()
+	}
+
+	for _, c := range ret {
+		defBlock := &ast.Node{Type: ast.NodeFootnotesDefBlock}
+		ast.Walk(c.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+			if !entering || ast.NodeFootnotesRef != n.Type {
+				return ast.WalkContinue
+			}
+			for i, def := range defs {
+				if nil != def && bytes.EqualFold(def.Tokens, n.Tokens) {
+					defBlock.AppendChild(def)
+					defs[i] = nil
+					break
+				}
+			}
+			return ast.WalkContinue
+		})
+		if nil != defBlock.FirstChild {
+			c.Root.AppendChild(defBlock)
+		}
+	}
+	return
+}
+
+// epubEmbedImages 收集 tree 中引用的本地图片，并将图片地址改写为电子书内的路径，remote 返回是否引用了远程图片。
+func epubEmbedImages(tree *parse.Tree, baseDir string, imageHrefs map[string]string, images *[]*epubImage) (remote bool, err error) {
+	ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeImage != n.Type {
+			return ast.WalkContinue
+		}
+		dest := n.ChildByType(ast.NodeLinkDest)
+		if nil == dest {
+			return ast.WalkContinue
+		}
+		if !epubLocalPath(util.BytesToStr(dest.Tokens)) {
+			if !bytes.HasPrefix(dest.Tokens, []byte("data:")) {
+				remote = true
+			}
+			return ast.WalkContinue
+		}
+
+		if !localImageInBaseDir(util.BytesToStr(dest.Tokens)) {
+			err = errors.New("image [" + util.BytesToStr(dest.Tokens) + "] is outside the base directory")
+			return ast.WalkStop
+		}
+		p := localImagePath(baseDir, util.BytesToStr(dest.Tokens))
+		href, ok := imageHrefs[p]
+		if !ok {
+			mediaType, data, readErr := readLocalImage(p)
+			if nil != readErr {
+				err = readErr
+				return ast.WalkStop
+			}
+			href = "images/" + strconv.Itoa(len(*images)+1) + strings.ToLower(filepath.Ext(p))
+			imageHrefs[p] = href
+			*images = append(*images, &epubImage{href: href, mediaType: mediaType, data: data})
+		}
+		dest.Tokens = []byte(href)
+		return ast.WalkContinue
+	})
+	return
+}
+
+// epubLocalPath 判断图片地址是否为本地文件路径。
+func epubLocalPath(dest string) bool {
+	if "" == dest || strings.HasPrefix(dest, "//") || strings.HasPrefix(dest, "data:") {
+		return false
+	}
+	if u, err := url.Parse(dest); nil == err && 1 < len(u.Scheme) {
+		// 单字母 scheme 是 Windows 盘符
+		return false
+	}
+	return true
+}
+
+// localImagePath 返回本地图片地址 dest 对应的文件路径，相对路径基于 baseDir。
+func localImagePath(baseDir, dest string) (ret string) {
+	ret, err := url.PathUnescape(dest)
+	if nil != err {
+		ret = dest
+	}
+	if !filepath.IsAbs(ret) {
+		ret = filepath.Join(baseDir, ret)
+	}
+	return
+}
+
+// localImageInBaseDir 判断本地图片地址 dest 是否指向基础目录下的文件，绝对路径和超出基础目录的相对路径都不允许读取。
+func localImageInBaseDir(dest string) bool {
+	p := localImagePath("", dest)
+	if filepath.IsAbs(p) || strings.HasPrefix(p, "/") || strings.HasPrefix(p, "\\") || "" != filepath.VolumeName(p) {
+		return false
+	}
+	return ".." != p && !strings.HasPrefix(p, ".."+string(filepath.Separator))
+}
+
+// readLocalImage 读取本地图片文件，mediaType 根据扩展名确定。
+func readLocalImage(p string) (mediaType string, data []byte, err error) {
+	if data, err = os.ReadFile(p); nil != err {
+		return
+	}
+	if mediaType, _, _ = mime.ParseMediaType(mime.TypeByExtension(strings.ToLower(filepath.Ext(p)))); "" == mediaType {
+		mediaType = "application/octet-stream"
+	}
+	return
+}
+
+// polyglotXHTML 将 HTML 片段重新序列化为同时满足 HTML 和 XML 语法的 XHTML 片段。
+func polyglotXHTML(fragment []byte) (ret []byte, err error) {
+	nodes, err := html.ParseFragment(bytes.NewReader(fragment), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if nil != err {
+		return
+	}
+	buf := &bytes.Buffer{}
+	for _, n := range nodes {
+		if err = html.Render(buf, n); nil != err {
+			return
+		}
+	}
+	ret = buf.Bytes()
+	return
+}
+
+// epubHeadingText 返回标题 heading 渲染后的纯文本，包括 HTML 实体、代码和公式，输出时再进行转义。
+func epubHeadingText(heading *ast.Node) string {
+	buf := &bytes.Buffer{}
+	ast.Walk(heading, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+		switch n.Type {
+		case ast.NodeText, ast.NodeLinkText, ast.NodeHTMLEntity, ast.NodeBackslashContent, ast.NodeCodeSpanContent, ast.NodeInlineMathContent:
+			buf.Write(n.Tokens)
+		case ast.NodeTextMark:
+			buf.WriteString(n.TextMarkTextContent)
+		case ast.NodeKramdownSpanIAL:
+			return ast.WalkSkipChildren
+		}
+		return ast.WalkContinue
+	})
+	return strings.TrimSpace(buf.String())
+}
+
+// epubXHTML 生成 XHTML 内容文档。
+func epubXHTML(title, lang string, body []byte) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<!DOCTYPE html>\n")
+	buf.WriteString("<html xmlns=\"http://www.w3.org/1999/xhtml\" xmlns:epub=\"http://www.idpf.org/2007/ops\" xml:lang=\"" + html.EscapeHTMLStr(lang) + "\" lang=\"" + html.EscapeHTMLStr(lang) + "\">\n")
+	buf.WriteString("<head>\n<meta charset=\"UTF-8\" />\n<title>" + html.EscapeHTMLStr(title) + "</title>\n</head>\n<body>\n")
+	buf.Write(bytes.TrimSpace(body))
+	buf.WriteString("\n</body>\n</html>\n")
+	return buf.Bytes()
+}
+
+// epubNav 根据章节标题生成 EPUB 导航文档。
+func epubNav(title, lang string, chapters []*epubChapter) (ret []byte, err error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("<nav epub:type=\"toc\" id=\"toc\">")
+	if "" != title {
+		buf.WriteString("<h1>" + html.EscapeHTMLStr(title) + "</h1>")
+	}
+	buf.WriteString("<ol>")
+	for _, chapter := range chapters {
+		if 1 > len(chapter.headings) {
+			buf.WriteString("<li><a href=\"" + chapter.href + "\">" + html.EscapeHTMLStr(chapter.title) + "</a></li>")
+			continue
+		}
+		for _, heading := range chapter.headings {
+			epubNavItem(buf, chapter.href, heading)
+		}
+	}
+	buf.WriteString("</ol></nav>")
+
+	body, err := polyglotXHTML(buf.Bytes())
+	if nil != err {
+		return
+	}
+	ret = epubXHTML(title, lang, body)
+	return
+}
+
+func epubNavItem(buf *bytes.Buffer, href string, heading *render.Heading) {
+	buf.WriteString("<li><a href=\"" + href + "#" + html.EscapeHTMLStr(heading.ID) + "\">" + heading.Content + "</a>")
+	if 0 < len(heading.Children) {
+		buf.WriteString("<ol>")
+		for _, child := range heading.Children {
+			epubNavItem(buf, href, child)
+		}
+		buf.WriteString("</ol>")
+	}
+	buf.WriteString("</li>")
+}
+
+// epubPackage 生成 OPF 包文档。
+func epubPackage(meta *EPUBMeta, lang string, chapters []*epubChapter, images []*epubImage) []byte {
+	identifier := meta.Identifier
+	if "" == identifier {
+		identifier = "urn:uuid:" + epubUUID()
+	}
+	modified := meta.Modified
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	buf.WriteString("<package xmlns=\"http://www.idpf.org/2007/opf\" version=\"3.0\" unique-identifier=\"uid\" xml:lang=\"" + html.EscapeHTMLStr(lang) + "\">\n")
+	buf.WriteString("<metadata xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n")
+	buf.WriteString("<dc:identifier id=\"uid\">" + html.EscapeHTMLStr(identifier) + "</dc:identifier>\n")
+	buf.WriteString("<dc:title>" + html.EscapeHTMLStr(meta.Title) + "</dc:title>\n")
+	buf.WriteString("<dc:language>" + html.EscapeHTMLStr(lang) + "</dc:language>\n")
+	for _, author := range meta.Authors {
+		buf.WriteString("<dc:creator>" + html.EscapeHTMLStr(author) + "</dc:creator>\n")
+	}
+	buf.WriteString("<meta property=\"dcterms:modified\">" + modified.UTC().Format("2006-01-02T15:04:05Z") + "</meta>\n")
+	buf.WriteString("</metadata>\n<manifest>\n")
+	buf.WriteString("<item id=\"nav\" href=\"nav.xhtml\" media-type=\"application/xhtml+xml\" properties=\"nav\"/>\n")
+	for i, chapter := range chapters {
+		buf.WriteString("<item id=\"chapter-" + strconv.Itoa(i+1) + "\" href=\"" + chapter.href + "\" media-type=\"application/xhtml+xml\"")
+		var properties []string
+		if chapter.mathML {
+			properties = append(properties, "mathml")
+		}
+		if chapter.remote {
+			properties = append(properties, "remote-resources")
+		}
+		if 0 < len(properties) {
+			buf.WriteString(" properties=\"" + strings.Join(properties, " ") + "\"")
+		}
+		buf.WriteString("/>\n")
+	}
+	for i, image := range images {
+		buf.WriteString("<item id=\"image-" + strconv.Itoa(i+1) + "\" href=\"" + image.href + "\" media-type=\"" + image.mediaType + "\"/>\n")
+	}
+	buf.WriteString("</manifest>\n<spine>\n")
+	for i := range chapters {
+		buf.WriteString("<itemref idref=\"chapter-" + strconv.Itoa(i+1) + "\"/>\n")
+	}
+	buf.WriteString("</spine>\n</package>\n")
+	return buf.Bytes()
+}
+
+// epubUUID 生成一个随机（第 4 版）UUID。
+func epubUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+const epubContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`
@@ -50,6 +50,9 @@ func (lute *Lute) HTML2Tree(dom string) (ret *parse.Tree) {
 	}
 
 	// 调整 DOM 结构
+	lute.normalizeOfficeDOM(htmlRoot)
+	lute.normalizeCodeDOM(htmlRoot)
+	lute.normalizeTableDOM(htmlRoot)
 	lute.adjustVditorDOM(htmlRoot)
 
 	// 将 HTML 树转换为 Markdown AST
@@ -84,6 +87,14 @@ func (lute *Lute) genASTByDOM(n *html.Node, tree *parse.Tree) {
 		return
 	}
 
+	if lute.convertByHTML2MdRules(n, tree) {
+		return
+	}
+
+	if lute.genMathByDOM(n, tree) {
+		return
+	}
+
 	if "svg" == n.Namespace {
 		return
 	}
@@ -151,6 +162,7 @@ func (lute *Lute) genASTByDOM(n *html.Node, tree *parse.Tree) {
 			language := ""
 			if strings.Contains(class, "-source-") {
 				language = class[strings.LastIndex(class, "-source-")+len("-source-"):]
+				language = normalizeCodeLanguage(strings.Fields(language + " ")[0])
 			} else if strings.Contains(class, "-text-html-basic") {
 				language = "html"
 			}
@@ -236,16 +248,8 @@ func (lute *Lute) genASTByDOM(n *html.Node, tree *parse.Tree) {
 				node.IsFencedCodeBlock = true
 				node.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceOpenMarker, Tokens: util.StrToBytes("```"), CodeBlockFenceLen: 3})
 				node.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceInfoMarker})
-				if atom.Code == firstc.DataAtom || atom.Span == firstc.DataAtom {
-					class := util.DomAttrValue(firstc, "class")
-					if !strings.Contains(class, "language-") {
-						class = util.DomAttrValue(n, "class")
-					}
-					if strings.Contains(class, "language-") {
-						language := class[strings.Index(class, "language-")+len("language-"):]
-						language = strings.Split(language, " ")[0]
-						node.LastChild.CodeBlockInfo = []byte(language)
-					}
+				if language := domCodeLanguage(n); "" != language {
+					node.LastChild.CodeBlockInfo = []byte(language)
 				}
 
 				if atom.Code == n.FirstChild.DataAtom {
@@ -467,6 +471,13 @@ func (lute *Lute) genASTByDOM(n *html.Node, tree *parse.Tree) {
 		tree.Context.Tip = node
 		defer tree.Context.ParentTip()
 	case atom.Table:
+		if lute.tableAsHTML(n) {
+			node.Type = ast.NodeHTMLBlock
+			node.Tokens = tableHTML(n)
+			tree.Context.Tip.AppendChild(node)
+			return
+		}
+
 		node.Type = ast.NodeTable
 		var tableAligns []int
 		if nil != n.FirstChild && nil != n.FirstChild.FirstChild && nil != n.FirstChild.FirstChild.FirstChild {
@@ -526,6 +537,9 @@ func (lute *Lute) genASTByDOM(n *html.Node, tree *parse.Tree) {
 		}
 		node.TableCellAlign = tableAlign
 		tree.Context.Tip.AppendChild(node)
+		if HTML2MdTableIAL == lute.HTML2MdTableMode {
+			parse.SetSpanIAL(node, n)
+		}
 		tree.Context.Tip = node
 		defer tree.Context.ParentTip()
 	case atom.Colgroup, atom.Col:
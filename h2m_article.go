@@ -0,0 +1,629 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/html/atom"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/render"
+	"github.com/88250/lute/util"
+)
+
+// articleMetadata 描述了网页文章的元数据。
+type articleMetadata struct {
+	Title  string // 标题
+	Byline string // 作者
+	Date   string // 发布时间
+}
+
+// HTML2MarkdownArticle 提取网页 htmlStr 中的正文并转换为 Markdown。
+//
+// 正文通过类似 Readability 的评分算法识别，导航、页脚、分享按钮等内容会被去掉。标题、作者和发布时间从 <meta>、
+// Open Graph 和 JSON-LD 中提取并写入 YAML Front Matter，正文中的相对链接和图片地址基于 baseURL 解析。
+func (lute *Lute) HTML2MarkdownArticle(htmlStr, baseURL string) (markdown string, err error) {
+	doc, err := html.Parse(strings.NewReader(htmlStr))
+	if nil != err {
+		return
+	}
+
+	meta := articleMeta(doc)
+	content := articleContent(doc, meta)
+	lute.resolveArticleURLs(content, articleBaseURL(doc, baseURL))
+
+	buf := &bytes.Buffer{}
+	for c := content.FirstChild; nil != c; c = c.NextSibling {
+		if err = html.Render(buf, c); nil != err {
+			return
+		}
+	}
+	tree := lute.HTML2Tree(buf.String())
+	if nil == tree {
+		tree = &parse.Tree{Name: "", Root: &ast.Node{Type: ast.NodeDocument}, Context: &parse.Context{ParseOption: lute.ParseOptions}}
+	}
+	if frontMatter := yamlFrontMatter([][2]string{{"title", meta.Title}, {"author", meta.Byline}, {"date", meta.Date}}); nil != frontMatter {
+		tree.Root.PrependChild(frontMatter)
+	}
+	markdown = lute.formatHTML2MdTree(tree)
+	return
+}
+
+// formatHTML2MdTree 使用 HTML2Md 渲染器将 tree 格式化为 Markdown。
+func (lute *Lute) formatHTML2MdTree(tree *parse.Tree) string {
+	renderer := render.NewFormatRenderer(tree, lute.RenderOptions)
+	for nodeType, rendererFunc := range lute.HTML2MdRendererFuncs {
+		renderer.ExtRendererFuncs[nodeType] = rendererFunc
+	}
+	return util.BytesToStr(renderer.Render())
+}
+
+// yamlFrontMatter 使用字段 fields 生成 YAML Front Matter 节点，值为空的字段会被忽略，全部为空时返回 nil。
+func yamlFrontMatter(fields [][2]string) *ast.Node {
+	buf := &bytes.Buffer{}
+	for _, field := range fields {
+		if "" != field[1] {
+			buf.WriteString(field[0] + ": " + strconv.Quote(field[1]) + "\n")
+		}
+	}
+	if 1 > buf.Len() {
+		return nil
+	}
+
+	content := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	ret := &ast.Node{Type: ast.NodeYamlFrontMatter, Tokens: content}
+	ret.AppendChild(&ast.Node{Type: ast.NodeYamlFrontMatterOpenMarker})
+	ret.AppendChild(&ast.Node{Type: ast.NodeYamlFrontMatterContent, Tokens: content})
+	ret.AppendChild(&ast.Node{Type: ast.NodeYamlFrontMatterCloseMarker})
+	return ret
+}
+
+// articleMeta 从 doc 的 JSON-LD、Open Graph、<meta> 和 <title> 中提取文章元数据，前面的来源优先。
+func articleMeta(doc *html.Node) (ret *articleMetadata) {
+	ret = &articleMetadata{}
+	metas := map[string]string{}
+	var title, timeDatetime string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.Meta:
+			key := util.DomAttrValue(n, "property")
+			if "" == key {
+				key = util.DomAttrValue(n, "name")
+			}
+			if "" == key {
+				key = util.DomAttrValue(n, "itemprop")
+			}
+			key = strings.ToLower(strings.TrimSpace(key))
+			if value := strings.TrimSpace(util.DomAttrValue(n, "content")); "" != key && "" != value {
+				if _, ok := metas[key]; !ok {
+					metas[key] = value
+				}
+			}
+		case atom.Title:
+			if "" == title {
+				title = articleText(n)
+			}
+		case atom.Time:
+			if "" == timeDatetime && ("datePublished" == util.DomAttrValue(n, "itemprop") || hasAttr(n, "pubdate")) {
+				timeDatetime = util.DomAttrValue(n, "datetime")
+			}
+		case atom.Script:
+			if "application/ld+json" == strings.ToLower(util.DomAttrValue(n, "type")) {
+				jsonLDArticleMeta(util.DomText(n), ret)
+			}
+		}
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	pick := func(value *string, keys ...string) {
+		for _, key := range keys {
+			if "" != *value {
+				return
+			}
+			*value = metas[key]
+		}
+	}
+	pick(&ret.Title, "og:title", "twitter:title", "dc.title", "headline", "title")
+	pick(&ret.Byline, "author", "article:author", "dc.creator", "byl", "twitter:creator")
+	pick(&ret.Date, "article:published_time", "datepublished", "publishdate", "pubdate", "date", "dc.date", "dcterms.created")
+	if "" == ret.Title {
+		ret.Title = title
+		// 去掉 <title> 中的站点名称，比如 “文章标题 - 站点名称”
+		var h1s []string
+		domFind(doc, func(n *html.Node) bool {
+			if atom.H1 == n.DataAtom {
+				h1s = append(h1s, articleText(n))
+			}
+			return false
+		})
+		if 1 == len(h1s) && "" != h1s[0] && strings.HasPrefix(title, h1s[0]) {
+			ret.Title = h1s[0]
+		}
+	}
+	if "" == ret.Date {
+		ret.Date = timeDatetime
+	}
+	if strings.HasPrefix(ret.Byline, "http://") || strings.HasPrefix(ret.Byline, "https://") {
+		ret.Byline = "" // article:author 可能是作者主页地址
+	}
+	ret.Title, ret.Byline, ret.Date = strings.TrimSpace(ret.Title), strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(ret.Byline), "By ")), strings.TrimSpace(ret.Date)
+	return
+}
+
+// jsonLDArticleMeta 从 JSON-LD 数据 data 中提取文章元数据，只填充 meta 中还没有值的字段。
+func jsonLDArticleMeta(data string, meta *articleMetadata) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); nil != err {
+		return
+	}
+
+	var objects []map[string]interface{}
+	var collect func(v interface{})
+	collect = func(v interface{}) {
+		switch value := v.(type) {
+		case []interface{}:
+			for _, item := range value {
+				collect(item)
+			}
+		case map[string]interface{}:
+			objects = append(objects, value)
+			collect(value["@graph"])
+		}
+	}
+	collect(v)
+
+	for _, obj := range objects {
+		if !jsonLDArticle(obj["@type"]) {
+			continue
+		}
+		if title, ok := obj["headline"].(string); ok && "" == meta.Title {
+			meta.Title = title
+		}
+		if title, ok := obj["name"].(string); ok && "" == meta.Title {
+			meta.Title = title
+		}
+		if "" == meta.Byline {
+			meta.Byline = jsonLDNames(obj["author"])
+		}
+		if date, ok := obj["datePublished"].(string); ok && "" == meta.Date {
+			meta.Date = date
+		}
+	}
+}
+
+// jsonLDArticle 判断 JSON-LD 对象类型 typ 是否为文章。
+func jsonLDArticle(typ interface{}) bool {
+	switch value := typ.(type) {
+	case string:
+		return strings.HasSuffix(value, "Article") || strings.HasSuffix(value, "Posting") || "Report" == value
+	case []interface{}:
+		for _, item := range value {
+			if jsonLDArticle(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDNames 返回 JSON-LD 作者 author 的名称，多个作者使用逗号分隔。
+func jsonLDNames(author interface{}) string {
+	switch value := author.(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		name, _ := value["name"].(string)
+		return name
+	case []interface{}:
+		var names []string
+		for _, item := range value {
+			if name := jsonLDNames(item); "" != name {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+var (
+	articleUnlikely = regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote|cookie|consent|share|newsletter|subscribe|promo|navbar|toolbar`)
+	articleMaybe    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	articlePositive = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	articleNegative = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget|cookie|consent`)
+)
+
+// articleContent 返回 doc 中的正文元素，meta 是已经提取的文章元数据。
+func articleContent(doc *html.Node, meta *articleMetadata) *html.Node {
+	body := domFind(doc, func(n *html.Node) bool { return atom.Body == n.DataAtom })
+	if nil == body {
+		body = doc
+	}
+	removeUnlikelyArticleNodes(body)
+
+	scores := map[*html.Node]float64{}
+	var candidates []*html.Node
+	addScore := func(n *html.Node, score float64) {
+		if nil == n || html.ElementNode != n.Type {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = articleBaseScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.P, atom.Pre, atom.Td, atom.Blockquote, atom.Section:
+			text := articleText(n)
+			if length := utf8.RuneCountInString(text); 25 <= length {
+				score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，")) + math.Min(float64(length/100), 3)
+				for level, ancestor := 0, n.Parent; 5 > level && nil != ancestor && atom.Html != ancestor.DataAtom; level, ancestor = level+1, ancestor.Parent {
+					switch level {
+					case 0:
+						addScore(ancestor, score)
+					case 1:
+						addScore(ancestor, score/2)
+					default:
+						addScore(ancestor, score/float64(level*3))
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(body)
+
+	var top *html.Node
+	for _, candidate := range candidates {
+		scores[candidate] *= 1 - articleLinkDensity(candidate)
+		if nil == top || scores[top] < scores[candidate] {
+			top = candidate
+		}
+	}
+
+	content := &html.Node{Type: html.ElementNode, DataAtom: atom.Div, Data: atom.Div.String()}
+	if nil == top {
+		for c := body.FirstChild; nil != c; {
+			next := c.NextSibling
+			c.Unlink()
+			content.AppendChild(c)
+			c = next
+		}
+	} else {
+		// 合并与正文元素相邻的高分元素，比如被拆分到多个 div 中的正文
+		threshold := scores[top] * 0.2
+		if 10 > threshold {
+			threshold = 10
+		}
+		var siblings []*html.Node
+		if nil == top.Parent || atom.Body == top.DataAtom {
+			siblings = append(siblings, top)
+		} else {
+			for s := top.Parent.FirstChild; nil != s; s = s.NextSibling {
+				if s == top || articleSibling(s, top, scores, threshold) {
+					siblings = append(siblings, s)
+				}
+			}
+		}
+		for _, s := range siblings {
+			s.Unlink()
+			if s == top || 1 == len(siblings) {
+				for c := s.FirstChild; nil != c; {
+					next := c.NextSibling
+					c.Unlink()
+					content.AppendChild(c)
+					c = next
+				}
+			} else {
+				content.AppendChild(s)
+			}
+		}
+	}
+
+	cleanArticleContent(content, meta)
+	return content
+}
+
+// articleSibling 判断正文元素 top 的兄弟元素 s 是否也属于正文。
+func articleSibling(s, top *html.Node, scores map[*html.Node]float64, threshold float64) bool {
+	if html.ElementNode != s.Type {
+		return false
+	}
+	bonus := 0.0
+	if class := util.DomAttrValue(top, "class"); "" != class && class == util.DomAttrValue(s, "class") {
+		bonus = scores[top] * 0.2
+	}
+	if score, ok := scores[s]; ok && threshold <= score+bonus {
+		return true
+	}
+	if atom.P != s.DataAtom {
+		return false
+	}
+
+	text := articleText(s)
+	length := utf8.RuneCountInString(text)
+	density := articleLinkDensity(s)
+	if 80 < length {
+		return 0.25 > density
+	}
+	last, _ := utf8.DecodeLastRuneInString(text)
+	return 0 < length && 0 == density && strings.ContainsRune(".。!！?？", last)
+}
+
+// removeUnlikelyArticleNodes 去掉 n 下脚本、导航、页眉页脚、隐藏元素以及 class 或者 id 看起来不是正文的元素。
+func removeUnlikelyArticleNodes(n *html.Node) {
+	for c := n.FirstChild; nil != c; {
+		next := c.NextSibling
+		if html.CommentNode == c.Type || html.ElementNode == c.Type && unlikelyArticleNode(c) {
+			c.Unlink()
+		} else {
+			removeUnlikelyArticleNodes(c)
+		}
+		c = next
+	}
+}
+
+func unlikelyArticleNode(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript, atom.Nav, atom.Footer, atom.Aside, atom.Form, atom.Button, atom.Input,
+		atom.Select, atom.Textarea, atom.Dialog, atom.Template, atom.Link, atom.Meta:
+		return true
+	case atom.Body, atom.Article, atom.Main, atom.A, atom.Table, atom.Tbody, atom.Thead, atom.Tr, atom.Td, atom.Th, atom.Pre, atom.Code:
+		return false
+	}
+
+	if hasAttr(n, "hidden") || "true" == util.DomAttrValue(n, "aria-hidden") && nil == domFind(n, isDOMMath) {
+		return true
+	}
+	if style := strings.ReplaceAll(strings.ToLower(util.DomAttrValue(n, "style")), " ", ""); strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+		return true
+	}
+	switch util.DomAttrValue(n, "role") {
+	case "navigation", "banner", "complementary", "contentinfo", "dialog", "alertdialog", "menu", "menubar", "search":
+		return true
+	}
+	if atom.Header == n.DataAtom && nil == domFind(n, func(c *html.Node) bool { return atom.P == c.DataAtom }) {
+		return true // 页眉，文章开头的 header 通常包含段落
+	}
+
+	match := util.DomAttrValue(n, "class") + " " + util.DomAttrValue(n, "id")
+	if !articleUnlikely.MatchString(match) || articleMaybe.MatchString(match) {
+		return false
+	}
+	return nil == domFind(n, func(c *html.Node) bool { return atom.Article == c.DataAtom || atom.Main == c.DataAtom })
+}
+
+// articleBaseScore 返回元素 n 的初始分数。
+func articleBaseScore(n *html.Node) (ret float64) {
+	switch n.DataAtom {
+	case atom.Div, atom.Article, atom.Main, atom.Section:
+		ret = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		ret = 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		ret = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		ret = -5
+	}
+	return ret + articleClassWeight(n)
+}
+
+// articleClassWeight 根据元素 n 的 class 和 id 返回权重。
+func articleClassWeight(n *html.Node) (ret float64) {
+	for _, value := range []string{util.DomAttrValue(n, "class"), util.DomAttrValue(n, "id")} {
+		if "" == value {
+			continue
+		}
+		if articleNegative.MatchString(value) {
+			ret -= 25
+		}
+		if articlePositive.MatchString(value) {
+			ret += 25
+		}
+	}
+	return
+}
+
+// articleLinkDensity 返回元素 n 中链接文本长度占全部文本长度的比例。
+func articleLinkDensity(n *html.Node) float64 {
+	length := utf8.RuneCountInString(articleText(n))
+	if 1 > length {
+		return 0
+	}
+
+	var linkLength int
+	var walk func(c *html.Node)
+	walk = func(c *html.Node) {
+		if atom.A == c.DataAtom {
+			if href := util.DomAttrValue(c, "href"); !strings.HasPrefix(href, "#") {
+				linkLength += utf8.RuneCountInString(articleText(c))
+			}
+			return
+		}
+		for child := c.FirstChild; nil != child; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return float64(linkLength) / float64(length)
+}
+
+var articleByline = regexp.MustCompile(`(?i)byline|author|dateline|writtenby|p-author`)
+
+// cleanArticleContent 去掉正文 content 中与文章标题重复的标题元素、作者信息、链接过多的列表以及分享按钮等残留元素。
+//
+// 元数据 meta 中没有作者时使用正文中的作者信息。
+func cleanArticleContent(content *html.Node, meta *articleMetadata) {
+	var removes []*html.Node
+	titleRemoved := "" == meta.Title
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			if html.ElementNode == c.Type && ("author" == util.DomAttrValue(c, "rel") || articleByline.MatchString(util.DomAttrValue(c, "class")+" "+util.DomAttrValue(c, "id"))) {
+				if byline := articleText(c); 100 > utf8.RuneCountInString(byline) {
+					if "" == meta.Byline {
+						meta.Byline = strings.TrimSpace(strings.TrimPrefix(byline, "By "))
+					}
+					removes = append(removes, c)
+					continue
+				}
+			}
+
+			switch c.DataAtom {
+			case atom.H1, atom.H2:
+				if !titleRemoved && strings.EqualFold(articleText(c), meta.Title) {
+					removes = append(removes, c)
+					titleRemoved = true
+					continue
+				}
+			case atom.Ul, atom.Ol, atom.Div, atom.Section, atom.Table:
+				if articleClutter(c) {
+					removes = append(removes, c)
+					continue
+				}
+			case atom.Svg:
+				if "" == strings.TrimSpace(util.DomText(c)) {
+					removes = append(removes, c) // 图标
+					continue
+				}
+			}
+			walk(c)
+		}
+	}
+	walk(content)
+	for _, n := range removes {
+		n.Unlink()
+	}
+}
+
+// articleClutter 判断正文中的元素 n 是否为分享按钮、相关链接等残留元素。
+func articleClutter(n *html.Node) bool {
+	if nil != domFind(n, func(c *html.Node) bool {
+		return atom.Pre == c.DataAtom || atom.Img == c.DataAtom || atom.Video == c.DataAtom || atom.Iframe == c.DataAtom || isDOMMath(c)
+	}) {
+		return false
+	}
+
+	weight := articleClassWeight(n)
+	if 0 > weight {
+		return true
+	}
+	text := articleText(n)
+	if "" == text {
+		return atom.Table != n.DataAtom
+	}
+
+	density := articleLinkDensity(n)
+	if atom.Ul == n.DataAtom || atom.Ol == n.DataAtom {
+		// 正文中的链接列表通常包含较多说明文字
+		return 25 > weight && 0.5 < density && 200 > utf8.RuneCountInString(text)
+	}
+	return 25 > weight && 0.5 < density
+}
+
+// articleBaseURL 返回解析相对地址使用的基础地址，优先使用页面中 <base> 指定的地址。
+func articleBaseURL(doc *html.Node, baseURL string) *url.URL {
+	ret, err := url.Parse(baseURL)
+	if nil != err {
+		ret = &url.URL{}
+	}
+	if base := domFind(doc, func(n *html.Node) bool { return atom.Base == n.DataAtom && "" != util.DomAttrValue(n, "href") }); nil != base {
+		if href, err := url.Parse(strings.TrimSpace(util.DomAttrValue(base, "href"))); nil == err {
+			ret = ret.ResolveReference(href)
+		}
+	}
+	return ret
+}
+
+// resolveArticleURLs 将正文 content 中链接和图片的相对地址解析为基于 base 的绝对地址，并使用懒加载图片的真实地址。
+func (lute *Lute) resolveArticleURLs(content *html.Node, base *url.URL) {
+	resolve := func(ref string) string {
+		ref = strings.TrimSpace(ref)
+		if "" == ref || strings.HasPrefix(ref, "data:") || "" == base.Host {
+			return ref
+		}
+		if u, err := url.Parse(ref); nil == err {
+			return base.ResolveReference(u).String()
+		}
+		return ref
+	}
+
+	var scripts []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.A:
+			if href := util.DomAttrValue(n, "href"); strings.HasPrefix(strings.ToLower(strings.TrimSpace(href)), "javascript:") {
+				scripts = append(scripts, n)
+			} else if "" != href {
+				lute.setDOMAttrValue(n, "href", resolve(href))
+			}
+		case atom.Img, atom.Video, atom.Audio, atom.Source, atom.Iframe:
+			src := util.DomAttrValue(n, "src")
+			for _, lazy := range []string{"data-src", "data-original", "data-lazy-src", "data-actualsrc"} {
+				if value := util.DomAttrValue(n, lazy); "" != value && ("" == src || strings.HasPrefix(src, "data:")) {
+					src = value
+					break
+				}
+			}
+			if "" == src {
+				if srcset := util.DomAttrValue(n, "srcset"); "" != srcset {
+					src = strings.Fields(strings.Split(srcset, ",")[0] + " ")[0]
+				}
+			}
+			if "" != src {
+				lute.setDOMAttrValue(n, "src", resolve(src))
+			}
+			if poster := util.DomAttrValue(n, "poster"); "" != poster {
+				lute.setDOMAttrValue(n, "poster", resolve(poster))
+			}
+		}
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(content)
+	for _, n := range scripts {
+		unwrapDOM(n)
+	}
+}
+
+// articleText 返回元素 n 的文本，连续的空白合并为一个空格。
+func articleText(n *html.Node) string {
+	return strings.Join(strings.Fields(util.DomText(n)), " ")
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, attr := range n.Attr {
+		if key == attr.Key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/html/atom"
+	"github.com/88250/lute/util"
+)
+
+// normalizeCodeDOM 调整代码高亮库生成的 DOM 结构：
+//   - 将带有行号栏的表格（Pygments、Chroma 等）替换为代码所在的 pre
+//   - 将 GitHub 按行渲染的代码表格转换为 pre
+//   - 去掉 pre 中的行号元素
+func (lute *Lute) normalizeCodeDOM(root *html.Node) {
+	var tables, lineNumbers []*html.Node
+	var walk func(n *html.Node, inPre bool)
+	walk = func(n *html.Node, inPre bool) {
+		switch {
+		case atom.Table == n.DataAtom:
+			tables = append(tables, n)
+		case inPre && html.ElementNode == n.Type && isLineNumberDOM(n):
+			lineNumbers = append(lineNumbers, n)
+			return
+		}
+		inPre = inPre || atom.Pre == n.DataAtom
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			walk(c, inPre)
+		}
+	}
+	walk(root, false)
+
+	for _, table := range tables {
+		if pre := gutterTableCode(table); nil != pre {
+			if language := domCodeLanguage(pre); "" != language {
+				// 移出表格后无法再从外层包裹元素识别语言
+				pre.Attr = append(pre.Attr, &html.Attribute{Key: "data-lang", Val: language})
+			}
+			pre.Unlink()
+			table.InsertBefore(pre)
+			table.Unlink()
+		} else if pre := blobTableCode(table); nil != pre {
+			table.InsertBefore(pre)
+			table.Unlink()
+		}
+	}
+	for _, n := range lineNumbers {
+		n.Unlink()
+	}
+}
+
+// gutterTableCode 返回行号栏表格 table 中的代码 pre，table 不是行号栏表格时返回 nil。
+func gutterTableCode(table *html.Node) *html.Node {
+	var cells []*html.Node
+	domFind(table, func(n *html.Node) bool {
+		if atom.Td == n.DataAtom {
+			cells = append(cells, n)
+		}
+		return false
+	})
+	if 2 != len(cells) || cells[0].Parent != cells[1].Parent {
+		return nil
+	}
+	gutter := strings.TrimSpace(util.DomText(cells[0]))
+	if "" == gutter || strings.Trim(gutter, "0123456789 \t\r\n") != "" {
+		return nil
+	}
+	return domFind(cells[1], func(n *html.Node) bool { return atom.Pre == n.DataAtom })
+}
+
+// blobTableCode 将 GitHub 按行渲染的代码表格 table 转换为 pre，table 不是代码表格时返回 nil。
+func blobTableCode(table *html.Node) *html.Node {
+	var lines []string
+	var ok bool
+	domFind(table, func(n *html.Node) bool {
+		if atom.Td == n.DataAtom && hasClass(n, "blob-code") {
+			lines = append(lines, strings.TrimSuffix(util.DomText(n), "\n"))
+			ok = true
+		}
+		return false
+	})
+	if !ok {
+		return nil
+	}
+
+	pre := &html.Node{Type: html.ElementNode, DataAtom: atom.Pre, Data: atom.Pre.String()}
+	code := &html.Node{Type: html.ElementNode, DataAtom: atom.Code, Data: atom.Code.String()}
+	if language := domCodeLanguage(table); "" != language {
+		code.Attr = append(code.Attr, &html.Attribute{Key: "class", Val: "language-" + language})
+	}
+	code.AppendChild(&html.Node{Type: html.TextNode, Data: strings.Join(lines, "\n")})
+	pre.AppendChild(code)
+	return pre
+}
+
+// isLineNumberDOM 判断 n 是否为代码中的行号元素。
+func isLineNumberDOM(n *html.Node) bool {
+	for _, class := range strings.Fields(util.DomAttrValue(n, "class")) {
+		switch class {
+		case "linenos", "lineno", "ln", "lnt", "line-numbers-rows", "gutter":
+			return true
+		}
+	}
+	return false
+}
+
+// domCodeLanguage 返回代码块 pre 的语言，依次从 code、pre 以及外层包裹元素的 data-lang 属性和 class 中识别。
+func domCodeLanguage(pre *html.Node) string {
+	var candidates []*html.Node
+	if c := pre.FirstChild; nil != c && (atom.Code == c.DataAtom || atom.Span == c.DataAtom) {
+		candidates = append(candidates, c)
+	}
+	candidates = append(candidates, pre)
+	for i, p := 0, pre.Parent; 3 > i && nil != p && html.ElementNode == p.Type; i, p = i+1, p.Parent {
+		candidates = append(candidates, p)
+	}
+
+	for _, n := range candidates {
+		for _, attr := range []string{"data-lang", "data-language"} {
+			if language := normalizeCodeLanguage(util.DomAttrValue(n, attr)); "" != language {
+				return language
+			}
+		}
+		if language := classCodeLanguage(util.DomAttrValue(n, "class")); "" != language {
+			return language
+		}
+	}
+	return ""
+}
+
+var codeLanguageBrush = regexp.MustCompile(`brush:\s*([^;\s]+)`)
+
+// classCodeLanguage 从 class 中识别代码语言，支持 language-go、lang-go、highlight-source-go、highlight-go、
+// brush: go 以及 hljs go、sourceCode go 和 GitHub blob-wrapper type-go 等形式。
+func classCodeLanguage(class string) string {
+	if m := codeLanguageBrush.FindStringSubmatch(class); nil != m {
+		return normalizeCodeLanguage(m[1])
+	}
+
+	fields := strings.Fields(class)
+	for _, field := range fields {
+		for _, prefix := range []string{"language-", "lang-", "highlight-source-", "highlight-"} {
+			if strings.HasPrefix(field, prefix) {
+				if language := normalizeCodeLanguage(field[len(prefix):]); "" != language {
+					return language
+				}
+			}
+		}
+	}
+
+	var hljs, blob bool
+	for _, field := range fields {
+		hljs = hljs || "hljs" == field || "sourceCode" == field
+		blob = blob || "blob-wrapper" == field
+	}
+	for _, field := range fields {
+		if blob && strings.HasPrefix(field, "type-") {
+			return normalizeCodeLanguage(field[len("type-"):])
+		}
+		if hljs && "hljs" != field && "sourceCode" != field && !strings.Contains(field, "-") && strings.ToLower(field) == field {
+			if language := normalizeCodeLanguage(field); "" != language {
+				return language
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeCodeLanguage 规范化代码语言名称，忽略表示不高亮的名称。
+func normalizeCodeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	switch language {
+	case "undefined", "null", "none", "nohighlight", "no-highlight":
+		return ""
+	}
+	return language
+}
@@ -0,0 +1,218 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/html/atom"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/render"
+	"github.com/88250/lute/util"
+)
+
+// genMathByDOM 识别 KaTeX、MathJax、维基百科和 MathML 渲染的公式 n，并转换为行级公式或者公式块。
+//
+// n 不是公式时返回 false；n 是公式渲染的辅助元素（比如 MathJax 的预览）时丢弃并返回 true。
+func (lute *Lute) genMathByDOM(n *html.Node, tree *parse.Tree) bool {
+	if html.ElementNode != n.Type {
+		return false
+	}
+
+	tex, block, ok := domMath(n)
+	if !ok {
+		return false
+	}
+	if "" == tex {
+		return true
+	}
+
+	if block && !tree.Context.Tip.IsContainerBlock() && (ast.NodeParagraph != tree.Context.Tip.Type || !tree.Context.Tip.Parent.IsContainerBlock()) {
+		block = false // 表格、标题等行级上下文中只能使用行级公式
+	}
+	if block {
+		math := &ast.Node{Type: ast.NodeMathBlock}
+		math.AppendChild(&ast.Node{Type: ast.NodeMathBlockOpenMarker, Tokens: parse.MathBlockMarker})
+		math.AppendChild(&ast.Node{Type: ast.NodeMathBlockContent, Tokens: []byte(tex)})
+		math.AppendChild(&ast.Node{Type: ast.NodeMathBlockCloseMarker, Tokens: parse.MathBlockMarker})
+		if ast.NodeParagraph != tree.Context.Tip.Type {
+			tree.Context.Tip.AppendChild(math)
+			return true
+		}
+
+		// 段落中的块级公式：拆分段落，公式后的内容放入新的段落
+		paragraph := tree.Context.Tip
+		paragraph.InsertAfter(math)
+		if last := paragraph.LastChild; nil != last && ast.NodeText == last.Type {
+			if last.Tokens = bytes.TrimRight(last.Tokens, " \t\n"); 1 > len(last.Tokens) {
+				last.Unlink()
+			}
+		}
+		if nil == paragraph.FirstChild {
+			paragraph.Unlink()
+		}
+		tree.Context.Tip = math
+		if next := nextDOMContent(n); nil != next {
+			if html.TextNode == next.Type {
+				next.Data = strings.TrimLeft(next.Data, " \t\n")
+			}
+			paragraph = &ast.Node{Type: ast.NodeParagraph}
+			math.InsertAfter(paragraph)
+			tree.Context.Tip = paragraph
+		}
+		return true
+	}
+
+	if tree.Context.Tip.ParentIs(ast.NodeTableCell) || ast.NodeTableCell == tree.Context.Tip.Type {
+		tex = strings.ReplaceAll(tex, "|", "\\|") // 表格中的竖线需要转义，否则会被当作单元格分隔符
+	}
+	if prev := n.PrevSibling; nil != prev && html.TextNode == prev.Type && "" == strings.TrimSpace(prev.Data) && "" != prev.Data {
+		// 块级节点下的空白文本会被忽略，这里补回公式前的空格，避免相邻公式粘连
+		if last := tree.Context.Tip.LastChild; nil != last && (ast.NodeText != last.Type || !bytes.HasSuffix(last.Tokens, []byte(" "))) {
+			tree.Context.Tip.AppendChild(&ast.Node{Type: ast.NodeText, Tokens: []byte(" ")})
+		}
+	}
+	math := &ast.Node{Type: ast.NodeInlineMath}
+	math.AppendChild(&ast.Node{Type: ast.NodeInlineMathOpenMarker, Tokens: []byte("$")})
+	math.AppendChild(&ast.Node{Type: ast.NodeInlineMathContent, Tokens: []byte(tex)})
+	math.AppendChild(&ast.Node{Type: ast.NodeInlineMathCloseMarker, Tokens: []byte("$")})
+	tree.Context.Tip.AppendChild(math)
+	return true
+}
+
+// domMath 返回公式元素 n 对应的 TeX 公式以及是否为块级公式，n 不是公式时 ok 返回 false。
+func domMath(n *html.Node) (tex string, block bool, ok bool) {
+	class := util.DomAttrValue(n, "class")
+	classes := strings.Fields(class)
+	switch {
+	case hasClass(n, "katex-display"):
+		if math := domFind(n, isDOMMath); nil != math {
+			return render.MathML2TeX(math), true, true
+		}
+	case hasClass(n, "katex"):
+		if math := domFind(n, isDOMMath); nil != math {
+			return render.MathML2TeX(math), "block" == util.DomAttrValue(math, "display"), true
+		}
+	case hasClass(n, "mwe-math-element"): // 维基百科
+		block = nil != domFind(n, func(c *html.Node) bool {
+			return hasClass(c, "mwe-math-mathml-display") || hasClass(c, "mwe-math-fallback-image-display")
+		})
+		if math := domFind(n, isDOMMath); nil != math {
+			return mathTeX(math), block, true
+		}
+		if img := domFind(n, func(c *html.Node) bool { return atom.Img == c.DataAtom }); nil != img {
+			return trimDisplayStyle(util.DomAttrValue(img, "alt")), block, true
+		}
+	case atom.Script == n.DataAtom: // MathJax v2
+		typ := util.DomAttrValue(n, "type")
+		if strings.HasPrefix(typ, "math/tex") {
+			return strings.TrimSpace(util.DomText(n)), strings.Contains(typ, "mode=display"), true
+		}
+	case "mjx-container" == n.Data: // MathJax v3
+		if math := domFind(n, isDOMMath); nil != math {
+			return mathTeX(math), "true" == util.DomAttrValue(n, "display") || "block" == util.DomAttrValue(math, "display"), true
+		}
+	case 0 < len(classes) && strings.HasPrefix(classes[0], "MathJax"): // MathJax v2 渲染结果
+		if "MathJax_Preview" == classes[0] {
+			return "", false, true
+		}
+		block = strings.HasSuffix(classes[0], "_Display")
+		if script := mathJaxScript(n); nil != script {
+			return "", block, true // 使用紧随其后的 script 中的公式
+		}
+		if dataMathML := util.DomAttrValue(n, "data-mathml"); "" != dataMathML {
+			if doc, err := html.Parse(strings.NewReader(dataMathML)); nil == err {
+				if math := domFind(doc, isDOMMath); nil != math {
+					return mathTeX(math), block || "block" == util.DomAttrValue(math, "display"), true
+				}
+			}
+		}
+		if math := domFind(n, isDOMMath); nil != math {
+			return mathTeX(math), block || "block" == util.DomAttrValue(math, "display"), true
+		}
+	case isDOMMath(n):
+		return mathTeX(n), "block" == util.DomAttrValue(n, "display"), true
+	}
+	return "", false, false
+}
+
+// mathTeX 返回 MathML 元素 math 对应的 TeX 公式，优先使用 alttext 属性。
+func mathTeX(math *html.Node) string {
+	if alt := util.DomAttrValue(math, "alttext"); "" != alt {
+		return trimDisplayStyle(alt)
+	}
+	return trimDisplayStyle(render.MathML2TeX(math))
+}
+
+// trimDisplayStyle 去掉维基百科公式外层的 {\displaystyle ...}。
+func trimDisplayStyle(tex string) string {
+	tex = strings.TrimSpace(tex)
+	for _, prefix := range []string{"{\\displaystyle ", "{\\textstyle "} {
+		if strings.HasPrefix(tex, prefix) && strings.HasSuffix(tex, "}") {
+			return strings.TrimSpace(tex[len(prefix) : len(tex)-1])
+		}
+	}
+	return tex
+}
+
+// mathJaxScript 返回 MathJax v2 渲染结果 n 对应的公式源码 script 元素。
+func mathJaxScript(n *html.Node) *html.Node {
+	for next := n.NextSibling; nil != next; next = next.NextSibling {
+		if html.TextNode == next.Type && "" == strings.TrimSpace(next.Data) {
+			continue
+		}
+		if atom.Script == next.DataAtom && strings.HasPrefix(util.DomAttrValue(next, "type"), "math/tex") {
+			return next
+		}
+		if html.ElementNode == next.Type && strings.HasPrefix(util.DomAttrValue(next, "class"), "MathJax") {
+			continue
+		}
+		break
+	}
+
+	// MathJax_Display 等包裹元素中的 script 位于包裹元素之后
+	if nil != n.Parent && n == n.Parent.LastChild && strings.HasPrefix(util.DomAttrValue(n.Parent, "class"), "MathJax") {
+		return mathJaxScript(n.Parent)
+	}
+	return nil
+}
+
+// nextDOMContent 返回段落中位于 n 之后的第一个非空白兄弟节点。
+func nextDOMContent(n *html.Node) *html.Node {
+	for ; nil != n && atom.P != n.DataAtom && atom.Div != n.DataAtom && atom.Li != n.DataAtom; n = n.Parent {
+		for next := n.NextSibling; nil != next; next = next.NextSibling {
+			if "" != strings.TrimSpace(util.DomText(next)) || atom.Img == next.DataAtom {
+				return next
+			}
+		}
+	}
+	return nil
+}
+
+func isDOMMath(n *html.Node) bool {
+	return html.ElementNode == n.Type && "math" == n.Data
+}
+
+// domFind 深度优先查找 n 下第一个满足条件的节点（包括 n 本身）。
+func domFind(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if match(n) {
+		return n
+	}
+	for c := n.FirstChild; nil != c; c = c.NextSibling {
+		if ret := domFind(c, match); nil != ret {
+			return ret
+		}
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/html/atom"
+	"github.com/88250/lute/util"
+)
+
+// normalizeOfficeDOM 将从 Microsoft Word 和 Google Docs 复制的 HTML 调整为语义化的结构：
+//   - 去掉 <o:p>、样式表和列表符号等排版元素
+//   - 将 MsoListParagraph 段落转换为嵌套列表
+//   - 将 MsoHeading、MsoTitle 段落转换为标题
+//   - 将行内样式中的加粗、斜体、删除线和上下标转换为对应的元素
+//   - 去掉表格单元格中的段落包裹
+func (lute *Lute) normalizeOfficeDOM(root *html.Node) {
+	word, gdocs := officeDOM(root)
+	if !word && !gdocs {
+		return
+	}
+
+	var cruft, wrappers, spans, headings, cells []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			walk(c)
+		}
+		if html.ElementNode != n.Type {
+			return
+		}
+
+		style := util.DomAttrValue(n, "style")
+		switch {
+		case "o:p" == n.Data || atom.Style == n.DataAtom || atom.Meta == n.DataAtom || atom.Link == n.DataAtom || "xml" == n.Data:
+			cruft = append(cruft, n)
+		case strings.Contains(strings.ToLower(style), "mso-list:ignore"):
+			// 列表符号在转换列表时去掉
+		case atom.B == n.DataAtom && strings.HasPrefix(util.DomAttrValue(n, "id"), "docs-internal-guid"):
+			wrappers = append(wrappers, n)
+		case atom.Span == n.DataAtom:
+			spans = append(spans, n)
+		case atom.P == n.DataAtom:
+			if 0 < officeHeadingLevel(n) {
+				headings = append(headings, n)
+			}
+		case atom.Td == n.DataAtom || atom.Th == n.DataAtom:
+			cells = append(cells, n)
+		}
+	}
+	walk(root)
+
+	for _, n := range cruft {
+		n.Unlink()
+	}
+	for _, n := range wrappers {
+		unwrapDOM(n)
+	}
+	for _, span := range spans {
+		officeSpanSemantics(span)
+	}
+	for _, p := range headings {
+		level := officeHeadingLevel(p)
+		p.DataAtom = atom.Lookup([]byte("h" + strconv.Itoa(level)))
+		p.Data = p.DataAtom.String()
+	}
+	if word {
+		officeLists(root)
+	}
+	for _, cell := range cells {
+		officeCell(cell)
+	}
+	removeEmptyOfficeParagraphs(root)
+}
+
+// officeDOM 判断 root 是否为 Microsoft Word 或者 Google Docs 生成的 HTML。
+func officeDOM(root *html.Node) (word, gdocs bool) {
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if word && gdocs {
+			return
+		}
+		if html.ElementNode == n.Type {
+			if "o:p" == n.Data || strings.HasPrefix(util.DomAttrValue(n, "class"), "Mso") || strings.Contains(util.DomAttrValue(n, "style"), "mso-") {
+				word = true
+			}
+			if strings.HasPrefix(util.DomAttrValue(n, "id"), "docs-internal-guid") {
+				gdocs = true
+			}
+		}
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return
+}
+
+// officeHeadingLevel 返回 Word 标题段落 p 的级别，p 不是标题时返回 0。
+func officeHeadingLevel(p *html.Node) int {
+	for _, class := range strings.Fields(util.DomAttrValue(p, "class")) {
+		switch {
+		case "MsoTitle" == class:
+			return 1
+		case "MsoSubtitle" == class:
+			return 2
+		case strings.HasPrefix(class, "MsoHeading"):
+			if level, err := strconv.Atoi(strings.TrimPrefix(class, "MsoHeading")); nil == err && 0 < level {
+				if 6 < level {
+					level = 6
+				}
+				return level
+			}
+		}
+	}
+	return 0
+}
+
+// officeSpanSemantics 将 span 行内样式表示的格式转换为对应的元素，并去掉 Word 的 class。
+func officeSpanSemantics(span *html.Node) {
+	styles := map[string]string{}
+	for _, declaration := range strings.Split(util.DomAttrValue(span, "style"), ";") {
+		if idx := strings.Index(declaration, ":"); 0 < idx {
+			styles[strings.ToLower(strings.TrimSpace(declaration[:idx]))] = strings.ToLower(strings.TrimSpace(declaration[idx+1:]))
+		}
+	}
+
+	var tags []atom.Atom
+	if weight := styles["font-weight"]; "bold" == weight || "bolder" == weight {
+		tags = append(tags, atom.Strong)
+	} else if w, err := strconv.Atoi(weight); nil == err && 600 <= w {
+		tags = append(tags, atom.Strong)
+	}
+	if "italic" == styles["font-style"] || "oblique" == styles["font-style"] {
+		tags = append(tags, atom.Em)
+	}
+	if strings.Contains(styles["text-decoration"], "line-through") || strings.Contains(styles["text-decoration-line"], "line-through") {
+		tags = append(tags, atom.S)
+	}
+	switch styles["vertical-align"] {
+	case "super":
+		tags = append(tags, atom.Sup)
+	case "sub":
+		tags = append(tags, atom.Sub)
+	}
+
+	var attrs []*html.Attribute
+	for _, attr := range span.Attr {
+		if "class" == attr.Key {
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+	span.Attr = attrs // Word 的 SpellE、GramE 等 class 会被当作强调处理
+
+	if 1 > len(tags) || "" == strings.TrimSpace(util.DomText(span)) {
+		return
+	}
+	span.DataAtom, span.Data, span.Attr = tags[0], tags[0].String(), nil
+	parent := span
+	for _, tag := range tags[1:] {
+		wrapper := &html.Node{Type: html.ElementNode, DataAtom: tag, Data: tag.String()}
+		for c := parent.FirstChild; nil != c; {
+			next := c.NextSibling
+			c.Unlink()
+			wrapper.AppendChild(c)
+			c = next
+		}
+		parent.AppendChild(wrapper)
+		parent = wrapper
+	}
+}
+
+var officeListStyle = regexp.MustCompile(`(?i)mso-list:\s*(l\d+)\s+level(\d+)`)
+var officeOrderedMarker = regexp.MustCompile(`^(\d+|[a-zA-Z]|[ivxlcdmIVXLCDM]+)[.)]$`)
+
+// officeLists 将 Word 使用段落模拟的列表转换为嵌套的 ul、ol 列表。
+func officeLists(n *html.Node) {
+	type list struct {
+		node  *html.Node
+		level int
+	}
+	var stack []list
+	var listID string
+	for c := n.FirstChild; nil != c; {
+		next := c.NextSibling
+		if html.TextNode == c.Type && "" == strings.TrimSpace(c.Data) && 0 < len(stack) {
+			c.Unlink() // 列表项之间的空白
+			c = next
+			continue
+		}
+
+		m := officeListStyle.FindStringSubmatch(util.DomAttrValue(c, "style"))
+		if atom.P != c.DataAtom || nil == m {
+			stack = nil
+			officeLists(c)
+			c = next
+			continue
+		}
+
+		if listID != m[1] {
+			stack = nil // 相邻的另一个列表
+			listID = m[1]
+		}
+		level, _ := strconv.Atoi(m[2])
+		marker := officeListMarker(c)
+		ordered := officeOrderedMarker.MatchString(marker)
+		for 0 < len(stack) && stack[len(stack)-1].level > level {
+			stack = stack[:len(stack)-1]
+		}
+		if 0 == len(stack) || stack[len(stack)-1].level < level {
+			tag := atom.Ul
+			if ordered {
+				tag = atom.Ol
+			}
+			l := &html.Node{Type: html.ElementNode, DataAtom: tag, Data: tag.String()}
+			if start := strings.TrimRight(marker, ".)"); ordered && "1" != start {
+				if _, err := strconv.Atoi(start); nil == err {
+					l.Attr = append(l.Attr, &html.Attribute{Key: "start", Val: start})
+				}
+			}
+			if 0 == len(stack) {
+				c.InsertBefore(l)
+			} else {
+				stack[len(stack)-1].node.LastChild.AppendChild(l)
+			}
+			stack = append(stack, list{node: l, level: level})
+		}
+
+		li := &html.Node{Type: html.ElementNode, DataAtom: atom.Li, Data: atom.Li.String()}
+		for child := c.FirstChild; nil != child; {
+			nextChild := child.NextSibling
+			child.Unlink()
+			li.AppendChild(child)
+			child = nextChild
+		}
+		stack[len(stack)-1].node.AppendChild(li)
+		c.Unlink()
+		c = next
+	}
+}
+
+// officeListMarker 去掉 Word 列表段落 p 中的列表符号并返回符号文本。
+func officeListMarker(p *html.Node) (marker string) {
+	var ignore *html.Node
+	var find func(n *html.Node)
+	find = func(n *html.Node) {
+		for c := n.FirstChild; nil != c && nil == ignore; c = c.NextSibling {
+			if strings.Contains(strings.ToLower(util.DomAttrValue(c, "style")), "mso-list:ignore") {
+				ignore = c
+				return
+			}
+			find(c)
+		}
+	}
+	find(p)
+	if nil == ignore {
+		return
+	}
+
+	marker = strings.TrimSpace(strings.ReplaceAll(util.DomText(ignore), "\u00a0", " "))
+	for ignore.Parent != p && strings.TrimSpace(strings.ReplaceAll(util.DomText(ignore.Parent), "\u00a0", " ")) == marker {
+		ignore = ignore.Parent // 包裹列表符号的字体 span
+	}
+	ignore.Unlink()
+	if first := p.FirstChild; nil != first && html.TextNode == first.Type {
+		first.Data = strings.TrimLeft(first.Data, " \u00a0")
+	}
+	return
+}
+
+// officeCell 去掉表格单元格 cell 中的段落包裹，多个段落之间使用换行分隔。
+func officeCell(cell *html.Node) {
+	for c := cell.FirstChild; nil != c; {
+		next := c.NextSibling
+		if atom.P == c.DataAtom || atom.Div == c.DataAtom {
+			if nil != c.PrevSibling && "" != strings.TrimSpace(util.DomText(c)) {
+				c.InsertBefore(&html.Node{Type: html.ElementNode, DataAtom: atom.Br, Data: atom.Br.String()})
+			}
+			unwrapDOM(c)
+		}
+		c = next
+	}
+	// 保留 colspan、rowspan 等结构属性，只去掉样式相关属性
+	attrs := cell.Attr[:0]
+	for _, attr := range cell.Attr {
+		if "style" != attr.Key && "class" != attr.Key && "width" != attr.Key {
+			attrs = append(attrs, attr)
+		}
+	}
+	cell.Attr = attrs
+}
+
+// removeEmptyOfficeParagraphs 去掉仅包含空白的段落。
+func removeEmptyOfficeParagraphs(n *html.Node) {
+	for c := n.FirstChild; nil != c; {
+		next := c.NextSibling
+		if atom.P == c.DataAtom && "" == strings.TrimSpace(strings.ReplaceAll(util.DomText(c), "\u00a0", " ")) && nil == domFind(c, func(img *html.Node) bool { return atom.Img == img.DataAtom }) {
+			c.Unlink()
+		} else {
+			removeEmptyOfficeParagraphs(c)
+		}
+		c = next
+	}
+}
+
+// unwrapDOM 使用 n 的子节点替换 n。
+func unwrapDOM(n *html.Node) {
+	for c := n.FirstChild; nil != c; {
+		next := c.NextSibling
+		n.InsertBefore(c)
+		c = next
+	}
+	n.Unlink()
+}
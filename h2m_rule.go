@@ -0,0 +1,109 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+// HTML2MdRule 描述了一条 HTML 转换 Markdown 的规则，规则在内置的元素转换逻辑之前按顺序匹配。
+type HTML2MdRule interface {
+	// Match 判断是否由该规则转换 DOM 节点 n。
+	Match(n *html.Node) bool
+
+	// Convert 将 DOM 节点 n 转换为 AST 节点并添加到 tree.Context.Tip 下。
+	// 调用 convertChildren 会继续转换 n 的子节点，转换结果添加到调用时的 tree.Context.Tip 下。
+	Convert(n *html.Node, tree *parse.Tree, convertChildren func())
+}
+
+// HTML2MdMatcher 描述了 DOM 元素的匹配条件，为空的条件不参与匹配。
+type HTML2MdMatcher struct {
+	Tag   string                  // 标签名，比如 div
+	Class string                  // 元素需要包含的 class
+	Attr  func(n *html.Node) bool // 属性判断
+}
+
+// Match 判断 DOM 节点 n 是否满足匹配条件。
+func (m *HTML2MdMatcher) Match(n *html.Node) bool {
+	if html.ElementNode != n.Type {
+		return false
+	}
+	if "" != m.Tag && !strings.EqualFold(m.Tag, n.Data) {
+		return false
+	}
+	if "" != m.Class && !hasClass(n, m.Class) {
+		return false
+	}
+	if nil != m.Attr && !m.Attr(n) {
+		return false
+	}
+	return true
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(util.DomAttrValue(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// HTML2MdFuncRule 使用匹配条件 HTML2MdMatcher 和转换函数 Handler 实现 HTML2MdRule。
+type HTML2MdFuncRule struct {
+	*HTML2MdMatcher
+	Handler func(n *html.Node, tree *parse.Tree, convertChildren func())
+}
+
+// Convert 调用转换函数 Handler。
+func (r *HTML2MdFuncRule) Convert(n *html.Node, tree *parse.Tree, convertChildren func()) {
+	r.Handler(n, tree, convertChildren)
+}
+
+// NewHTML2MdKeepRule 创建一条将匹配的元素保留为 HTML 的规则。
+func NewHTML2MdKeepRule(matcher *HTML2MdMatcher) HTML2MdRule {
+	return &HTML2MdFuncRule{HTML2MdMatcher: matcher, Handler: func(n *html.Node, tree *parse.Tree, convertChildren func()) {
+		tokens := bytes.TrimSpace(util.DomHTML(n))
+		if tree.Context.Tip.IsContainerBlock() {
+			tree.Context.Tip.AppendChild(&ast.Node{Type: ast.NodeHTMLBlock, Tokens: tokens})
+			return
+		}
+		tree.Context.Tip.AppendChild(&ast.Node{Type: ast.NodeInlineHTML, Tokens: tokens})
+	}}
+}
+
+// NewHTML2MdRemoveRule 创建一条丢弃匹配的元素（包括其子节点）的规则。
+func NewHTML2MdRemoveRule(matcher *HTML2MdMatcher) HTML2MdRule {
+	return &HTML2MdFuncRule{HTML2MdMatcher: matcher, Handler: func(n *html.Node, tree *parse.Tree, convertChildren func()) {}}
+}
+
+// convertByHTML2MdRules 使用用户自定义的规则转换 DOM 节点 n，没有规则匹配时返回 false。
+func (lute *Lute) convertByHTML2MdRules(n *html.Node, tree *parse.Tree) bool {
+	for _, rule := range lute.HTML2MdRules {
+		if !rule.Match(n) {
+			continue
+		}
+
+		rule.Convert(n, tree, func() {
+			for c := n.FirstChild; nil != c; c = c.NextSibling {
+				lute.genASTByDOM(c, tree)
+			}
+		})
+		return true
+	}
+	return false
+}
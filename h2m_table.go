@@ -0,0 +1,345 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/html/atom"
+	"github.com/88250/lute/render"
+	"github.com/88250/lute/util"
+)
+
+// HTML2Md 复杂表格（包含合并单元格或者单元格中包含块级内容）的转换方式。
+const (
+	HTML2MdTableExpand = iota // 将合并单元格展开为空单元格（默认）
+	HTML2MdTableIAL           // 使用 Kramdown span IAL 保留合并单元格，和 Protyle 表格一致，需要启用 KramdownSpanIAL 渲染选项
+	HTML2MdTableHTML          // 输出为 HTML 块
+)
+
+// normalizeTableDOM 将表格调整为 GFM 表格能够表示的结构：
+//   - 表头只保留第一行，其余行和 tfoot 中的行移到 tbody 中
+//   - 将 caption 移到表格前作为段落
+//   - 将表体中的行标题 th 转换为加粗的 td
+//   - 单元格中的段落和列表使用换行分隔
+//   - 按照转换方式展开合并单元格
+//
+// 需要输出为 HTML 块的表格保持不变。
+func (lute *Lute) normalizeTableDOM(root *html.Node) {
+	var tables []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if atom.Table == n.DataAtom {
+			tables = append(tables, n)
+			return
+		}
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	for _, table := range tables {
+		if lute.tableAsHTML(table) {
+			continue
+		}
+
+		rows := tableRows(table)
+		for c := table.FirstChild; nil != c; {
+			next := c.NextSibling
+			if atom.Caption == c.DataAtom {
+				if "" != strings.TrimSpace(util.DomText(c)) {
+					c.DataAtom, c.Data, c.Attr = atom.P, atom.P.String(), nil
+					flattenTableCell(c)
+					table.InsertBefore(c)
+				} else {
+					c.Unlink()
+				}
+			} else {
+				c.Unlink()
+			}
+			c = next
+		}
+		if 1 > len(rows) {
+			continue
+		}
+
+		for _, tr := range rows {
+			tr.Unlink()
+		}
+		thead := &html.Node{Type: html.ElementNode, DataAtom: atom.Thead, Data: atom.Thead.String()}
+		thead.AppendChild(rows[0])
+		table.AppendChild(thead)
+		if 1 < len(rows) {
+			tbody := &html.Node{Type: html.ElementNode, DataAtom: atom.Tbody, Data: atom.Tbody.String()}
+			for _, tr := range rows[1:] {
+				tbody.AppendChild(tr)
+			}
+			table.AppendChild(tbody)
+		}
+
+		for i, tr := range rows {
+			for _, cell := range tableRowCells(tr) {
+				flattenTableCell(cell)
+				if 0 < i && atom.Th == cell.DataAtom {
+					// 行标题
+					cell.DataAtom, cell.Data = atom.Td, atom.Td.String()
+					if "" != strings.TrimSpace(util.DomText(cell)) {
+						strong := &html.Node{Type: html.ElementNode, DataAtom: atom.Strong, Data: atom.Strong.String()}
+						for c := cell.FirstChild; nil != c; {
+							next := c.NextSibling
+							c.Unlink()
+							strong.AppendChild(c)
+							c = next
+						}
+						cell.AppendChild(strong)
+					}
+				}
+			}
+		}
+		expandTableSpans(rows, HTML2MdTableIAL == lute.HTML2MdTableMode)
+	}
+}
+
+// tableAsHTML 判断是否需要将表格 table 输出为 HTML 块。
+//
+// 单元格中包含代码块或者嵌套表格时总是输出为 HTML 块，否则这些内容会破坏 GFM 表格的结构。
+func (lute *Lute) tableAsHTML(table *html.Node) bool {
+	var merged bool
+	for _, tr := range tableRows(table) {
+		for _, cell := range tableRowCells(tr) {
+			complexity := tableCellComplexity(cell)
+			if 1 < complexity {
+				return true
+			}
+			merged = merged || 0 < complexity || 1 < tableSpan(cell, "colspan") || 1 < tableSpan(cell, "rowspan")
+		}
+	}
+	return HTML2MdTableHTML == lute.HTML2MdTableMode && merged
+}
+
+// tableHTML 返回表格 table 清理后的 HTML，只保留合并单元格和对齐相关的属性。
+func tableHTML(table *html.Node) []byte {
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if html.ElementNode == n.Type {
+			var attrs []*html.Attribute
+			for _, attr := range n.Attr {
+				switch attr.Key {
+				case "colspan", "rowspan", "scope", "align", "href", "src", "alt", "title", "start":
+					attrs = append(attrs, attr)
+				}
+			}
+			n.Attr = attrs
+		}
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(table)
+	return []byte(render.Sanitize(string(util.DomHTML(table))))
+}
+
+// tableRows 按照 thead、tbody、tfoot 的顺序返回表格 table 中的行。
+func tableRows(table *html.Node) (ret []*html.Node) {
+	var head, body, foot []*html.Node
+	for c := table.FirstChild; nil != c; c = c.NextSibling {
+		switch c.DataAtom {
+		case atom.Tr:
+			body = append(body, c)
+		case atom.Thead, atom.Tbody, atom.Tfoot:
+			for tr := c.FirstChild; nil != tr; tr = tr.NextSibling {
+				if atom.Tr != tr.DataAtom {
+					continue
+				}
+				switch c.DataAtom {
+				case atom.Thead:
+					head = append(head, tr)
+				case atom.Tbody:
+					body = append(body, tr)
+				default:
+					foot = append(foot, tr)
+				}
+			}
+		}
+	}
+	ret = append(ret, head...)
+	ret = append(ret, body...)
+	return append(ret, foot...)
+}
+
+// tableRowCells 返回行 tr 中的单元格。
+func tableRowCells(tr *html.Node) (ret []*html.Node) {
+	for c := tr.FirstChild; nil != c; c = c.NextSibling {
+		if atom.Th == c.DataAtom || atom.Td == c.DataAtom {
+			ret = append(ret, c)
+		}
+	}
+	return
+}
+
+// tableSpan 返回单元格 cell 的 colspan 或者 rowspan 属性值，无效时返回 1。
+func tableSpan(cell *html.Node, attr string) int {
+	span, err := strconv.Atoi(strings.TrimSpace(util.DomAttrValue(cell, attr)))
+	if nil != err || 1 > span {
+		return 1
+	}
+	if 1000 < span {
+		span = 1000
+	}
+	return span
+}
+
+// tableCellComplexity 返回单元格 cell 中块级内容的复杂程度：0 为行级内容，1 为多个段落、列表或者引述，
+// 2 为代码块或者嵌套表格。
+func tableCellComplexity(cell *html.Node) (ret int) {
+	var paragraphs int
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; nil != c && 2 > ret; c = c.NextSibling {
+			switch c.DataAtom {
+			case atom.Pre, atom.Table:
+				ret = 2
+				return
+			case atom.Ul, atom.Ol, atom.Dl, atom.Blockquote, atom.Hr:
+				ret = 1
+			case atom.P, atom.Div:
+				if paragraphs++; 1 < paragraphs {
+					ret = 1
+				}
+			}
+			walk(c)
+		}
+	}
+	walk(cell)
+	return
+}
+
+// flattenTableCell 去掉单元格 cell 中的段落和列表包裹，段落和列表项之间使用换行分隔。
+func flattenTableCell(cell *html.Node) {
+	num := 0
+	if atom.Ol == cell.DataAtom {
+		if start, err := strconv.Atoi(util.DomAttrValue(cell, "start")); nil == err {
+			num = start - 1
+		}
+	}
+	for c := cell.FirstChild; nil != c; {
+		next := c.NextSibling
+		flattenTableCell(c)
+		switch c.DataAtom {
+		case atom.P, atom.Div, atom.Ul, atom.Ol, atom.Li, atom.Dl, atom.Dt, atom.Dd:
+			if "" != strings.TrimSpace(util.DomText(c)) && contentBeforeDOM(c) {
+				c.InsertBefore(&html.Node{Type: html.ElementNode, DataAtom: atom.Br, Data: atom.Br.String()})
+			}
+			if atom.Li == c.DataAtom {
+				marker := "- "
+				if atom.Ol == cell.DataAtom {
+					num++
+					marker = strconv.Itoa(num) + ". "
+				}
+				// 表格中的文本节点会去掉首尾空白，所以列表符号需要和内容放在同一个文本节点中
+				if text := domFind(c, func(n *html.Node) bool { return html.TextNode == n.Type && "" != strings.TrimSpace(n.Data) }); nil != text {
+					text.Data = marker + strings.TrimLeft(text.Data, " \t\n")
+				}
+			}
+			unwrapDOM(c)
+		}
+		c = next
+	}
+}
+
+// contentBeforeDOM 判断 n 之前（上一个 br 之后）是否存在非空白的兄弟节点。
+func contentBeforeDOM(n *html.Node) bool {
+	for prev := n.PrevSibling; nil != prev; prev = prev.PrevSibling {
+		if atom.Br == prev.DataAtom {
+			return false
+		}
+		if html.TextNode != prev.Type || "" != strings.TrimSpace(prev.Data) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandTableSpans 展开行 rows 中的合并单元格，被合并的位置插入空单元格。
+//
+// ial 为 true 时保留合并单元格的 colspan 和 rowspan 属性，被合并的单元格使用 fn__none 隐藏。
+func expandTableSpans(rows []*html.Node, ial bool) {
+	var carry []int // 每列还需要向下占用的行数
+	for _, tr := range rows {
+		cells := tableRowCells(tr)
+		tag := atom.Td
+		if 0 < len(cells) && atom.Th == cells[0].DataAtom {
+			tag = atom.Th
+		}
+		covered := func() *html.Node {
+			ret := &html.Node{Type: html.ElementNode, DataAtom: tag, Data: tag.String()}
+			if ial {
+				ret.Attr = append(ret.Attr, &html.Attribute{Key: "class", Val: "fn__none"})
+			}
+			return ret
+		}
+
+		var expanded []*html.Node
+		col := 0
+		fill := func(all bool) {
+			last := len(carry) - 1
+			for ; 0 <= last && 1 > carry[last]; last-- {
+			}
+			for ; col < len(carry) && (0 < carry[col] || all && col <= last); col++ {
+				if 0 < carry[col] {
+					expanded = append(expanded, covered())
+					carry[col]--
+				} else {
+					expanded = append(expanded, &html.Node{Type: html.ElementNode, DataAtom: tag, Data: tag.String()})
+				}
+			}
+		}
+		for _, cell := range cells {
+			fill(false)
+			colspan, rowspan := tableSpan(cell, "colspan"), tableSpan(cell, "rowspan")
+			var attrs []*html.Attribute
+			for _, attr := range cell.Attr {
+				if "colspan" != attr.Key && "rowspan" != attr.Key && (!ial || "class" != attr.Key && "style" != attr.Key) {
+					attrs = append(attrs, attr)
+				}
+			}
+			if ial && (1 < colspan || 1 < rowspan) {
+				attrs = append(attrs, &html.Attribute{Key: "colspan", Val: strconv.Itoa(colspan)}, &html.Attribute{Key: "rowspan", Val: strconv.Itoa(rowspan)})
+			}
+			cell.Attr = attrs
+
+			expanded = append(expanded, cell)
+			for i := 0; i < colspan; i++ {
+				if col+i >= len(carry) {
+					carry = append(carry, 0)
+				}
+				carry[col+i] = rowspan - 1
+				if 0 < i {
+					expanded = append(expanded, covered())
+				}
+			}
+			col += colspan
+		}
+		fill(true)
+
+		for c := tr.FirstChild; nil != c; {
+			next := c.NextSibling
+			c.Unlink()
+			c = next
+		}
+		for _, cell := range expanded {
+			tr.AppendChild(cell)
+		}
+	}
+}
@@ -0,0 +1,553 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/render"
+	"github.com/88250/lute/util"
+)
+
+// IpynbAsset 描述了从 Jupyter Notebook 输出中提取的资源文件。
+type IpynbAsset struct {
+	Name string // 文件名，Markdown 中使用该名称引用资源
+	Data []byte // 文件内容
+}
+
+// 单元格和输出的信息保存在块级 IAL 的以下属性中。
+const (
+	ipynbAttrCell           = "custom-jupyter-cell"            // 单元格类型：markdown、code 或 raw
+	ipynbAttrID             = "custom-jupyter-id"              // 单元格 ID
+	ipynbAttrMetadata       = "custom-jupyter-metadata"        // 单元格或者输出的元数据 JSON
+	ipynbAttrExecutionCount = "custom-jupyter-execution-count" // 执行计数
+	ipynbAttrOutput         = "custom-jupyter-output"          // 输出类型：stream、display_data、execute_result 或 error
+	ipynbAttrName           = "custom-jupyter-name"            // 流输出名称：stdout 或 stderr
+	ipynbAttrEName          = "custom-jupyter-ename"           // 错误名称
+	ipynbAttrEValue         = "custom-jupyter-evalue"          // 错误信息
+	ipynbAttrTextPlain      = "custom-jupyter-text-plain"      // 图片和 HTML 输出的纯文本替代内容
+)
+
+// HTML 输出转换为 HTML 块时使用的 div 包裹，导出时去掉。
+const (
+	ipynbHTMLOpen  = "<div>\n"
+	ipynbHTMLClose = "\n</div>"
+)
+
+// ipynbNotebook 描述了 nbformat v4 格式的 Notebook。
+type ipynbNotebook struct {
+	Cells         []*ipynbCell    `json:"cells"`
+	Metadata      json.RawMessage `json:"metadata"`
+	NBFormat      int             `json:"nbformat"`
+	NBFormatMinor int             `json:"nbformat_minor"`
+}
+
+// ipynbCell 描述了 Notebook 中的一个单元格，字段按照 Jupyter 保存时的键顺序排列。
+type ipynbCell struct {
+	Attachments    json.RawMessage `json:"attachments,omitempty"`
+	CellType       string          `json:"cell_type"`
+	ExecutionCount json.RawMessage `json:"execution_count,omitempty"`
+	ID             string          `json:"id,omitempty"`
+	Metadata       json.RawMessage `json:"metadata"`
+	Outputs        *[]*ipynbOutput `json:"outputs,omitempty"`
+	Source         ipynbText       `json:"source"`
+}
+
+// ipynbOutput 描述了代码单元格的一个输出。
+type ipynbOutput struct {
+	Data           map[string]json.RawMessage `json:"data,omitempty"`
+	EName          *string                    `json:"ename,omitempty"`
+	EValue         *string                    `json:"evalue,omitempty"`
+	ExecutionCount json.RawMessage            `json:"execution_count,omitempty"`
+	Metadata       json.RawMessage            `json:"metadata,omitempty"`
+	Name           string                     `json:"name,omitempty"`
+	OutputType     string                     `json:"output_type"`
+	Text           *ipynbText                 `json:"text,omitempty"`
+	Traceback      *[]string                  `json:"traceback,omitempty"`
+}
+
+// ipynbText 描述了 nbformat 中的多行文本，读取时兼容字符串和字符串数组两种形式，保存时使用按行拆分的数组。
+type ipynbText string
+
+func (t *ipynbText) UnmarshalJSON(data []byte) (err error) {
+	var lines []string
+	if nil == json.Unmarshal(data, &lines) {
+		*t = ipynbText(strings.Join(lines, ""))
+		return
+	}
+	var str string
+	err = json.Unmarshal(data, &str)
+	*t = ipynbText(str)
+	return
+}
+
+func (t ipynbText) MarshalJSON() ([]byte, error) {
+	lines := []string{}
+	for str := string(t); "" != str; {
+		i := strings.IndexByte(str, '\n')
+		if 0 > i {
+			lines = append(lines, str)
+			break
+		}
+		lines = append(lines, str[:i+1])
+		str = str[i+1:]
+	}
+	return ipynbMarshal(lines, "")
+}
+
+// Ipynb2Markdown 将 nbformat v4 格式的 Jupyter Notebook 转换为 Markdown。
+//
+// Markdown 单元格按照 Markdown 解析，代码单元格转换为使用内核语言的代码块，文本、HTML 和 PNG 图片输出依次跟在代码块后面，
+// 图片通过 assets 返回，调用方需要将其保存到 Markdown 文件所在的目录。单元格和输出的类型、元数据等信息保存在块级 IAL 中，
+// Notebook 的元数据保存在 YAML Front Matter 中，以便通过 Markdown2Ipynb 还原。
+func (lute *Lute) Ipynb2Markdown(ipynb []byte) (markdown string, assets []*IpynbAsset, err error) {
+	notebook := &ipynbNotebook{}
+	if err = json.Unmarshal(ipynb, notebook); nil != err {
+		return
+	}
+	if 4 != notebook.NBFormat {
+		err = errors.New("unsupported nbformat [" + strconv.Itoa(notebook.NBFormat) + "]")
+		return
+	}
+
+	tree := &parse.Tree{Name: "", Root: &ast.Node{Type: ast.NodeDocument}, Context: &parse.Context{ParseOption: lute.ParseOptions}}
+	jupyter, err := ipynbMarshal(map[string]interface{}{
+		"metadata":       ipynbObject(notebook.Metadata),
+		"nbformat":       notebook.NBFormat,
+		"nbformat_minor": notebook.NBFormatMinor,
+	}, "")
+	if nil != err {
+		return
+	}
+	tree.Root.AppendChild(yamlFrontMatter([][2]string{{"jupyter", string(jupyter)}}))
+
+	language := ipynbLanguage(notebook.Metadata)
+	for i, cell := range notebook.Cells {
+		if nil == cell {
+			continue
+		}
+
+		var blocks []*ast.Node
+		switch cell.CellType {
+		case "markdown":
+			cellTree := parse.Parse("", []byte(cell.Source), lute.ParseOptions)
+			for c := cellTree.Root.FirstChild; nil != c; c = c.Next {
+				blocks = append(blocks, c)
+			}
+		case "code":
+			blocks = append(blocks, ipynbCodeBlock(language, string(cell.Source)))
+		default:
+			blocks = append(blocks, ipynbCodeBlock("", string(cell.Source)))
+		}
+		if 1 > len(blocks) {
+			continue
+		}
+
+		first := blocks[0]
+		first.SetIALAttr("id", ast.NewNodeID())
+		first.SetIALAttr(ipynbAttrCell, cell.CellType)
+		if "" != cell.ID {
+			first.SetIALAttr(ipynbAttrID, cell.ID)
+		}
+		if metadata := ipynbCompact(cell.Metadata); "" != metadata {
+			first.SetIALAttr(ipynbAttrMetadata, metadata)
+		}
+		if count := ipynbCompact(cell.ExecutionCount); "" != count && "null" != count {
+			first.SetIALAttr(ipynbAttrExecutionCount, count)
+		}
+		for _, block := range blocks {
+			tree.Root.AppendChild(block)
+			if block == first {
+				tree.Root.AppendChild(&ast.Node{Type: ast.NodeKramdownBlockIAL, Tokens: parse.IAL2Tokens(block.KramdownIAL)})
+			}
+		}
+
+		if nil == cell.Outputs {
+			continue
+		}
+		for j, output := range *cell.Outputs {
+			if nil == output {
+				continue
+			}
+			block, asset := ipynbOutputBlock(output, "cell"+strconv.Itoa(i+1)+"-output"+strconv.Itoa(j+1))
+			if nil == block {
+				continue
+			}
+			if nil != asset {
+				assets = append(assets, asset)
+			}
+			tree.Root.AppendChild(block)
+			tree.Root.AppendChild(&ast.Node{Type: ast.NodeKramdownBlockIAL, Tokens: parse.IAL2Tokens(block.KramdownIAL)})
+		}
+	}
+
+	options := *lute.RenderOptions
+	options.KramdownBlockIAL = true
+	renderer := render.NewFormatRenderer(tree, &options)
+	markdown = util.BytesToStr(renderer.Render())
+	return
+}
+
+// Markdown2Ipynb 将 Ipynb2Markdown 生成的 Markdown 还原为 nbformat v4 格式的 Jupyter Notebook。
+//
+// 带有单元格 IAL 的块开始一个新的单元格，带有输出 IAL 的块作为前一个代码单元格的输出，其他块归入前一个 Markdown 单元格。
+// 图片输出的内容从 assets 中按照文件名查找。
+func (lute *Lute) Markdown2Ipynb(markdown string, assets []*IpynbAsset) (ipynb []byte, err error) {
+	parseOptions := *lute.ParseOptions
+	parseOptions.KramdownBlockIAL = true
+	parseOptions.YamlFrontMatter = true
+	tree := parse.Parse("", []byte(markdown), &parseOptions)
+
+	notebook := &ipynbNotebook{Cells: []*ipynbCell{}, Metadata: json.RawMessage("{}"), NBFormat: 4, NBFormatMinor: 5}
+	if frontMatter := tree.Root.ChildByType(ast.NodeYamlFrontMatter); nil != frontMatter {
+		if content := frontMatter.ChildByType(ast.NodeYamlFrontMatterContent); nil != content {
+			ipynbFrontMatter(util.BytesToStr(content.Tokens), notebook)
+		}
+		frontMatter.Unlink()
+	}
+
+	renderOptions := *lute.RenderOptions
+	renderOptions.KramdownBlockIAL = false
+	var cell *ipynbCell
+	var cellBlocks []*ast.Node
+	flush := func() {
+		if nil != cell && "markdown" == cell.CellType {
+			cellTree := &parse.Tree{Name: "", Root: &ast.Node{Type: ast.NodeDocument}, Context: &parse.Context{ParseOption: &parseOptions}}
+			for _, block := range cellBlocks {
+				cellTree.Root.AppendChild(block)
+			}
+			source := render.NewFormatRenderer(cellTree, &renderOptions).Render()
+			cell.Source = ipynbText(strings.TrimRight(util.BytesToStr(source), "\n"))
+		}
+		cellBlocks = nil
+	}
+
+	ids := map[string]bool{}
+	var blocks []*ast.Node
+	for c := tree.Root.FirstChild; nil != c; c = c.Next {
+		if ast.NodeKramdownBlockIAL != c.Type {
+			blocks = append(blocks, c)
+		}
+	}
+	for _, block := range blocks {
+		if outputType := block.IALAttr(ipynbAttrOutput); "" != outputType {
+			if nil != cell && "code" == cell.CellType {
+				*cell.Outputs = append(*cell.Outputs, ipynbBlockOutput(block, outputType, assets))
+			}
+			continue
+		}
+
+		cellType := block.IALAttr(ipynbAttrCell)
+		if "" == cellType && nil != cell && "markdown" == cell.CellType {
+			block.Unlink()
+			cellBlocks = append(cellBlocks, block)
+			continue
+		}
+
+		flush()
+		if "" == cellType {
+			cellType = "markdown"
+		}
+		cell = &ipynbCell{CellType: cellType, Metadata: json.RawMessage("{}")}
+		if metadata := block.IALAttr(ipynbAttrMetadata); json.Valid([]byte(metadata)) {
+			cell.Metadata = json.RawMessage(metadata)
+		}
+		if 5 <= notebook.NBFormatMinor {
+			// nbformat 4.5 开始单元格必须有唯一的 ID
+			id := block.IALAttr(ipynbAttrID)
+			if "" == id {
+				id = block.ID
+			}
+			for base, i := id, 1; ids[id]; i++ {
+				id = base + "-" + strconv.Itoa(i)
+			}
+			ids[id] = true
+			cell.ID = id
+		}
+		switch cellType {
+		case "markdown":
+			block.Unlink()
+			cellBlocks = append(cellBlocks, block)
+		case "code":
+			cell.ExecutionCount = json.RawMessage("null")
+			if count, atoiErr := strconv.Atoi(block.IALAttr(ipynbAttrExecutionCount)); nil == atoiErr {
+				cell.ExecutionCount = json.RawMessage(strconv.Itoa(count))
+			}
+			cell.Outputs = &[]*ipynbOutput{}
+			cell.Source = ipynbText(ipynbBlockText(block))
+		default:
+			cell.Source = ipynbText(ipynbBlockText(block))
+		}
+		notebook.Cells = append(notebook.Cells, cell)
+	}
+	flush()
+
+	if ipynb, err = ipynbMarshal(notebook, " "); nil != err {
+		return
+	}
+	ipynb = append(ipynb, '\n')
+	return
+}
+
+// ipynbMarshal 使用 Jupyter 保存时的格式（不转义 HTML 字符）序列化 v，indent 为缩进。
+func ipynbMarshal(v interface{}, indent string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(v); nil != err {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// ipynbCompact 返回 JSON 数据 data 的紧凑形式，data 为空或者是空对象时返回空字符串。
+func ipynbCompact(data json.RawMessage) string {
+	buf := &bytes.Buffer{}
+	if 1 > len(data) || nil != json.Compact(buf, data) || "{}" == buf.String() {
+		return ""
+	}
+	return buf.String()
+}
+
+// ipynbObject 返回 JSON 对象 data，data 为空时返回空对象。
+func ipynbObject(data json.RawMessage) json.RawMessage {
+	if 1 > len(bytes.TrimSpace(data)) {
+		return json.RawMessage("{}")
+	}
+	return data
+}
+
+// ipynbLanguage 从 Notebook 元数据中获取内核语言。
+func ipynbLanguage(metadata json.RawMessage) string {
+	meta := &struct {
+		KernelSpec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	}{}
+	if nil != json.Unmarshal(metadata, meta) {
+		return ""
+	}
+	if "" != meta.KernelSpec.Language {
+		return strings.ToLower(meta.KernelSpec.Language)
+	}
+	return strings.ToLower(meta.LanguageInfo.Name)
+}
+
+// ipynbFrontMatter 从 YAML Front Matter 的 jupyter 字段中读取 Notebook 的元数据和版本。
+func ipynbFrontMatter(yaml string, notebook *ipynbNotebook) {
+	for _, line := range strings.Split(yaml, "\n") {
+		value := strings.TrimPrefix(line, "jupyter:")
+		if value == line {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); nil == err {
+			value = unquoted
+		}
+		jupyter := &ipynbNotebook{}
+		if nil != json.Unmarshal([]byte(value), jupyter) {
+			return
+		}
+		notebook.Metadata = ipynbObject(jupyter.Metadata)
+		if 4 == jupyter.NBFormat {
+			notebook.NBFormatMinor = jupyter.NBFormatMinor
+		}
+		return
+	}
+}
+
+// ipynbCodeBlock 生成内容为 code 的代码块，围栏长度大于内容中最长的连续反引号。
+//
+// 结束围栏前允许最多 3 个空格缩进，所以统计内容中任意位置的连续反引号，而不仅仅是行首的。
+func ipynbCodeBlock(language, code string) *ast.Node {
+	fenceLen := 3
+	for ticks, i := 0, 0; i < len(code); i++ {
+		if '`' != code[i] {
+			ticks = 0
+			continue
+		}
+		if ticks++; ticks >= fenceLen {
+			fenceLen = ticks + 1
+		}
+	}
+	fence := strings.Repeat("`", fenceLen)
+
+	ret := &ast.Node{Type: ast.NodeCodeBlock, IsFencedCodeBlock: true}
+	ret.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceOpenMarker, Tokens: util.StrToBytes(fence), CodeBlockFenceLen: fenceLen})
+	ret.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceInfoMarker, CodeBlockInfo: []byte(language)})
+	ret.AppendChild(&ast.Node{Type: ast.NodeCodeBlockCode, Tokens: []byte(code)})
+	ret.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceCloseMarker, Tokens: util.StrToBytes(fence), CodeBlockFenceLen: fenceLen})
+	return ret
+}
+
+var ipynbANSI = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+
+// ipynbOutputBlock 将输出 output 转换为块，图片输出以 name 为文件名返回资源 asset，不支持的输出返回 nil。
+func ipynbOutputBlock(output *ipynbOutput, name string) (ret *ast.Node, asset *IpynbAsset) {
+	switch output.OutputType {
+	case "stream":
+		if nil == output.Text {
+			return
+		}
+		ret = ipynbCodeBlock("", strings.TrimSuffix(string(*output.Text), "\n"))
+		ret.SetIALAttr(ipynbAttrName, output.Name)
+	case "error":
+		var traceback []string
+		if nil != output.Traceback {
+			traceback = *output.Traceback
+		}
+		ret = ipynbCodeBlock("", ipynbANSI.ReplaceAllString(strings.Join(traceback, "\n"), ""))
+		if nil != output.EName {
+			ret.SetIALAttr(ipynbAttrEName, *output.EName)
+		}
+		if nil != output.EValue {
+			ret.SetIALAttr(ipynbAttrEValue, *output.EValue)
+		}
+	case "display_data", "execute_result":
+		var text ipynbText
+		if data := output.Data["image/png"]; nil != data && nil == json.Unmarshal(data, &text) {
+			png, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(text)), ""))
+			if nil != err {
+				return
+			}
+			asset = &IpynbAsset{Name: name + ".png", Data: png}
+			img := &ast.Node{Type: ast.NodeImage}
+			img.AppendChild(&ast.Node{Type: ast.NodeBang})
+			img.AppendChild(&ast.Node{Type: ast.NodeOpenBracket})
+			img.AppendChild(&ast.Node{Type: ast.NodeCloseBracket})
+			img.AppendChild(&ast.Node{Type: ast.NodeOpenParen})
+			img.AppendChild(&ast.Node{Type: ast.NodeLinkDest, Tokens: util.StrToBytes(asset.Name)})
+			img.AppendChild(&ast.Node{Type: ast.NodeCloseParen})
+			ret = &ast.Node{Type: ast.NodeParagraph}
+			ret.AppendChild(img)
+		} else if data := output.Data["text/html"]; nil != data && nil == json.Unmarshal(data, &text) {
+			// HTML 块在空行处结束，因此需要去掉空行
+			var lines []string
+			for _, line := range strings.Split(string(text), "\n") {
+				if "" != strings.TrimSpace(line) {
+					lines = append(lines, line)
+				}
+			}
+			// 总是使用 div 包裹，否则以行内元素开头的 HTML 会被解析为段落
+			htmlStr := ipynbHTMLOpen + strings.Join(lines, "\n") + ipynbHTMLClose
+			ret = &ast.Node{Type: ast.NodeHTMLBlock, Tokens: []byte(htmlStr)}
+		} else if data := output.Data["text/plain"]; nil != data && nil == json.Unmarshal(data, &text) {
+			ret = ipynbCodeBlock("", strings.TrimSuffix(string(text), "\n"))
+		} else {
+			return
+		}
+		if ast.NodeCodeBlock != ret.Type {
+			var plain ipynbText
+			if data := output.Data["text/plain"]; nil != data && nil == json.Unmarshal(data, &plain) {
+				ret.SetIALAttr(ipynbAttrTextPlain, string(plain))
+			}
+		}
+		if metadata := ipynbCompact(output.Metadata); "" != metadata {
+			ret.SetIALAttr(ipynbAttrMetadata, metadata)
+		}
+		if count := ipynbCompact(output.ExecutionCount); "" != count && "null" != count {
+			ret.SetIALAttr(ipynbAttrExecutionCount, count)
+		}
+	default:
+		return
+	}
+
+	ret.KramdownIAL = append([][]string{{"id", ast.NewNodeID()}, {ipynbAttrOutput, output.OutputType}}, ret.KramdownIAL...)
+	return
+}
+
+// ipynbBlockOutput 将带有输出 IAL 的块 block 还原为输出，图片内容从 assets 中查找。
+func ipynbBlockOutput(block *ast.Node, outputType string, assets []*IpynbAsset) (ret *ipynbOutput) {
+	ret = &ipynbOutput{OutputType: outputType}
+	text := ipynbText(ipynbBlockText(block))
+	switch outputType {
+	case "stream":
+		ret.Name = block.IALAttr(ipynbAttrName)
+		if "" != text {
+			text += "\n"
+		}
+		ret.Text = &text
+	case "error":
+		ename, evalue := block.IALAttr(ipynbAttrEName), block.IALAttr(ipynbAttrEValue)
+		traceback := []string{}
+		if "" != text {
+			traceback = strings.Split(string(text), "\n")
+		}
+		ret.EName, ret.EValue, ret.Traceback = &ename, &evalue, &traceback
+	default:
+		var mimeType string
+		var data json.RawMessage
+		switch block.Type {
+		case ast.NodeHTMLBlock:
+			mimeType = "text/html"
+			htmlStr := util.BytesToStr(block.Tokens)
+			if strings.HasPrefix(htmlStr, ipynbHTMLOpen) && strings.HasSuffix(htmlStr, ipynbHTMLClose) {
+				htmlStr = htmlStr[len(ipynbHTMLOpen) : len(htmlStr)-len(ipynbHTMLClose)]
+			}
+			data, _ = ipynbMarshal(ipynbText(htmlStr), "")
+		case ast.NodeCodeBlock:
+			mimeType = "text/plain"
+			data, _ = ipynbMarshal(text, "")
+		default:
+			mimeType = "text/plain"
+			data, _ = ipynbMarshal(text, "")
+			if img := block.ChildByType(ast.NodeImage); nil != img {
+				dest := util.BytesToStr(img.ChildByType(ast.NodeLinkDest).Tokens)
+				for _, asset := range assets {
+					if asset.Name == dest || asset.Name == path.Base(dest) {
+						mimeType = "image/png"
+						data, _ = ipynbMarshal(base64.StdEncoding.EncodeToString(asset.Data), "")
+						break
+					}
+				}
+			}
+		}
+		ret.Data = map[string]json.RawMessage{mimeType: data}
+		if plain := block.IALAttr(ipynbAttrTextPlain); "" != plain && "text/plain" != mimeType {
+			ret.Data["text/plain"], _ = ipynbMarshal(ipynbText(plain), "")
+		}
+		ret.Metadata = json.RawMessage("{}")
+		if metadata := block.IALAttr(ipynbAttrMetadata); json.Valid([]byte(metadata)) {
+			ret.Metadata = json.RawMessage(metadata)
+		}
+		if "execute_result" == outputType {
+			ret.ExecutionCount = json.RawMessage("null")
+			if count, err := strconv.Atoi(block.IALAttr(ipynbAttrExecutionCount)); nil == err {
+				ret.ExecutionCount = json.RawMessage(strconv.Itoa(count))
+			}
+		}
+	}
+	return
+}
+
+// ipynbBlockText 返回代码块 block 的内容，block 不是代码块时返回块的文本。
+func ipynbBlockText(block *ast.Node) string {
+	if ast.NodeCodeBlock == block.Type {
+		if code := block.ChildByType(ast.NodeCodeBlockCode); nil != code {
+			return strings.TrimSuffix(util.BytesToStr(code.Tokens), "\n")
+		}
+		return ""
+	}
+	return block.Text()
+}
@@ -42,6 +42,9 @@ type Lute struct {
 	Md2VditorIRDOMRendererFuncs   map[ast.NodeType]render.ExtRendererFunc // 用户自定义的 Md2VditorIRDOM 渲染器函数
 	Md2BlockDOMRendererFuncs      map[ast.NodeType]render.ExtRendererFunc // 用户自定义的 Md2BlockDOM 渲染器函数
 	Md2VditorSVDOMRendererFuncs   map[ast.NodeType]render.ExtRendererFunc // 用户自定义的 Md2VditorSVDOM 渲染器函数
+
+	HTML2MdRules     []HTML2MdRule // 用户自定义的 HTML2Md 转换规则，在内置的元素转换逻辑之前匹配
+	HTML2MdTableMode int           // HTML2Md 复杂表格的转换方式，参考 HTML2MdTableExpand 等常量
 }
 
 // New 创建一个新的 Lute 引擎。
@@ -144,6 +147,13 @@ func (lute *Lute) RenderJSON(markdown string) (json string) {
 	return
 }
 
+// ToC 返回 markdown 的标题树，可用于渲染侧边栏目录。
+func (lute *Lute) ToC(markdown string) []*render.Heading {
+	tree := parse.Parse("", []byte(markdown), lute.ParseOptions)
+	renderer := render.NewHtmlRenderer(tree, lute.RenderOptions)
+	return renderer.Headings()
+}
+
 // Space 用于在 text 中的中西文之间插入空格。
 func (lute *Lute) Space(text string) string {
 	return render.Space0(text)
@@ -356,6 +366,34 @@ func (lute *Lute) SetToC(b bool) {
 	lute.RenderOptions.ToC = b
 }
 
+func (lute *Lute) SetToCMinLevel(level int) {
+	lute.RenderOptions.ToCMinLevel = level
+}
+
+func (lute *Lute) SetToCMaxLevel(level int) {
+	lute.RenderOptions.ToCMaxLevel = level
+}
+
+func (lute *Lute) SetToCOrdered(b bool) {
+	lute.RenderOptions.ToCOrdered = b
+}
+
+func (lute *Lute) SetToCNumbering(b bool) {
+	lute.RenderOptions.ToCNumbering = b
+}
+
+func (lute *Lute) SetToCNumberHeadings(b bool) {
+	lute.RenderOptions.ToCNumberHeadings = b
+}
+
+func (lute *Lute) SetToCNestedHeadings(b bool) {
+	lute.RenderOptions.ToCNestedHeadings = b
+}
+
+func (lute *Lute) SetToCNav(b bool) {
+	lute.RenderOptions.ToCNav = b
+}
+
 func (lute *Lute) SetHeadingID(b bool) {
 	lute.ParseOptions.HeadingID = b
 	lute.RenderOptions.HeadingID = b
@@ -425,6 +463,10 @@ func (lute *Lute) GetLinkBase() string {
 	return lute.RenderOptions.LinkBase
 }
 
+func (lute *Lute) SetLinkResolver(resolver render.LinkResolver) {
+	lute.RenderOptions.LinkResolver = resolver
+}
+
 func (lute *Lute) SetVditorCodeBlockPreview(b bool) {
 	lute.RenderOptions.VditorCodeBlockPreview = b
 }
@@ -533,6 +575,18 @@ func (lute *Lute) SetDataImage(b bool) {
 	lute.ParseOptions.DataImage = b
 }
 
+func (lute *Lute) SetMediaEmbed(b bool) {
+	lute.ParseOptions.MediaEmbed = b
+}
+
+func (lute *Lute) SetMediaEmbedProviders(providers []*parse.MediaEmbedProvider) {
+	lute.ParseOptions.MediaEmbedProviders = providers
+}
+
+func (lute *Lute) SetLinkifiers(linkifiers []*parse.Linkifier) {
+	lute.ParseOptions.Linkifiers = linkifiers
+}
+
 func (lute *Lute) SetTextMark(b bool) {
 	lute.ParseOptions.TextMark = b
 }
@@ -558,6 +612,42 @@ func (lute *Lute) SetSpellcheck(b bool) {
 	lute.RenderOptions.Spellcheck = b
 }
 
+func (lute *Lute) SetSlidesHeadingLevel(level int) {
+	lute.RenderOptions.SlidesHeadingLevel = level
+}
+
+func (lute *Lute) SetSlidesRevealJS(url string) {
+	lute.RenderOptions.SlidesRevealJS = url
+}
+
+func (lute *Lute) SetFigure(b bool) {
+	lute.RenderOptions.Figure = b
+}
+
+func (lute *Lute) SetFigureNumbering(b bool) {
+	lute.RenderOptions.FigureNumbering = b
+}
+
+func (lute *Lute) SetMathML(b bool) {
+	lute.RenderOptions.MathML = b
+}
+
+func (lute *Lute) SetHeadingSlugger(slugger render.HeadingSlugger) {
+	lute.RenderOptions.HeadingSlugger = slugger
+}
+
+func (lute *Lute) SetHeadingSections(b bool) {
+	lute.RenderOptions.HeadingSections = b
+}
+
+func (lute *Lute) SetHTML2MdRules(rules []HTML2MdRule) {
+	lute.HTML2MdRules = rules
+}
+
+func (lute *Lute) SetHTML2MdTableMode(mode int) {
+	lute.HTML2MdTableMode = mode
+}
+
 func (lute *Lute) SetJSRenderers(options map[string]map[string]*js.Object) {
 	for rendererType, extRenderer := range options["renderers"] {
 		switch extRenderer.Interface().(type) { // 稍微进行一点格式校验
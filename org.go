@@ -0,0 +1,717 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/render"
+	"github.com/88250/lute/util"
+)
+
+// Org2Tree 将 Emacs Org 文本解析为 AST。
+//
+// 支持标题、列表、复选框、#+BEGIN_SRC 代码块、表格、链接和属性抽屉（:PROPERTIES:）。属性抽屉会被转换为节点的 KramdownIAL，
+// 其中 :ID: 属性对应块 ID；标题标签 :a:b: 转换为 tags 属性。
+func (lute *Lute) Org2Tree(org string) (ret *parse.Tree) {
+	ret = &parse.Tree{Name: "", Root: &ast.Node{Type: ast.NodeDocument}, Context: &parse.Context{ParseOption: lute.ParseOptions}}
+	ret.Context.Tip = ret.Root
+
+	org = strings.ReplaceAll(org, "\r\n", "\n")
+	lines := strings.Split(org, "\n")
+	lute.parseOrgBlocks(lines, ret.Root)
+
+	if lute.ParseOptions.KramdownBlockIAL {
+		// 将属性抽屉生成的 IAL 以 kramdown 块级 IAL 节点挂到块后面，以便格式化渲染和 Protyle 渲染
+		var ialNodes []*ast.Node
+		ast.Walk(ret.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+			if !entering || !n.IsBlock() || ast.NodeDocument == n.Type || 1 > len(n.KramdownIAL) {
+				return ast.WalkContinue
+			}
+			ialNodes = append(ialNodes, n)
+			return ast.WalkContinue
+		})
+		for _, n := range ialNodes {
+			n.InsertAfter(&ast.Node{Type: ast.NodeKramdownBlockIAL, Tokens: parse.IAL2Tokens(n.KramdownIAL)})
+		}
+		if 0 < len(ret.Root.KramdownIAL) {
+			ret.Root.AppendChild(&ast.Node{Type: ast.NodeKramdownBlockIAL, Tokens: parse.IAL2Tokens(ret.Root.KramdownIAL)})
+		}
+	}
+	return
+}
+
+// Org2Md 将 Emacs Org 文本转换为 Markdown。
+func (lute *Lute) Org2Md(org string) (markdown string) {
+	tree := lute.Org2Tree(org)
+	renderer := render.NewFormatRenderer(tree, lute.RenderOptions)
+	markdown = util.BytesToStr(renderer.Render())
+	return
+}
+
+// Org2BlockDOM 将 Emacs Org 文本转换为 Protyle BlockDOM。
+func (lute *Lute) Org2BlockDOM(org string) (vHTML string) {
+	markdown := lute.Org2Md(org)
+	tree := parse.Parse("", []byte(markdown), lute.ParseOptions)
+	renderer := render.NewProtyleRenderer(tree, lute.RenderOptions)
+	output := renderer.Render()
+	vHTML = util.BytesToStr(output)
+	return
+}
+
+// Md2Org 将 Markdown 转换为 Emacs Org 文本。
+func (lute *Lute) Md2Org(markdown string) (org string) {
+	return lute.md2(markdown, render.NewOrgRenderer)
+}
+
+var (
+	orgHeadlineRegexp = regexp.MustCompile(`^(\*+)\s+(.*)$`)
+	orgTagsRegexp     = regexp.MustCompile(`\s+(:[^\s:]+(?::[^\s:]+)*:)\s*$`)
+	orgListItemRegexp = regexp.MustCompile(`^(\s*)([-+*]|\d+[.)])(?:\s+(.*))?$`)
+	orgPropertyRegexp = regexp.MustCompile(`^\s*:([^\s:]+):\s*(.*)$`)
+	orgKeywordRegexp  = regexp.MustCompile(`^\s*#\+([A-Za-z_]+):\s*(.*)$`)
+	orgPlanningRegexp = regexp.MustCompile(`^\s*(SCHEDULED|DEADLINE|CLOSED):`)
+	orgImageExts      = []string{".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".bmp"}
+)
+
+func (lute *Lute) parseOrgBlocks(lines []string, parent *ast.Node) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if "" == trimmed {
+			i++
+			continue
+		}
+
+		if ast.NodeDocument == parent.Type {
+			if m := orgHeadlineRegexp.FindStringSubmatch(line); nil != m {
+				i = lute.parseOrgHeadline(lines, i, m, parent)
+				continue
+			}
+		}
+
+		upper := strings.ToUpper(trimmed)
+		switch {
+		case strings.HasPrefix(upper, "#+BEGIN_"):
+			i = lute.parseOrgBlock(lines, i, parent)
+			continue
+		case ":PROPERTIES:" == upper:
+			var ial [][]string
+			ial, i = orgPropertiesDrawer(lines, i)
+			parent.KramdownIAL = append(parent.KramdownIAL, ial...)
+			continue
+		case strings.HasPrefix(trimmed, "#+"):
+			if m := orgKeywordRegexp.FindStringSubmatch(line); nil != m && "TITLE" == strings.ToUpper(m[1]) && ast.NodeDocument == parent.Type {
+				parent.SetIALAttr("title", m[2])
+			}
+			i++
+			continue
+		case "#" == trimmed || strings.HasPrefix(trimmed, "# "):
+			i++ // 注释行
+			continue
+		case strings.HasPrefix(trimmed, "|"):
+			i = lute.parseOrgTable(lines, i, parent)
+			continue
+		case isOrgThematicBreak(trimmed):
+			parent.AppendChild(&ast.Node{Type: ast.NodeThematicBreak})
+			i++
+			continue
+		case `\[` == trimmed || "$$" == trimmed:
+			i = parseOrgMathBlock(lines, i, parent)
+			continue
+		}
+
+		if m := orgListItemRegexp.FindStringSubmatch(line); nil != m && isOrgListItem(m) {
+			i = lute.parseOrgList(lines, i, parent)
+			continue
+		}
+
+		i = lute.parseOrgParagraph(lines, i, parent)
+	}
+}
+
+func (lute *Lute) parseOrgHeadline(lines []string, i int, m []string, parent *ast.Node) int {
+	heading := &ast.Node{Type: ast.NodeHeading, HeadingLevel: len(m[1])}
+	if 6 < heading.HeadingLevel {
+		heading.HeadingLevel = 6
+	}
+	heading.AppendChild(&ast.Node{Type: ast.NodeHeadingC8hMarker, Tokens: util.StrToBytes(strings.Repeat("#", heading.HeadingLevel))})
+
+	title := m[2]
+	if tags := orgTagsRegexp.FindStringSubmatch(title); nil != tags {
+		title = strings.TrimSpace(title[:len(title)-len(tags[0])])
+		tagList := strings.Split(strings.Trim(tags[1], ":"), ":")
+		heading.SetIALAttr("tags", strings.Join(tagList, ","))
+	}
+	lute.appendOrgInlines(heading, title)
+	parent.AppendChild(heading)
+	i++
+
+	// 跳过计划行（SCHEDULED/DEADLINE/CLOSED），然后读取属性抽屉
+	for i < len(lines) && orgPlanningRegexp.MatchString(lines[i]) {
+		i++
+	}
+	if i < len(lines) && ":PROPERTIES:" == strings.ToUpper(strings.TrimSpace(lines[i])) {
+		var ial [][]string
+		ial, i = orgPropertiesDrawer(lines, i)
+		for _, kv := range ial {
+			heading.SetIALAttr(kv[0], kv[1])
+		}
+		if id := heading.IALAttr("id"); "" != id {
+			heading.ID = id
+		}
+	}
+	return i
+}
+
+// orgPropertiesDrawer 解析从 lines[i] 开始的属性抽屉，返回 IAL 和抽屉后的下一行位置。
+func orgPropertiesDrawer(lines []string, i int) (ial [][]string, next int) {
+	for i++; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if ":END:" == strings.ToUpper(trimmed) {
+			i++
+			break
+		}
+		if m := orgPropertyRegexp.FindStringSubmatch(lines[i]); nil != m {
+			ial = append(ial, []string{strings.ToLower(m[1]), strings.TrimSpace(m[2])})
+		}
+	}
+	next = i
+	return
+}
+
+func (lute *Lute) parseOrgBlock(lines []string, i int, parent *ast.Node) int {
+	trimmed := strings.TrimSpace(lines[i])
+	fields := strings.Fields(trimmed)
+	blockType := strings.ToUpper(strings.TrimPrefix(strings.ToUpper(fields[0]), "#+BEGIN_"))
+	end := "#+END_" + blockType
+	var body []string
+	i++
+	for ; i < len(lines); i++ {
+		if strings.ToUpper(strings.TrimSpace(lines[i])) == end {
+			i++
+			break
+		}
+		body = append(body, lines[i])
+	}
+
+	switch blockType {
+	case "SRC", "EXAMPLE":
+		lang := ""
+		if "SRC" == blockType && 1 < len(fields) {
+			lang = fields[1]
+		}
+		parent.AppendChild(newOrgCodeBlock(lang, orgDedent(body)))
+	case "QUOTE":
+		blockquote := &ast.Node{Type: ast.NodeBlockquote}
+		blockquote.AppendChild(&ast.Node{Type: ast.NodeBlockquoteMarker, Tokens: util.StrToBytes(">")})
+		lute.parseOrgBlocks(body, blockquote)
+		parent.AppendChild(blockquote)
+	case "EXPORT":
+		if 1 < len(fields) && "html" == strings.ToLower(fields[1]) {
+			parent.AppendChild(&ast.Node{Type: ast.NodeHTMLBlock, Tokens: util.StrToBytes(strings.Join(body, "\n"))})
+		} else {
+			parent.AppendChild(newOrgCodeBlock(strings.ToLower(strings.Join(fields[1:], " ")), orgDedent(body)))
+		}
+	default:
+		// CENTER、VERSE 等块仅保留内容
+		lute.parseOrgBlocks(body, parent)
+	}
+	return i
+}
+
+func newOrgCodeBlock(lang string, code string) (ret *ast.Node) {
+	ret = &ast.Node{Type: ast.NodeCodeBlock, IsFencedCodeBlock: true, CodeBlockFenceChar: '`', CodeBlockFenceLen: 3, CodeBlockInfo: util.StrToBytes(lang)}
+	ret.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceOpenMarker, Tokens: util.StrToBytes("```"), CodeBlockFenceLen: 3})
+	ret.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceInfoMarker, CodeBlockInfo: util.StrToBytes(lang)})
+	if "" != code {
+		code += "\n"
+	}
+	ret.AppendChild(&ast.Node{Type: ast.NodeCodeBlockCode, Tokens: util.StrToBytes(code)})
+	ret.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceCloseMarker, Tokens: util.StrToBytes("```"), CodeBlockFenceLen: 3})
+	return
+}
+
+func parseOrgMathBlock(lines []string, i int, parent *ast.Node) int {
+	closeMarker := `\]`
+	if "$$" == strings.TrimSpace(lines[i]) {
+		closeMarker = "$$"
+	}
+	var body []string
+	for i++; i < len(lines); i++ {
+		if closeMarker == strings.TrimSpace(lines[i]) {
+			i++
+			break
+		}
+		body = append(body, lines[i])
+	}
+	math := &ast.Node{Type: ast.NodeMathBlock}
+	math.AppendChild(&ast.Node{Type: ast.NodeMathBlockOpenMarker})
+	math.AppendChild(&ast.Node{Type: ast.NodeMathBlockContent, Tokens: util.StrToBytes(orgDedent(body))})
+	math.AppendChild(&ast.Node{Type: ast.NodeMathBlockCloseMarker})
+	parent.AppendChild(math)
+	return i
+}
+
+func (lute *Lute) parseOrgTable(lines []string, i int, parent *ast.Node) int {
+	var rows [][]string
+	headRows := -1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "|") {
+			break
+		}
+		if strings.HasPrefix(trimmed, "|-") {
+			if 0 > headRows {
+				headRows = len(rows)
+			}
+			continue
+		}
+		trimmed = strings.TrimSuffix(strings.TrimPrefix(trimmed, "|"), "|")
+		var cells []string
+		for _, cell := range strings.Split(trimmed, "|") {
+			cells = append(cells, strings.TrimSpace(cell))
+		}
+		rows = append(rows, cells)
+	}
+	if 1 > len(rows) {
+		return i
+	}
+
+	cols := 0
+	for _, row := range rows {
+		if cols < len(row) {
+			cols = len(row)
+		}
+	}
+	table := &ast.Node{Type: ast.NodeTable, TableAligns: make([]int, cols)}
+	for r, row := range rows {
+		tr := &ast.Node{Type: ast.NodeTableRow, TableAligns: table.TableAligns}
+		for c := 0; c < cols; c++ {
+			td := &ast.Node{Type: ast.NodeTableCell}
+			if c < len(row) {
+				lute.appendOrgInlines(td, row[c])
+			}
+			tr.AppendChild(td)
+		}
+		if 0 == r {
+			// Markdown 表格仅支持一行表头，没有分隔行时首行作为表头
+			head := &ast.Node{Type: ast.NodeTableHead}
+			head.AppendChild(tr)
+			table.AppendChild(head)
+			continue
+		}
+		table.AppendChild(tr)
+	}
+	parent.AppendChild(table)
+	return i
+}
+
+func isOrgThematicBreak(trimmed string) bool {
+	return 5 <= len(trimmed) && "" == strings.Trim(trimmed, "-")
+}
+
+func isOrgListItem(m []string) bool {
+	// 顶格的 * 是标题，列表项使用 * 作为标记时必须缩进
+	if "*" == m[2] && "" == m[1] {
+		return false
+	}
+	return true
+}
+
+func orgIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+func (lute *Lute) parseOrgList(lines []string, i int, parent *ast.Node) int {
+	first := orgListItemRegexp.FindStringSubmatch(lines[i])
+	indent := len(first[1])
+	ordered := '0' <= first[2][0] && '9' >= first[2][0]
+	list := &ast.Node{Type: ast.NodeList, ListData: &ast.ListData{Tight: true}}
+	if ordered {
+		list.ListData.Typ = 1
+	}
+	parent.AppendChild(list)
+
+	num := 0
+	separated := false
+	for i < len(lines) {
+		m := orgListItemRegexp.FindStringSubmatch(lines[i])
+		if nil == m || !isOrgListItem(m) || len(m[1]) != indent || ordered != ('0' <= m[2][0] && '9' >= m[2][0]) {
+			break
+		}
+		if separated {
+			// 列表项之间存在空行时为松散列表
+			list.ListData.Tight = false
+		}
+
+		// 收集列表项内容：首行标记后的文本以及后续缩进大于列表项标记的行
+		contentCol := indent + len(m[2]) + 1
+		body := []string{m[3]}
+		blankLines := 0
+		j := i + 1
+		for ; j < len(lines); j++ {
+			if "" == strings.TrimSpace(lines[j]) {
+				blankLines++
+				if 1 < blankLines {
+					break
+				}
+				body = append(body, "")
+				continue
+			}
+			if orgIndent(lines[j]) <= indent {
+				break
+			}
+			blankLines = 0
+			line := lines[j]
+			if strip := orgIndent(line); strip < contentCol {
+				line = line[strip:]
+			} else {
+				line = line[contentCol:]
+			}
+			body = append(body, line)
+		}
+		separated = false
+		for 0 < len(body) && "" == body[len(body)-1] {
+			body = body[:len(body)-1]
+			separated = true
+		}
+		i = j
+
+		li := &ast.Node{Type: ast.NodeListItem, ListData: &ast.ListData{Typ: list.ListData.Typ}}
+		if ordered {
+			marker := strings.TrimRight(m[2], ".)")
+			n, _ := strconv.Atoi(marker)
+			if 0 == num {
+				num = n
+				list.ListData.Start = n
+			}
+			li.ListData.Num = num
+			li.ListData.Start = num
+			li.ListData.Marker = util.StrToBytes(strconv.Itoa(num))
+			li.ListData.Delimiter = m[2][len(m[2])-1]
+			num++
+		} else {
+			li.ListData.BulletChar = '-'
+			li.ListData.Marker = []byte("-")
+			li.ListData.Num = -1
+		}
+		li.Tokens = li.ListData.Marker
+
+		checkbox := ""
+		if 0 < len(body) && 3 <= len(body[0]) && '[' == body[0][0] && ']' == body[0][2] && strings.Contains(" xX-", body[0][1:2]) {
+			checkbox = body[0][:3]
+			body[0] = strings.TrimLeft(body[0][3:], " ")
+		}
+
+		lute.parseOrgBlocks(body, li)
+		if "" != checkbox {
+			li.ListData.Typ = 3
+			list.ListData.Typ = 3
+			if ordered {
+				li.ListData.BulletChar = 0
+			}
+			checked := 'x' == checkbox[1] || 'X' == checkbox[1]
+			li.ListData.Checked = checked
+			p := li.FirstChild
+			if nil == p || ast.NodeParagraph != p.Type {
+				p = &ast.Node{Type: ast.NodeParagraph}
+				li.PrependChild(p)
+			}
+			marker := &ast.Node{Type: ast.NodeTaskListItemMarker, TaskListItemChecked: checked}
+			if checked {
+				marker.Tokens = []byte("[X]")
+			} else {
+				marker.Tokens = []byte("[ ]")
+			}
+			if nil != p.FirstChild && ast.NodeText == p.FirstChild.Type {
+				p.FirstChild.Tokens = append([]byte(" "), p.FirstChild.Tokens...)
+			}
+			p.PrependChild(marker)
+		}
+		list.AppendChild(li)
+
+		if 1 < blankLines {
+			break
+		}
+	}
+
+	list.ListData.BulletChar = list.FirstChild.ListData.BulletChar
+	list.ListData.Marker = list.FirstChild.ListData.Marker
+	list.ListData.Delimiter = list.FirstChild.ListData.Delimiter
+	list.ListData.Num = list.FirstChild.ListData.Num
+	for li := list.FirstChild; nil != li; li = li.Next {
+		li.ListData.Tight = list.ListData.Tight
+		if 3 == list.ListData.Typ {
+			li.ListData.Typ = 3
+			if ordered {
+				li.ListData.BulletChar = 0
+			}
+		}
+	}
+	return i
+}
+
+func (lute *Lute) parseOrgParagraph(lines []string, i int, parent *ast.Node) int {
+	var buf []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if "" == trimmed {
+			break
+		}
+		if 0 < len(buf) {
+			if orgHeadlineRegexp.MatchString(line) && ast.NodeDocument == parent.Type {
+				break
+			}
+			upper := strings.ToUpper(trimmed)
+			if strings.HasPrefix(upper, "#+") || strings.HasPrefix(trimmed, "|") || isOrgThematicBreak(trimmed) || `\[` == trimmed || "$$" == trimmed {
+				break
+			}
+			if m := orgListItemRegexp.FindStringSubmatch(line); nil != m && isOrgListItem(m) {
+				break
+			}
+		}
+		buf = append(buf, trimmed)
+	}
+
+	p := &ast.Node{Type: ast.NodeParagraph}
+	for j, line := range buf {
+		hardBreak := strings.HasSuffix(line, `\\`)
+		if hardBreak {
+			line = strings.TrimRight(strings.TrimSuffix(line, `\\`), " ")
+		}
+		lute.appendOrgInlines(p, line)
+		if j < len(buf)-1 {
+			if hardBreak {
+				p.AppendChild(&ast.Node{Type: ast.NodeHardBreak, Tokens: []byte("\n")})
+			} else {
+				p.AppendChild(&ast.Node{Type: ast.NodeSoftBreak, Tokens: []byte("\n")})
+			}
+		}
+	}
+	parent.AppendChild(p)
+	return i
+}
+
+func orgDedent(lines []string) string {
+	min := -1
+	for _, line := range lines {
+		if "" == strings.TrimSpace(line) {
+			continue
+		}
+		if indent := orgIndent(line); 0 > min || indent < min {
+			min = indent
+		}
+	}
+	var ret []string
+	for _, line := range lines {
+		if min <= len(line) && 0 < min {
+			line = line[min:]
+		}
+		ret = append(ret, line)
+	}
+	return strings.Join(ret, "\n")
+}
+
+// appendOrgInlines 解析 Org 行级元素并追加到 parent 下。
+func (lute *Lute) appendOrgInlines(parent *ast.Node, text string) {
+	for _, n := range lute.parseOrgInlines(text) {
+		parent.AppendChild(n)
+	}
+}
+
+func (lute *Lute) parseOrgInlines(text string) (ret []*ast.Node) {
+	textStart := 0
+	flushText := func(end int) {
+		if textStart < end {
+			ret = append(ret, &ast.Node{Type: ast.NodeText, Tokens: util.StrToBytes(text[textStart:end])})
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case '[' == c && strings.HasPrefix(text[i:], "[["):
+			end := strings.Index(text[i:], "]]")
+			if 0 > end {
+				break
+			}
+			flushText(i)
+			ret = append(ret, lute.newOrgLink(text[i+2:i+end]))
+			i += end + 2
+			textStart = i
+			continue
+		case '\\' == c && strings.HasPrefix(text[i:], `\(`):
+			end := strings.Index(text[i+2:], `\)`)
+			if 0 > end {
+				break
+			}
+			flushText(i)
+			math := &ast.Node{Type: ast.NodeInlineMath}
+			math.AppendChild(&ast.Node{Type: ast.NodeInlineMathOpenMarker, Tokens: []byte("$")})
+			math.AppendChild(&ast.Node{Type: ast.NodeInlineMathContent, Tokens: util.StrToBytes(text[i+2 : i+2+end])})
+			math.AppendChild(&ast.Node{Type: ast.NodeInlineMathCloseMarker, Tokens: []byte("$")})
+			ret = append(ret, math)
+			i += end + 4
+			textStart = i
+			continue
+		case strings.IndexByte("*/_+=~", c) >= 0:
+			end := orgEmphasisEnd(text, i)
+			if 0 > end {
+				break
+			}
+			flushText(i)
+			ret = append(ret, lute.newOrgEmphasis(c, text[i+1:end]))
+			i = end + 1
+			textStart = i
+			continue
+		}
+		i++
+	}
+	flushText(len(text))
+	return
+}
+
+// orgEmphasisEnd 返回从 text[start] 开始的强调标记对应的结束标记位置，不构成强调时返回 -1。
+func orgEmphasisEnd(text string, start int) int {
+	marker := text[start]
+	if 0 < start && !isOrgEmphasisPre(text[start-1]) {
+		return -1
+	}
+	if start+1 >= len(text) || isOrgSpace(text[start+1]) {
+		return -1
+	}
+	for j := start + 2; j < len(text); j++ {
+		if marker != text[j] || isOrgSpace(text[j-1]) {
+			continue
+		}
+		if j+1 == len(text) || isOrgEmphasisPost(text[j+1]) {
+			return j
+		}
+	}
+	return -1
+}
+
+func isOrgSpace(c byte) bool {
+	return ' ' == c || '\t' == c || '\n' == c
+}
+
+func isOrgEmphasisPre(c byte) bool {
+	return isOrgSpace(c) || strings.IndexByte(`-({'"`, c) >= 0 || 0x80 <= c
+}
+
+func isOrgEmphasisPost(c byte) bool {
+	return isOrgSpace(c) || strings.IndexByte(`-.,;:!?'")}[`, c) >= 0 || 0x80 <= c
+}
+
+func (lute *Lute) newOrgEmphasis(marker byte, content string) (ret *ast.Node) {
+	switch marker {
+	case '=', '~':
+		ret = &ast.Node{Type: ast.NodeCodeSpan, CodeMarkerLen: 1}
+		ret.AppendChild(&ast.Node{Type: ast.NodeCodeSpanOpenMarker, Tokens: []byte("`")})
+		ret.AppendChild(&ast.Node{Type: ast.NodeCodeSpanContent, Tokens: util.StrToBytes(content)})
+		ret.AppendChild(&ast.Node{Type: ast.NodeCodeSpanCloseMarker, Tokens: []byte("`")})
+		return
+	case '*':
+		ret = &ast.Node{Type: ast.NodeStrong}
+		ret.AppendChild(&ast.Node{Type: ast.NodeStrongA6kOpenMarker, Tokens: []byte("**")})
+		lute.appendOrgInlines(ret, content)
+		ret.AppendChild(&ast.Node{Type: ast.NodeStrongA6kCloseMarker, Tokens: []byte("**")})
+	case '/':
+		ret = &ast.Node{Type: ast.NodeEmphasis}
+		ret.AppendChild(&ast.Node{Type: ast.NodeEmA6kOpenMarker, Tokens: []byte("*")})
+		lute.appendOrgInlines(ret, content)
+		ret.AppendChild(&ast.Node{Type: ast.NodeEmA6kCloseMarker, Tokens: []byte("*")})
+	case '_':
+		ret = &ast.Node{Type: ast.NodeUnderline}
+		ret.AppendChild(&ast.Node{Type: ast.NodeUnderlineOpenMarker, Tokens: []byte("<u>")})
+		lute.appendOrgInlines(ret, content)
+		ret.AppendChild(&ast.Node{Type: ast.NodeUnderlineCloseMarker, Tokens: []byte("</u>")})
+	case '+':
+		ret = &ast.Node{Type: ast.NodeStrikethrough}
+		ret.AppendChild(&ast.Node{Type: ast.NodeStrikethrough2OpenMarker, Tokens: []byte("~~")})
+		lute.appendOrgInlines(ret, content)
+		ret.AppendChild(&ast.Node{Type: ast.NodeStrikethrough2CloseMarker, Tokens: []byte("~~")})
+	}
+	return
+}
+
+// newOrgLink 根据 [[dest][desc]] 中括号内的内容构造链接或者图片节点。
+func (lute *Lute) newOrgLink(content string) (ret *ast.Node) {
+	dest, desc := content, ""
+	if idx := strings.Index(content, "]["); 0 <= idx {
+		dest, desc = content[:idx], content[idx+2:]
+	}
+	dest = strings.TrimPrefix(dest, "file:")
+
+	if lute.ParseOptions.BlockRef && strings.HasPrefix(dest, "id:") {
+		// [[id:xxx][desc]] 转换为块引用
+		ret = &ast.Node{Type: ast.NodeBlockRef}
+		ret.AppendChild(&ast.Node{Type: ast.NodeOpenParen})
+		ret.AppendChild(&ast.Node{Type: ast.NodeOpenParen})
+		ret.AppendChild(&ast.Node{Type: ast.NodeBlockRefID, Tokens: util.StrToBytes(dest[3:])})
+		if "" != desc {
+			ret.AppendChild(&ast.Node{Type: ast.NodeBlockRefSpace})
+			ret.AppendChild(&ast.Node{Type: ast.NodeBlockRefText, Tokens: util.StrToBytes(desc)})
+		}
+		ret.AppendChild(&ast.Node{Type: ast.NodeCloseParen})
+		ret.AppendChild(&ast.Node{Type: ast.NodeCloseParen})
+		return
+	}
+
+	if "" == desc && isOrgImage(dest) {
+		ret = &ast.Node{Type: ast.NodeImage}
+		ret.AppendChild(&ast.Node{Type: ast.NodeBang})
+		ret.AppendChild(&ast.Node{Type: ast.NodeOpenBracket})
+		ret.AppendChild(&ast.Node{Type: ast.NodeLinkText})
+		ret.AppendChild(&ast.Node{Type: ast.NodeCloseBracket})
+		ret.AppendChild(&ast.Node{Type: ast.NodeOpenParen})
+		ret.AppendChild(&ast.Node{Type: ast.NodeLinkDest, Tokens: util.StrToBytes(dest)})
+		ret.AppendChild(&ast.Node{Type: ast.NodeCloseParen})
+		return
+	}
+
+	ret = &ast.Node{Type: ast.NodeLink}
+	ret.AppendChild(&ast.Node{Type: ast.NodeOpenBracket})
+	if "" == desc {
+		ret.AppendChild(&ast.Node{Type: ast.NodeLinkText, Tokens: util.StrToBytes(dest)})
+	} else {
+		for _, n := range lute.parseOrgInlines(desc) {
+			ast.Walk(n, func(c *ast.Node, entering bool) ast.WalkStatus {
+				if entering && ast.NodeText == c.Type {
+					c.Type = ast.NodeLinkText
+				}
+				return ast.WalkContinue
+			})
+			ret.AppendChild(n)
+		}
+	}
+	ret.AppendChild(&ast.Node{Type: ast.NodeCloseBracket})
+	ret.AppendChild(&ast.Node{Type: ast.NodeOpenParen})
+	ret.AppendChild(&ast.Node{Type: ast.NodeLinkDest, Tokens: util.StrToBytes(dest)})
+	ret.AppendChild(&ast.Node{Type: ast.NodeCloseParen})
+	return
+}
+
+func isOrgImage(dest string) bool {
+	lower := strings.ToLower(dest)
+	for _, ext := range orgImageExts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,375 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"regexp"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/html/atom"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/render"
+	"github.com/88250/lute/util"
+)
+
+// outlineItem 描述了导入的大纲或者脑图中的一个节点。
+type outlineItem struct {
+	text     string         // 节点文本
+	note     string         // 备注，多个段落之间使用空行分隔
+	task     bool           // 是否为任务
+	checked  bool           // 任务是否已完成
+	children []*outlineItem // 子节点
+}
+
+// Md2OPML 将 Markdown 中的标题层级和嵌套列表转换为 OPML 2.0 大纲。
+func (lute *Lute) Md2OPML(markdown string) (opml string) {
+	return lute.md2(markdown, render.NewOPMLRenderer)
+}
+
+// Md2FreeMind 将 Markdown 中的标题层级和嵌套列表转换为 FreeMind 脑图（.mm）。
+func (lute *Lute) Md2FreeMind(markdown string) (mm string) {
+	return lute.md2(markdown, render.NewFreeMindRenderer)
+}
+
+// opmlOutline 描述了 OPML 中的 outline 元素。
+type opmlOutline struct {
+	Text     string         `xml:"text,attr"`
+	Note     string         `xml:"_note,attr"`
+	Status   string         `xml:"_status,attr"`   // OmniOutliner 任务状态：checked、unchecked
+	Complete string         `xml:"_complete,attr"` // Workflowy 完成状态：true
+	Outlines []*opmlOutline `xml:"outline"`
+}
+
+// OPML2Tree 将 OPML 大纲转换为由嵌套列表构成的 AST。
+//
+// outline 的 text 属性作为列表项的文本，_note 属性作为列表项下的段落，_status、_complete 属性转换为任务列表项，head 中的 title 作为树的名称。
+func (lute *Lute) OPML2Tree(opml string) (ret *parse.Tree, err error) {
+	doc := &struct {
+		Title    string         `xml:"head>title"`
+		Outlines []*opmlOutline `xml:"body>outline"`
+	}{}
+	if err = xml.Unmarshal([]byte(opml), doc); nil != err {
+		return
+	}
+
+	var convert func(outlines []*opmlOutline) []*outlineItem
+	convert = func(outlines []*opmlOutline) (items []*outlineItem) {
+		for _, o := range outlines {
+			item := &outlineItem{text: o.Text, note: o.Note, children: convert(o.Outlines)}
+			if "" != o.Status {
+				item.task, item.checked = true, "checked" == o.Status
+			} else if "true" == o.Complete {
+				item.task, item.checked = true, true
+			}
+			items = append(items, item)
+		}
+		return
+	}
+	ret = lute.outlineTree(strings.TrimSpace(doc.Title), convert(doc.Outlines))
+	return
+}
+
+// OPML2Md 将 OPML 大纲转换为 Markdown。
+func (lute *Lute) OPML2Md(opml string) (markdown string, err error) {
+	tree, err := lute.OPML2Tree(opml)
+	if nil != err {
+		return
+	}
+	markdown = lute.formatOutlineTree(tree)
+	return
+}
+
+// freeMindNode 描述了 FreeMind 脑图中的 node 元素。
+type freeMindNode struct {
+	Text         string `xml:"TEXT,attr"`
+	RichContents []*struct {
+		Type  string `xml:"TYPE,attr"`
+		Inner string `xml:",innerxml"`
+	} `xml:"richcontent"`
+	Icons []*struct {
+		Builtin string `xml:"BUILTIN,attr"`
+	} `xml:"icon"`
+	Nodes []*freeMindNode `xml:"node"`
+}
+
+// FreeMind2Tree 将 FreeMind 脑图（.mm，Freeplane、XMind 等工具也可以导出该格式）转换为由嵌套列表构成的 AST。
+//
+// 节点文本为空的中心节点会被忽略，其子节点作为顶层列表项；NOTE 类型的 richcontent 作为列表项下的段落，button_ok、button_cancel 图标转换为任务列表项。
+func (lute *Lute) FreeMind2Tree(mm string) (ret *parse.Tree, err error) {
+	doc := &struct {
+		Nodes []*freeMindNode `xml:"node"`
+	}{}
+	if err = xml.Unmarshal([]byte(mm), doc); nil != err {
+		return
+	}
+
+	var convert func(nodes []*freeMindNode) []*outlineItem
+	convert = func(nodes []*freeMindNode) (items []*outlineItem) {
+		for _, n := range nodes {
+			item := &outlineItem{text: n.Text, children: convert(n.Nodes)}
+			for _, richContent := range n.RichContents {
+				switch richContent.Type {
+				case "NODE":
+					if "" == item.text {
+						item.text = strings.Join(strings.Fields(lute.richContentText(richContent.Inner)), " ")
+					}
+				case "NOTE":
+					item.note = lute.richContentText(richContent.Inner)
+				}
+			}
+			for _, icon := range n.Icons {
+				switch icon.Builtin {
+				case "button_ok":
+					item.task, item.checked = true, true
+				case "button_cancel":
+					item.task = true
+				}
+			}
+			items = append(items, item)
+		}
+		return
+	}
+	ret = lute.outlineTree("", outlineRootItems(convert(doc.Nodes)))
+	return
+}
+
+// FreeMind2Md 将 FreeMind 脑图转换为 Markdown。
+func (lute *Lute) FreeMind2Md(mm string) (markdown string, err error) {
+	tree, err := lute.FreeMind2Tree(mm)
+	if nil != err {
+		return
+	}
+	markdown = lute.formatOutlineTree(tree)
+	return
+}
+
+// kityMinderNode 描述了 KityMinder JSON 中的一个节点。
+type kityMinderNode struct {
+	Data struct {
+		Text     string `json:"text"`
+		Note     string `json:"note"`
+		Progress int    `json:"progress"` // 任务进度：1 为未开始，9 为已完成
+		Type     string `json:"type"`     // RenderKityMinderJSON 生成的节点类型
+	} `json:"data"`
+	Children []*kityMinderNode `json:"children"`
+}
+
+// KityMinderJSON2Tree 将 KityMinder JSON（百度脑图、RenderKityMinderJSON 生成的数据）转换为由嵌套列表构成的 AST。
+//
+// 节点的 note 作为列表项下的段落，progress 转换为任务列表项。RenderKityMinderJSON 生成的文档和列表节点会被展开，列表项的文本取自其第一个段落子节点，其余段落子节点作为备注。
+func (lute *Lute) KityMinderJSON2Tree(jsonStr string) (ret *parse.Tree, err error) {
+	doc := &struct {
+		Root *kityMinderNode `json:"root"`
+	}{}
+	if err = json.Unmarshal([]byte(jsonStr), doc); nil != err {
+		return
+	}
+	if nil == doc.Root {
+		ret = lute.outlineTree("", nil)
+		return
+	}
+
+	var convert func(nodes []*kityMinderNode) []*outlineItem
+	convert = func(nodes []*kityMinderNode) (items []*outlineItem) {
+		for _, n := range nodes {
+			if nil == n {
+				continue
+			}
+
+			children := n.Children
+			switch n.Data.Type {
+			case "NodeDocument", "NodeList":
+				items = append(items, convert(children)...)
+				continue
+			case "NodeListItem":
+				if 0 < len(children) && nil != children[0] && "NodeParagraph" == children[0].Data.Type {
+					n.Data.Text, children = children[0].Data.Text, children[1:]
+				}
+				// 其余段落子节点作为备注
+				notes, rest := []string{n.Data.Note}, children[:0:0]
+				for _, c := range children {
+					if nil != c && "NodeParagraph" == c.Data.Type {
+						notes = append(notes, c.Data.Text)
+					} else {
+						rest = append(rest, c)
+					}
+				}
+				n.Data.Note, children = strings.Join(notes, "\n\n"), rest
+			}
+
+			item := &outlineItem{text: n.Data.Text, note: strings.TrimSpace(n.Data.Note), children: convert(children)}
+			if 0 < n.Data.Progress {
+				item.task, item.checked = true, 9 == n.Data.Progress
+			}
+			items = append(items, item)
+		}
+		return
+	}
+	ret = lute.outlineTree("", outlineRootItems(convert([]*kityMinderNode{doc.Root})))
+	return
+}
+
+// KityMinderJSON2Md 将 KityMinder JSON 转换为 Markdown。
+func (lute *Lute) KityMinderJSON2Md(jsonStr string) (markdown string, err error) {
+	tree, err := lute.KityMinderJSON2Tree(jsonStr)
+	if nil != err {
+		return
+	}
+	markdown = lute.formatOutlineTree(tree)
+	return
+}
+
+// outlineRootItems 返回脑图中心节点下的节点：中心节点文本为空时使用其子节点作为顶层节点。
+func outlineRootItems(items []*outlineItem) []*outlineItem {
+	if 1 == len(items) && "" == strings.TrimSpace(items[0].text) && "" == items[0].note {
+		return items[0].children
+	}
+	return items
+}
+
+var outlineSpaces = regexp.MustCompile(`\s+`)
+
+// richContentText 返回 FreeMind richcontent 中 HTML 的文本，段落之间使用空行分隔，<br> 转换为换行。
+func (lute *Lute) richContentText(htmlStr string) string {
+	root := lute.parseHTML(htmlStr)
+	if nil == root {
+		return ""
+	}
+
+	var paragraphs []string
+	buf := &strings.Builder{}
+	flush := func() {
+		if p := strings.TrimSpace(buf.String()); "" != p {
+			paragraphs = append(paragraphs, p)
+		}
+		buf.Reset()
+	}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.Br:
+			buf.WriteString("\n")
+			return
+		case atom.P, atom.Div, atom.Li, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			flush()
+			defer flush()
+		}
+		if html.TextNode == n.Type {
+			buf.WriteString(outlineSpaces.ReplaceAllString(n.Data, " "))
+		}
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	flush()
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// outlineTree 使用 items 生成由嵌套列表构成的 AST。
+func (lute *Lute) outlineTree(name string, items []*outlineItem) (ret *parse.Tree) {
+	ret = &parse.Tree{Name: name, Root: &ast.Node{Type: ast.NodeDocument}, Context: &parse.Context{ParseOption: lute.ParseOptions}}
+	if 0 < len(items) {
+		ret.Root.AppendChild(outlineList(items))
+	}
+	return
+}
+
+// outlineList 使用 items 生成列表，备注的每个段落作为列表项下的段落，子节点作为列表项下的子列表。
+func outlineList(items []*outlineItem) (ret *ast.Node) {
+	ret = &ast.Node{Type: ast.NodeList, ListData: &ast.ListData{Tight: true, BulletChar: '-', Marker: []byte("-"), Num: -1}}
+	for _, item := range items {
+		ret.ListData.Tight = ret.ListData.Tight && "" == item.note
+		if item.task {
+			ret.ListData.Typ = 3
+		}
+	}
+
+	for _, item := range items {
+		li := &ast.Node{Type: ast.NodeListItem, ListData: &ast.ListData{Typ: ret.ListData.Typ, Tight: ret.ListData.Tight, BulletChar: '-', Marker: []byte("-"), Num: -1}}
+		li.Tokens = li.ListData.Marker
+
+		p := outlineParagraph(strings.Join(strings.Fields(item.text), " "))
+		if item.task {
+			marker := &ast.Node{Type: ast.NodeTaskListItemMarker, TaskListItemChecked: item.checked, Tokens: []byte("[ ]")}
+			if item.checked {
+				marker.Tokens = []byte("[X]")
+			}
+			if nil != p.FirstChild {
+				p.FirstChild.Tokens = append([]byte(" "), p.FirstChild.Tokens...)
+			}
+			p.PrependChild(marker)
+		}
+		li.AppendChild(p)
+
+		if "" != item.note {
+			for _, note := range strings.Split(strings.ReplaceAll(item.note, "\r\n", "\n"), "\n\n") {
+				if note = strings.TrimSpace(note); "" != note {
+					li.AppendChild(outlineParagraph(note))
+				}
+			}
+		}
+		if 0 < len(item.children) {
+			li.AppendChild(outlineList(item.children))
+		}
+		ret.AppendChild(li)
+	}
+	return
+}
+
+// outlineParagraph 生成内容为 text 的段落，text 中的换行转换为软换行。
+func outlineParagraph(text string) (ret *ast.Node) {
+	ret = &ast.Node{Type: ast.NodeParagraph}
+	for i, line := range strings.Split(text, "\n") {
+		if 0 < i {
+			ret.AppendChild(&ast.Node{Type: ast.NodeSoftBreak, Tokens: []byte("\n")})
+		}
+		if line = strings.TrimSpace(line); "" != line {
+			ret.AppendChild(&ast.Node{Type: ast.NodeText, Tokens: util.StrToBytes(outlineEscape(line))})
+		}
+	}
+	return
+}
+
+// outlineEscape 转义行 line 中的 Markdown 标记符，使导入的文本在格式化后仍然作为纯文本解析。
+//
+// 行内标记符、实体和 HTML 标签在任意位置转义，标题、引述、列表、分隔线和 IAL 等块标记符只在行首转义。
+func outlineEscape(line string) string {
+	buf := &strings.Builder{}
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch c {
+		case '\\', '`', '*', '_', '~', '$', '[', ']', '<', '>', '&', '|', '#':
+			buf.WriteByte('\\')
+		case '-', '+', '=', '{':
+			if 0 == i {
+				buf.WriteByte('\\')
+			}
+		case '.', ')':
+			if 0 < digits && i == digits { // 有序列表标记
+				buf.WriteByte('\\')
+			}
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// formatOutlineTree 将大纲生成的 AST 格式化为 Markdown。
+func (lute *Lute) formatOutlineTree(tree *parse.Tree) string {
+	renderer := render.NewFormatRenderer(tree, lute.RenderOptions)
+	return util.BytesToStr(renderer.Render())
+}
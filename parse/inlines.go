@@ -90,16 +90,24 @@ func (t *Tree) walkParseInline(node *ast.Node) {
 			t.parseGFMAutoLink(node)
 		}
 
+		if 0 < len(t.Context.ParseOption.Linkifiers) && !t.Context.ParseOption.VditorWYSIWYG && !t.Context.ParseOption.VditorIR && !t.Context.ParseOption.VditorSV && !t.Context.ParseOption.ProtyleWYSIWYG {
+			t.linkify(node)
+		}
+
 		if t.Context.ParseOption.Emoji {
 			t.emoji(node)
 		}
+
+		if ast.NodeParagraph == typ && t.Context.ParseOption.MediaEmbed {
+			t.embedMedia(node, tokens)
+		}
 		return
 	} else if ast.NodeCodeBlock == typ {
 		if node.IsFencedCodeBlock {
 			// 细化围栏代码块子节点
 			openMarker := &ast.Node{Type: ast.NodeCodeBlockFenceOpenMarker, Tokens: node.CodeBlockOpenFence, CodeBlockFenceLen: node.CodeBlockFenceLen}
 			node.PrependChild(openMarker)
-			info := &ast.Node{Type: ast.NodeCodeBlockFenceInfoMarker, CodeBlockInfo: node.CodeBlockInfo}
+			info := &ast.Node{Type: ast.NodeCodeBlockFenceInfoMarker, CodeBlockInfo: node.CodeBlockInfo, CodeBlockInfoAttrs: node.CodeBlockInfoAttrs}
 			node.AppendChild(info)
 			code := &ast.Node{Type: ast.NodeCodeBlockCode, Tokens: node.Tokens}
 			node.AppendChild(code)
@@ -0,0 +1,106 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package parse
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+)
+
+// Linkifier 描述了一个自定义自动链接规则，比如将 #123 转换为指向 issue 的链接。
+//
+// 匹配的文本前后不能紧邻字母、数字或者下划线，代码、已有链接和图片中的文本不会被处理。
+type Linkifier struct {
+	Name     string         // 名称，作为生成的链接节点的子类型 LinkSubtype
+	Pattern  *regexp.Regexp // 匹配文本的正则表达式
+	Template string         // 链接地址模板，使用 $1、${name} 等引用 Pattern 中的分组
+}
+
+// linkify 使用自定义自动链接规则处理 node 下的文本节点。
+func (t *Tree) linkify(node *ast.Node) {
+	for child := node.FirstChild; nil != child; {
+		next := child.Next
+		switch child.Type {
+		case ast.NodeText:
+			t.linkify0(child)
+		case ast.NodeLink, ast.NodeImage:
+		default:
+			t.linkify(child) // 递归处理子节点
+		}
+		child = next
+	}
+}
+
+func (t *Tree) linkify0(node *ast.Node) {
+	tokens := node.Tokens
+	last := 0
+	for {
+		var linkifier *Linkifier
+		var match []int
+		for _, l := range t.Context.ParseOption.Linkifiers {
+			if m := linkifierMatch(l, tokens, last); nil != m && (nil == match || m[0] < match[0]) {
+				linkifier, match = l, m
+			}
+		}
+		if nil == match {
+			break
+		}
+
+		if last < match[0] {
+			t.addPreviousText(node, tokens[last:match[0]])
+		}
+		dest := linkifier.Pattern.Expand(nil, []byte(linkifier.Template), tokens, match)
+		link := t.newLink(ast.NodeLink, tokens[match[0]:match[1]], html.EncodeDestination(dest), nil, 2)
+		link.LinkSubtype = linkifier.Name
+		node.InsertBefore(link)
+		last = match[1]
+	}
+
+	if 0 == last {
+		return
+	}
+	if last < len(tokens) {
+		node.Tokens = tokens[last:]
+	} else {
+		node.Unlink()
+	}
+}
+
+// linkifierMatch 返回规则 l 在 tokens 中从 from 开始的第一个前后边界合法的匹配。
+func linkifierMatch(l *Linkifier, tokens []byte, from int) []int {
+	for from < len(tokens) {
+		m := l.Pattern.FindSubmatchIndex(tokens[from:])
+		if nil == m || m[0] == m[1] {
+			return nil
+		}
+		for i := range m {
+			if 0 <= m[i] {
+				m[i] += from
+			}
+		}
+		if before, _ := utf8.DecodeLastRune(tokens[:m[0]]); !isLinkifierWordRune(before) {
+			if after, _ := utf8.DecodeRune(tokens[m[1]:]); !isLinkifierWordRune(after) {
+				return m
+			}
+		}
+		_, size := utf8.DecodeRune(tokens[m[0]:])
+		from = m[0] + size
+	}
+	return nil
+}
+
+func isLinkifierWordRune(r rune) bool {
+	return '_' == r || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,111 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package parse
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/util"
+)
+
+// MediaEmbedProvider 描述了一个媒体嵌入提供方。
+type MediaEmbedProvider struct {
+	Name     string         // 名称
+	Pattern  *regexp.Regexp // 匹配链接地址的正则表达式
+	Template string         // iframe 地址模板，使用 $1、${name} 等引用 Pattern 中的分组
+}
+
+// DefaultMediaEmbedProviders 是默认的媒体嵌入提供方列表。
+var DefaultMediaEmbedProviders = []*MediaEmbedProvider{
+	{Name: "YouTube", Pattern: regexp.MustCompile(`^https?://(?:www\.|m\.)?(?:youtube\.com/watch\?(?:[^#]*&)?v=|youtu\.be/)([\w-]{11})`), Template: "https://www.youtube.com/embed/$1"},
+	{Name: "Bilibili", Pattern: regexp.MustCompile(`^https?://(?:www\.|m\.)?bilibili\.com/video/(BV\w{10})`), Template: "https://player.bilibili.com/player.html?bvid=$1"},
+	{Name: "Bilibili", Pattern: regexp.MustCompile(`^https?://(?:www\.|m\.)?bilibili\.com/video/av(\d+)`), Template: "https://player.bilibili.com/player.html?aid=$1"},
+	{Name: "Vimeo", Pattern: regexp.MustCompile(`^https?://(?:www\.)?vimeo\.com/(\d+)`), Template: "https://player.vimeo.com/video/$1"},
+}
+
+// mediaEmbedTypes 是可以直接嵌入的音视频文件扩展名。
+var mediaEmbedTypes = map[string]ast.NodeType{
+	".mp4":  ast.NodeVideo,
+	".webm": ast.NodeVideo,
+	".mp3":  ast.NodeAudio,
+	".ogg":  ast.NodeAudio,
+}
+
+// embedMedia 将仅包含一个链接的顶层段落 paragraph 转换为 iframe、video 或者 audio 节点，原始链接保存在 MediaEmbedLink 中。
+func (t *Tree) embedMedia(paragraph *ast.Node, tokens []byte) {
+	if t.Context.ParseOption.VditorWYSIWYG || t.Context.ParseOption.VditorIR || t.Context.ParseOption.VditorSV || t.Context.ParseOption.Spin {
+		return
+	}
+	if ast.NodeDocument != paragraph.Parent.Type {
+		return
+	}
+
+	var link *ast.Node
+	for c := paragraph.FirstChild; nil != c; c = c.Next {
+		if ast.NodeText == c.Type && 1 > len(bytes.TrimSpace(c.Tokens)) {
+			continue
+		}
+		if ast.NodeLink != c.Type || nil != link {
+			return
+		}
+		link = c
+	}
+	if nil == link {
+		return
+	}
+	dest := link.ChildByType(ast.NodeLinkDest)
+	if nil == dest {
+		return
+	}
+
+	typ, embed := mediaEmbed(util.BytesToStr(dest.Tokens), t.Context.ParseOption.MediaEmbedProviders)
+	if "" == embed {
+		return
+	}
+	for c := paragraph.FirstChild; nil != c; {
+		next := c.Next
+		c.Unlink()
+		c = next
+	}
+	paragraph.Type = typ
+	paragraph.Tokens = []byte(embed)
+	paragraph.MediaEmbedLink = bytes.TrimSpace(tokens)
+}
+
+// mediaEmbed 返回链接地址 dest 对应的嵌入节点类型和 HTML，无法嵌入时返回空字符串。
+func mediaEmbed(dest string, providers []*MediaEmbedProvider) (typ ast.NodeType, embed string) {
+	if nil == providers {
+		providers = DefaultMediaEmbedProviders
+	}
+	for _, provider := range providers {
+		if m := provider.Pattern.FindStringSubmatchIndex(dest); nil != m {
+			src := string(provider.Pattern.ExpandString(nil, provider.Template, dest, m))
+			return ast.NodeIFrame, "<iframe src=\"" + html.EscapeHTMLStr(src) + "\" border=\"0\" frameborder=\"no\" framespacing=\"0\" allowfullscreen=\"true\"></iframe>"
+		}
+	}
+
+	p := dest
+	if idx := strings.IndexAny(p, "?#"); 0 <= idx {
+		p = p[:idx]
+	}
+	switch typ = mediaEmbedTypes[strings.ToLower(path.Ext(p))]; typ {
+	case ast.NodeVideo:
+		embed = "<video controls=\"controls\" src=\"" + html.EscapeHTMLStr(dest) + "\"></video>"
+	case ast.NodeAudio:
+		embed = "<audio controls=\"controls\" src=\"" + html.EscapeHTMLStr(dest) + "\"></audio>"
+	}
+	return
+}
@@ -404,6 +404,13 @@ type Options struct {
 	// 该选项的引入主要为了解决 finalParseBlockIAL 过程中是否需要移动 IAL 节点的问题，只有处于自旋过程中才需要移动 IAL 节点
 	// 其他情况（比如 API 输入 markdown https://github.com/siyuan-note/siyuan/issues/6725）无需移动处理
 	Spin bool
+	// MediaEmbed 设置是否将仅包含一个链接的顶层段落转换为媒体嵌入：匹配 MediaEmbedProviders 的链接转换为 iframe，
+	// 音视频文件链接转换为 video 或者 audio。
+	MediaEmbed bool
+	// MediaEmbedProviders 设置媒体嵌入提供方列表，为 nil 时使用 DefaultMediaEmbedProviders。
+	MediaEmbedProviders []*MediaEmbedProvider
+	// Linkifiers 设置自定义自动链接规则列表，用于将 #123、@user 等文本转换为链接。
+	Linkifiers []*Linkifier
 }
 
 var EmojiLock = sync.Mutex{}
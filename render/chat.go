@@ -0,0 +1,194 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/88250/lute/ast"
+)
+
+// 聊天工具（Slack、Telegram、Discord）渲染器共用的降级处理。
+
+// plainText 返回节点下的纯文本，包括代码、公式和表情等内容。
+func plainText(node *ast.Node) string {
+	buf := &bytes.Buffer{}
+	ast.Walk(node, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+		switch n.Type {
+		case ast.NodeText, ast.NodeLinkText, ast.NodeCodeSpanContent, ast.NodeInlineMathContent, ast.NodeBackslashContent,
+			ast.NodeHTMLEntity, ast.NodeEmojiUnicode, ast.NodeBlockRefText, ast.NodeBlockRefDynamicText:
+			buf.Write(n.Tokens)
+			return ast.WalkSkipChildren
+		case ast.NodeEmojiImg:
+			if alias := n.ChildByType(ast.NodeEmojiAlias); nil != alias {
+				buf.Write(alias.Tokens)
+			}
+			return ast.WalkSkipChildren
+		case ast.NodeSoftBreak, ast.NodeHardBreak:
+			buf.WriteByte(' ')
+		case ast.NodeTaskListItemMarker:
+			if n.TaskListItemChecked {
+				buf.WriteString("☑")
+			} else {
+				buf.WriteString("☐")
+			}
+		}
+		return ast.WalkContinue
+	})
+	return buf.String()
+}
+
+// monospaceTable 将表格转换为等宽字体下列对齐的纯文本，用于不支持表格的聊天工具。
+func monospaceTable(table *ast.Node) string {
+	var rows [][]string
+	ast.Walk(table, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+		switch n.Type {
+		case ast.NodeTableRow:
+			rows = append(rows, nil)
+		case ast.NodeTableCell:
+			rows[len(rows)-1] = append(rows[len(rows)-1], strings.TrimSpace(plainText(n)))
+			return ast.WalkSkipChildren
+		}
+		return ast.WalkContinue
+	})
+	if 1 > len(rows) {
+		return ""
+	}
+
+	widths := make([]int, len(table.TableAligns))
+	for _, row := range rows {
+		for col, cell := range row {
+			if col >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := displayWidth(cell); widths[col] < w {
+				widths[col] = w
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	writeRow := func(row []string) {
+		for col := range widths {
+			cell := ""
+			if col < len(row) {
+				cell = row[col]
+			}
+			if 0 < col {
+				buf.WriteString(" | ")
+			}
+			padding := strings.Repeat(" ", widths[col]-displayWidth(cell))
+			align := 0
+			if col < len(table.TableAligns) {
+				align = table.TableAligns[col]
+			}
+			switch align {
+			case 2:
+				left := padding[:len(padding)/2]
+				buf.WriteString(left + cell + padding[len(left):])
+			case 3:
+				buf.WriteString(padding + cell)
+			default:
+				buf.WriteString(cell + padding)
+			}
+		}
+		buf.WriteByte('\n')
+	}
+
+	writeRow(rows[0])
+	for col, width := range widths {
+		if 0 < col {
+			buf.WriteString("-+-")
+		}
+		buf.WriteString(strings.Repeat("-", width))
+	}
+	buf.WriteByte('\n')
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimRight(buf.String(), " \n")
+}
+
+// displayWidth 返回字符串在等宽字体下的显示宽度，东亚宽字符计为 2。
+func displayWidth(str string) (ret int) {
+	for _, r := range str {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hangul, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) ||
+			(0xFF01 <= r && 0xFF60 >= r) || (0x3000 <= r && 0x303F >= r) {
+			ret += 2
+		} else {
+			ret++
+		}
+	}
+	return
+}
+
+// chatListItemMarker 返回列表项在聊天消息中的缩进和标记，indentUnit 为每级缩进。
+func chatListItemMarker(listItem *ast.Node, indentUnit, bullet string) string {
+	depth := 0
+	for n := listItem.Parent; nil != n; n = n.Parent {
+		if ast.NodeList == n.Type {
+			depth++
+		}
+	}
+	indent := strings.Repeat(indentUnit, depth-1)
+	if 1 == listItem.ListData.Typ || (3 == listItem.ListData.Typ && 0 == listItem.ListData.BulletChar) {
+		return indent + strconv.Itoa(listItem.ListData.Num) + ". "
+	}
+	if 3 == listItem.ListData.Typ {
+		// 无序任务列表项由复选框代替项目符号
+		return indent
+	}
+	return indent + bullet + " "
+}
+
+// chatCheckbox 返回任务列表项复选框对应的字符。
+func chatCheckbox(marker *ast.Node) string {
+	if marker.TaskListItemChecked {
+		return "☑ "
+	}
+	return "☐ "
+}
+
+// chatQuote 为引述块内容的每一行加上前缀 prefix。
+func chatQuote(content []byte, prefix string) []byte {
+	lines := bytes.Split(content, []byte("\n"))
+	buf := &bytes.Buffer{}
+	for i, line := range lines {
+		if 0 < i {
+			buf.WriteByte('\n')
+		}
+		if 1 > len(line) {
+			buf.WriteString(strings.TrimRight(prefix, " "))
+			continue
+		}
+		buf.WriteString(prefix)
+		buf.Write(line)
+	}
+	return buf.Bytes()
+}
+
+// chatFootnotesDefNum 返回脚注定义的序号。
+func chatFootnotesDefNum(def *ast.Node) int {
+	num := 1
+	for prev := def.Previous; nil != prev; prev = prev.Previous {
+		num++
+	}
+	return num
+}
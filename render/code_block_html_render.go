@@ -39,7 +39,7 @@ func (r *HtmlRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkSt
 			rendered := false
 			tokens := node.FirstChild.Tokens
 			if r.Options.CodeSyntaxHighlight {
-				rendered = highlightChroma(node, tokens, "", r)
+				rendered = highlightChroma(node, tokens, "", nil, r)
 				if !rendered {
 					tokens = html.EscapeHTML(tokens)
 					r.Write(tokens)
@@ -77,6 +77,8 @@ func (r *HtmlRenderer) renderCodeBlockCode(node *ast.Node, entering bool) ast.Wa
 
 		tokens := node.Tokens
 		if 0 < len(node.Previous.CodeBlockInfo) {
+			info := parseCodeBlockInfo(node.Previous)
+			r.renderCodeBlockTitle(info)
 			rendered := false
 			if isGo(language) {
 				// Go 代码块自动格式化 https://github.com/b3log/lute/issues/37
@@ -94,7 +96,7 @@ func (r *HtmlRenderer) renderCodeBlockCode(node *ast.Node, entering bool) ast.Wa
 				rendered = true
 			} else {
 				if r.Options.CodeSyntaxHighlight && !preDiv {
-					rendered = highlightChroma(node.Parent, tokens, language, r)
+					rendered = highlightChroma(node.Parent, tokens, language, info, r)
 				}
 			}
 
@@ -102,7 +104,7 @@ func (r *HtmlRenderer) renderCodeBlockCode(node *ast.Node, entering bool) ast.Wa
 				if preDiv {
 					r.WriteString("<div class=\"language-")
 				} else {
-					r.Tag("pre", attrs, false)
+					r.Tag("pre", append(attrs, info.preAttrs()...), false)
 					r.WriteString("<code class=\"language-")
 				}
 				r.WriteString(language)
@@ -113,7 +115,7 @@ func (r *HtmlRenderer) renderCodeBlockCode(node *ast.Node, entering bool) ast.Wa
 		} else {
 			rendered := false
 			if r.Options.CodeSyntaxHighlight {
-				rendered = highlightChroma(node.Parent, tokens, "", r)
+				rendered = highlightChroma(node.Parent, tokens, "", nil, r)
 				if !rendered {
 					tokens = html.EscapeHTML(tokens)
 					r.Write(tokens)
@@ -144,12 +146,17 @@ func (r *HtmlRenderer) renderCodeBlockCode(node *ast.Node, entering bool) ast.Wa
 	return ast.WalkContinue
 }
 
-func highlightChroma(codeNode *ast.Node, tokens []byte, language string, r *HtmlRenderer) (rendered bool) {
+func highlightChroma(codeNode *ast.Node, tokens []byte, language string, info *codeBlockInfo, r *HtmlRenderer) (rendered bool) {
 	var attrs [][]string
 	r.handleKramdownBlockIAL(codeNode)
 	attrs = append(attrs, codeNode.KramdownIAL...)
 
 	codeBlock := util.BytesToStr(tokens)
+	var diffMarkers []byte
+	if nil != info && info.diff {
+		language = info.language
+		codeBlock, diffMarkers = splitDiffMarkers(codeBlock)
+	}
 	var lexer chroma.Lexer
 	if "" != language {
 		lexer = chromalexers.Get(language)
@@ -163,6 +170,9 @@ func highlightChroma(codeNode *ast.Node, tokens []byte, language string, r *Html
 	}
 	lexer = chroma.Coalesce(lexer)
 	iterator, err := lexer.Tokenise(nil, codeBlock)
+	if nil == err && nil != diffMarkers {
+		iterator = diffIterator(iterator, diffMarkers)
+	}
 	if nil == err {
 		chromahtmlOpts := []chromahtml.Option{
 			chromahtml.PreventSurroundingPre(true),
@@ -171,7 +181,26 @@ func highlightChroma(codeNode *ast.Node, tokens []byte, language string, r *Html
 		if !r.Options.CodeSyntaxHighlightInlineStyle {
 			chromahtmlOpts = append(chromahtmlOpts, chromahtml.WithClasses(true))
 		}
-		if r.Options.CodeSyntaxHighlightLineNum {
+		lineNum := r.Options.CodeSyntaxHighlightLineNum
+		if nil != info {
+			if 0 < info.lineStart {
+				lineNum = true
+				chromahtmlOpts = append(chromahtmlOpts, chromahtml.BaseLineNumber(info.lineStart))
+			}
+			if 0 < len(info.highlights) {
+				// 高亮行相对于代码块第一行，chroma 使用的是实际行号
+				offset := 0
+				if 0 < info.lineStart {
+					offset = info.lineStart - 1
+				}
+				var ranges [][2]int
+				for _, hl := range info.highlights {
+					ranges = append(ranges, [2]int{hl[0] + offset, hl[1] + offset})
+				}
+				chromahtmlOpts = append(chromahtmlOpts, chromahtml.HighlightLines(ranges))
+			}
+		}
+		if lineNum {
 			chromahtmlOpts = append(chromahtmlOpts, chromahtml.WithLineNumbers(true))
 		}
 		formatter := chromahtml.New(chromahtmlOpts...)
@@ -203,6 +232,43 @@ func highlightChroma(codeNode *ast.Node, tokens []byte, language string, r *Html
 	return
 }
 
+// splitDiffMarkers 去掉 diff 代码每行行首的 +、- 或者空格标记，markers 按行返回标记，没有标记的行为 0。
+func splitDiffMarkers(code string) (ret string, markers []byte) {
+	buf := &strings.Builder{}
+	for _, line := range strings.SplitAfter(code, "\n") {
+		if "" == line {
+			continue
+		}
+		if '+' == line[0] || '-' == line[0] || ' ' == line[0] {
+			markers = append(markers, line[0])
+			line = line[1:]
+		} else {
+			markers = append(markers, 0)
+		}
+		buf.WriteString(line)
+	}
+	return buf.String(), markers
+}
+
+// diffIterator 在高亮后的每行行首插入 diff 标记。
+func diffIterator(iterator chroma.Iterator, markers []byte) chroma.Iterator {
+	var tokens []chroma.Token
+	for i, line := range chroma.SplitTokensIntoLines(iterator.Tokens()) {
+		if i < len(markers) {
+			switch markers[i] {
+			case '+':
+				tokens = append(tokens, chroma.Token{Type: chroma.GenericInserted, Value: "+"})
+			case '-':
+				tokens = append(tokens, chroma.Token{Type: chroma.GenericDeleted, Value: "-"})
+			case ' ':
+				tokens = append(tokens, chroma.Token{Type: chroma.Text, Value: " "})
+			}
+		}
+		tokens = append(tokens, line...)
+	}
+	return chroma.Literator(tokens...)
+}
+
 func isGo(language string) bool {
 	return strings.EqualFold(language, "go") || strings.EqualFold(language, "golang")
 }
@@ -51,6 +51,11 @@ func (r *HtmlRenderer) renderCodeBlockCode(node *ast.Node, entering bool) ast.Wa
 		var attrs [][]string
 		r.handleKramdownBlockIAL(node)
 		attrs = append(attrs, node.KramdownIAL...)
+		if 0 < len(node.Previous.CodeBlockInfo) {
+			info := parseCodeBlockInfo(node.Previous)
+			r.renderCodeBlockTitle(info)
+			attrs = append(attrs, info.preAttrs()...)
+		}
 		if !preDiv {
 			r.Tag("pre", attrs, false)
 		}
@@ -0,0 +1,134 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/util"
+)
+
+// codeBlockInfo 描述了围栏代码块信息字符串中的属性，比如 ```diff-go {3,5-7} title="main.go" linenostart=10。
+type codeBlockInfo struct {
+	language   string   // 高亮使用的语言，diff-go 形式时为 go
+	diff       bool     // 是否为 diff-语言 形式，行首的 +、- 为差异标记
+	highlights [][2]int // 需要高亮的行，行号从代码块第一行为 1 开始计算
+	title      string   // 代码块标题
+	lineStart  int      // 起始行号，0 表示未设置
+}
+
+// parseCodeBlockInfo 解析代码块信息字符串节点 infoMarker 中的属性。
+func parseCodeBlockInfo(infoMarker *ast.Node) (ret *codeBlockInfo) {
+	ret = &codeBlockInfo{}
+	if words := strings.Fields(util.BytesToStr(infoMarker.CodeBlockInfo)); 0 < len(words) {
+		ret.language = words[0]
+	}
+	if lang := strings.TrimPrefix(ret.language, "diff-"); lang != ret.language && "" != lang {
+		ret.language = lang
+		ret.diff = true
+	}
+
+	attrs := strings.TrimSpace(util.BytesToStr(infoMarker.CodeBlockInfoAttrs))
+	for "" != attrs {
+		if strings.HasPrefix(attrs, "{") {
+			end := strings.IndexByte(attrs, '}')
+			if 0 > end {
+				break
+			}
+			ret.highlights = append(ret.highlights, parseLineRanges(attrs[1:end])...)
+			attrs = strings.TrimSpace(attrs[end+1:])
+			continue
+		}
+
+		key, value := attrs, ""
+		if idx := strings.IndexAny(attrs, "= "); 0 <= idx && '=' == attrs[idx] {
+			key, attrs = attrs[:idx], attrs[idx+1:]
+			if strings.HasPrefix(attrs, "\"") {
+				if end := strings.IndexByte(attrs[1:], '"'); 0 <= end {
+					value, attrs = attrs[1:end+1], attrs[end+2:]
+				} else {
+					value, attrs = attrs[1:], ""
+				}
+			} else {
+				value, attrs, _ = strings.Cut(attrs, " ")
+			}
+		} else {
+			key, attrs, _ = strings.Cut(attrs, " ")
+		}
+		attrs = strings.TrimSpace(attrs)
+		value = util.BytesToStr(html.UnescapeBytes([]byte(value))) // 属性部分保留的是原文
+
+		switch key {
+		case "title":
+			ret.title = value
+		case "linenostart":
+			if start, err := strconv.Atoi(value); nil == err && 0 < start {
+				ret.lineStart = start
+			}
+		}
+	}
+	sort.Slice(ret.highlights, func(i, j int) bool { return ret.highlights[i][0] < ret.highlights[j][0] })
+	return
+}
+
+// parseLineRanges 解析 3,5-7 形式的行号范围。
+func parseLineRanges(str string) (ret [][2]int) {
+	for _, part := range strings.Split(str, ",") {
+		from, to, found := strings.Cut(strings.TrimSpace(part), "-")
+		start, err := strconv.Atoi(from)
+		if nil != err {
+			continue
+		}
+		end := start
+		if found {
+			if end, err = strconv.Atoi(to); nil != err || end < start {
+				continue
+			}
+		}
+		ret = append(ret, [2]int{start, end})
+	}
+	return
+}
+
+// lineRangesStr 将行号范围转换为 3,5-7 形式。
+func lineRangesStr(ranges [][2]int) string {
+	var parts []string
+	for _, r := range ranges {
+		if r[0] == r[1] {
+			parts = append(parts, strconv.Itoa(r[0]))
+		} else {
+			parts = append(parts, strconv.Itoa(r[0])+"-"+strconv.Itoa(r[1]))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// preAttrs 返回未进行语法高亮时 <pre> 上的行高亮和起始行号属性，和 Prism 的 data-line、data-start 约定一致。
+func (info *codeBlockInfo) preAttrs() (ret [][]string) {
+	if 0 < len(info.highlights) {
+		ret = append(ret, []string{"data-line", lineRangesStr(info.highlights)})
+	}
+	if 0 < info.lineStart {
+		ret = append(ret, []string{"data-start", strconv.Itoa(info.lineStart)})
+	}
+	return
+}
+
+// renderCodeBlockTitle 渲染代码块标题。
+func (r *HtmlRenderer) renderCodeBlockTitle(info *codeBlockInfo) {
+	if "" != info.title {
+		r.WriteString("<div class=\"code-block-title\">" + html.EscapeHTMLStr(info.title) + "</div>")
+	}
+}
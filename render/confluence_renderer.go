@@ -0,0 +1,544 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/lex"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+// ConfluenceRenderer 描述了 Confluence 存储格式（XHTML）渲染器。
+//
+// 代码块使用 code 宏，GitHub 风格的提示块 > [!NOTE] 使用 info、tip、note 和 warning 面板宏。
+type ConfluenceRenderer struct {
+	*BaseRenderer
+	alertMarker *ast.Node // 当前提示块的 [!TYPE] 标记文本节点
+}
+
+// NewConfluenceRenderer 创建一个 Confluence 存储格式渲染器。
+func NewConfluenceRenderer(tree *parse.Tree, options *Options) Renderer {
+	ret := &ConfluenceRenderer{BaseRenderer: NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderText
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeSpan] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeInlineMath] = ret.renderInlineMath
+	ret.RendererFuncs[ast.NodeCodeBlock] = ret.renderCodeBlock
+	ret.RendererFuncs[ast.NodeMathBlock] = ret.renderMathBlock
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeEmphasis] = ret.renderEmphasis
+	ret.RendererFuncs[ast.NodeStrong] = ret.renderStrong
+	ret.RendererFuncs[ast.NodeStrikethrough] = ret.renderStrikethrough
+	ret.RendererFuncs[ast.NodeUnderline] = ret.renderUnderline
+	ret.RendererFuncs[ast.NodeSup] = ret.renderSup
+	ret.RendererFuncs[ast.NodeSub] = ret.renderSub
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderSoftBreak
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeTableRow] = ret.renderTableRow
+	ret.RendererFuncs[ast.NodeTableCell] = ret.renderTableCell
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderEmojiUnicode
+	ret.RendererFuncs[ast.NodeEmojiImg] = ret.renderEmojiImg
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeFootnotesDefBlock] = ret.renderFootnotesDefBlock
+	ret.RendererFuncs[ast.NodeFootnotesDef] = ret.renderFootnotesDef
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+func (r *ConfluenceRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *ConfluenceRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		buf := bytes.TrimSpace(r.Writer.Bytes())
+		r.Writer.Reset()
+		r.Write(buf)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if inTightListItem(node) {
+		return ast.WalkContinue
+	}
+	if entering {
+		r.Tag("p", nil, false)
+	} else {
+		r.Tag("/p", nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		tokens := node.Tokens
+		if node == r.alertMarker {
+			tokens = alertMarkerRemains(node)
+		} else if nil != node.Previous && ast.NodeTaskListItemMarker == node.Previous.Type {
+			tokens = bytes.TrimLeft(tokens, " ")
+		}
+		r.Write(html.EscapeHTML(tokens))
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderCodeSpan(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Tag("code", nil, false)
+		if content := node.ChildByType(ast.NodeCodeSpanContent); nil != content {
+			r.Write(html.EscapeHTML(content.Tokens))
+		}
+		r.Tag("/code", nil, false)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ConfluenceRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Tag("code", nil, false)
+		if content := node.ChildByType(ast.NodeInlineMathContent); nil != content {
+			r.Write(html.EscapeHTML(content.Tokens))
+		}
+		r.Tag("/code", nil, false)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ConfluenceRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		lang, code := codeBlockLangCode(node)
+		r.macro("code", lang, code)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ConfluenceRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var code []byte
+		if content := node.ChildByType(ast.NodeMathBlockContent); nil != content {
+			code = content.Tokens
+		}
+		r.macro("code", "", code)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ConfluenceRenderer) renderHTMLBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.macro("html", "", node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+// macro 输出 plain-text-body 类型的宏，比如 code 和 html 宏。
+func (r *ConfluenceRenderer) macro(name, lang string, body []byte) {
+	r.Newline()
+	r.WriteString("<ac:structured-macro ac:name=\"" + name + "\">")
+	if "" != lang {
+		r.WriteString("<ac:parameter ac:name=\"language\">" + util.BytesToStr(html.EscapeHTML(util.StrToBytes(lang))) + "</ac:parameter>")
+	}
+	r.WriteString("<ac:plain-text-body><![CDATA[")
+	body = bytes.TrimRight(body, "\n")
+	r.Write(bytes.ReplaceAll(body, []byte("]]>"), []byte("]]]]><![CDATA[>")))
+	r.WriteString("]]></ac:plain-text-body></ac:structured-macro>")
+	r.Newline()
+}
+
+func (r *ConfluenceRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("em", entering)
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("strong", entering)
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderStrikethrough(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("s", entering)
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderUnderline(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("u", entering)
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderSup(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("sup", entering)
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderSub(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("sub", entering)
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) inlineTag(name string, entering bool) {
+	if entering {
+		r.Tag(name, nil, false)
+	} else {
+		r.Tag("/"+name, nil, false)
+	}
+}
+
+func (r *ConfluenceRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	typ, marker := blockquoteAlert(node)
+	panel := ""
+	switch typ {
+	case "note", "info":
+		panel = "info"
+	case "tip":
+		panel = "tip"
+	case "important":
+		panel = "note"
+	case "warning", "caution":
+		panel = "warning"
+	}
+
+	r.Newline()
+	if entering {
+		if "" == panel {
+			r.Tag("blockquote", nil, false)
+		} else {
+			r.alertMarker = marker
+			r.WriteString("<ac:structured-macro ac:name=\"" + panel + "\"><ac:rich-text-body>")
+		}
+	} else {
+		if "" == panel {
+			r.Tag("/blockquote", nil, false)
+		} else {
+			r.alertMarker = nil
+			r.WriteString("</ac:rich-text-body></ac:structured-macro>")
+		}
+	}
+	r.Newline()
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.Tag("h"+headingLevel[node.HeadingLevel:node.HeadingLevel+1], nil, false)
+	} else {
+		r.Tag("/h"+headingLevel[node.HeadingLevel:node.HeadingLevel+1], nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	tag := "ul"
+	var attrs [][]string
+	switch node.ListData.Typ {
+	case 1:
+		tag = "ol"
+		if 1 != node.ListData.Start {
+			attrs = append(attrs, []string{"start", strconv.Itoa(node.ListData.Start)})
+		}
+	case 3:
+		tag = "ac:task-list"
+	}
+	r.Newline()
+	if entering {
+		r.Tag(tag, attrs, false)
+	} else {
+		r.Tag("/"+tag, nil, false)
+	}
+	r.Newline()
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	if 3 == node.ListData.Typ {
+		if entering {
+			status := "incomplete"
+			if marker := taskListItemMarker(node); nil != marker && marker.TaskListItemChecked {
+				status = "complete"
+			}
+			r.WriteString("<ac:task><ac:task-status>" + status + "</ac:task-status><ac:task-body>")
+		} else {
+			r.WriteString("</ac:task-body></ac:task>")
+			r.Newline()
+		}
+		return ast.WalkContinue
+	}
+
+	if entering {
+		r.Tag("li", nil, false)
+	} else {
+		r.Tag("/li", nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.Tag("hr", nil, true)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderHardBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Tag("br", nil, true)
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderSoftBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if nil != r.alertMarker && node.Previous == r.alertMarker && 1 > len(alertMarkerRemains(r.alertMarker)) {
+			return ast.WalkContinue
+		}
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest := linkDest(r.Tree, node)
+		r.Tag("a", [][]string{{"href", util.BytesToStr(html.EscapeHTML(r.resolveLinkPath(dest, LinkKindLink)))}}, false)
+	} else {
+		r.Tag("/a", nil, false)
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest := util.BytesToStr(r.resolveLinkPath(linkDest(r.Tree, node), LinkKindImage))
+		var attrs [][]string
+		if alt := node.ChildByType(ast.NodeLinkText); nil != alt && 0 < len(alt.Tokens) {
+			attrs = append(attrs, []string{"ac:alt", util.BytesToStr(html.EscapeHTML(alt.Tokens))})
+		}
+		r.Tag("ac:image", attrs, false)
+		if strings.Contains(dest, "://") {
+			r.Tag("ri:url", [][]string{{"ri:value", util.BytesToStr(html.EscapeHTML(util.StrToBytes(dest)))}}, true)
+		} else {
+			// 本地图片作为页面附件引用
+			r.Tag("ri:attachment", [][]string{{"ri:filename", util.BytesToStr(html.EscapeHTML(util.StrToBytes(path.Base(dest))))}}, true)
+		}
+		r.Tag("/ac:image", nil, false)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ConfluenceRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	r.Newline()
+	if entering {
+		r.Tag("table", nil, false)
+		r.Tag("tbody", nil, false)
+	} else {
+		r.Tag("/tbody", nil, false)
+		r.Tag("/table", nil, false)
+	}
+	r.Newline()
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderTableRow(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Tag("tr", nil, false)
+	} else {
+		r.Tag("/tr", nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderTableCell(node *ast.Node, entering bool) ast.WalkStatus {
+	tag := "td"
+	if ast.NodeTableHead == node.Parent.Parent.Type {
+		tag = "th"
+	}
+	if entering {
+		var attrs [][]string
+		switch node.TableCellAlign {
+		case 1:
+			attrs = append(attrs, []string{"style", "text-align: left;"})
+		case 2:
+			attrs = append(attrs, []string{"style", "text-align: center;"})
+		case 3:
+			attrs = append(attrs, []string{"style", "text-align: right;"})
+		}
+		r.Tag(tag, attrs, false)
+	} else {
+		r.Tag("/"+tag, nil, false)
+	}
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderEmojiUnicode(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ConfluenceRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if alias := node.ChildByType(ast.NodeEmojiAlias); nil != alias {
+			r.Write(html.EscapeHTML(alias.Tokens))
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ConfluenceRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("<sup>" + strconv.Itoa(footnotesRefNum(node)) + "</sup>")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ConfluenceRenderer) renderFootnotesDefBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	r.Newline()
+	if entering {
+		r.Tag("hr", nil, true)
+		r.Newline()
+		r.Tag("ol", nil, false)
+	} else {
+		r.Tag("/ol", nil, false)
+	}
+	r.Newline()
+	return ast.WalkContinue
+}
+
+func (r *ConfluenceRenderer) renderFootnotesDef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Tag("li", nil, false)
+	} else {
+		r.Tag("/li", nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+// blockquoteAlert 识别 GitHub 风格的提示块 > [!NOTE]，返回小写的提示类型和 [!TYPE] 标记所在的文本节点。
+func blockquoteAlert(blockquote *ast.Node) (typ string, marker *ast.Node) {
+	p := blockquote.FirstChild
+	for nil != p && ast.NodeBlockquoteMarker == p.Type {
+		p = p.Next
+	}
+	if nil == p || ast.NodeParagraph != p.Type || nil == p.FirstChild || ast.NodeText != p.FirstChild.Type {
+		return
+	}
+
+	text := util.BytesToStr(p.FirstChild.Tokens)
+	if !strings.HasPrefix(text, "[!") {
+		return
+	}
+	end := strings.Index(text, "]")
+	if 0 > end {
+		return
+	}
+	switch t := strings.ToLower(text[2:end]); t {
+	case "note", "info", "tip", "important", "warning", "caution":
+		return t, p.FirstChild
+	}
+	return
+}
+
+// alertMarkerRemains 返回提示块标记文本节点中 [!TYPE] 之后的内容。
+func alertMarkerRemains(marker *ast.Node) []byte {
+	tokens := marker.Tokens[bytes.IndexByte(marker.Tokens, lex.ItemCloseBracket)+1:]
+	return bytes.TrimLeft(tokens, " ")
+}
+
+// inTightListItem 判断块是否是紧凑列表项的直接子节点。
+func inTightListItem(node *ast.Node) bool {
+	parent := node.Parent
+	if nil == parent || ast.NodeListItem != parent.Type {
+		return false
+	}
+	return nil != parent.Parent && nil != parent.Parent.ListData && parent.Parent.ListData.Tight
+}
+
+// taskListItemMarker 返回任务列表项的复选框标记节点。
+func taskListItemMarker(listItem *ast.Node) *ast.Node {
+	if p := listItem.FirstChild; nil != p && nil != p.FirstChild && ast.NodeTaskListItemMarker == p.FirstChild.Type {
+		return p.FirstChild
+	}
+	return nil
+}
+
+// codeBlockLangCode 返回代码块的语言和代码。
+func codeBlockLangCode(node *ast.Node) (lang string, code []byte) {
+	if !node.IsFencedCodeBlock {
+		if nil != node.FirstChild {
+			code = node.FirstChild.Tokens
+		}
+		return
+	}
+	if c := node.ChildByType(ast.NodeCodeBlockCode); nil != c {
+		code = c.Tokens
+	}
+	if info := strings.Fields(util.BytesToStr(node.CodeBlockInfo)); 0 < len(info) {
+		lang = info[0]
+	}
+	return
+}
+
+// linkDest 返回链接或者图片的地址，引用链接会从链接引用定义中查找地址。
+func linkDest(tree *parse.Tree, node *ast.Node) []byte {
+	if 3 == node.LinkType {
+		if def := tree.FindLinkRefDefLink(node.LinkRefLabel); nil != def {
+			node = def
+		}
+	}
+	if dest := node.ChildByType(ast.NodeLinkDest); nil != dest {
+		return dest.Tokens
+	}
+	return nil
+}
+
+// footnotesRefNum 返回脚注引用的序号。
+func footnotesRefNum(node *ast.Node) int {
+	num, _ := strconv.Atoi(node.FootnotesRefId)
+	return num
+}
@@ -0,0 +1,376 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/lex"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+// DiscordRenderer 描述了 Discord 消息渲染器。
+type DiscordRenderer struct {
+	*BaseRenderer
+	NodeWriterStack []*bytes.Buffer // 节点输出缓冲栈
+}
+
+// NewDiscordRenderer 创建一个 Discord 消息渲染器。
+func NewDiscordRenderer(tree *parse.Tree, options *Options) Renderer {
+	ret := &DiscordRenderer{BaseRenderer: NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderText
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeSpan] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeInlineMath] = ret.renderInlineMath
+	ret.RendererFuncs[ast.NodeCodeBlock] = ret.renderCodeBlock
+	ret.RendererFuncs[ast.NodeMathBlock] = ret.renderMathBlock
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeEmphasis] = ret.renderEmphasis
+	ret.RendererFuncs[ast.NodeStrong] = ret.renderStrong
+	ret.RendererFuncs[ast.NodeStrikethrough] = ret.renderStrikethrough
+	ret.RendererFuncs[ast.NodeUnderline] = ret.renderUnderline
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeTaskListItemMarker] = ret.renderTaskListItemMarker
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderBreak
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderEmojiUnicode
+	ret.RendererFuncs[ast.NodeEmojiImg] = ret.renderEmojiImg
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeFootnotesDefBlock] = ret.renderFootnotesDefBlock
+	ret.RendererFuncs[ast.NodeFootnotesDef] = ret.renderFootnotesDef
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+func (r *DiscordRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+	} else {
+		buf := bytes.TrimSpace(r.Writer.Bytes())
+		r.Writer.Reset()
+		r.Write(buf)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) pushWriter() {
+	r.Writer = &bytes.Buffer{}
+	r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+}
+
+func (r *DiscordRenderer) popWriter() (ret []byte) {
+	writer := r.NodeWriterStack[len(r.NodeWriterStack)-1]
+	r.NodeWriterStack = r.NodeWriterStack[:len(r.NodeWriterStack)-1]
+	r.Writer = r.NodeWriterStack[len(r.NodeWriterStack)-1]
+	return bytes.TrimRight(writer.Bytes(), " \t\n")
+}
+
+// codeBlock 输出 Discord 代码块，lang 为空时不指定代码语言。
+func (r *DiscordRenderer) codeBlock(node *ast.Node, lang string, code []byte) {
+	r.Newline()
+	r.WriteString("```" + lang + "\n")
+	r.Write(bytes.Trim(code, "\n"))
+	r.WriteString("\n```")
+	r.blockEnd(node)
+}
+
+func (r *DiscordRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(discordEscape(node.Tokens, lex.ItemNewline == r.LastOut))
+	}
+	return ast.WalkContinue
+}
+
+// discordEscape 使用反斜杠转义 Discord 格式字符，lineStart 为 true 时还会转义行首的引述、标题和列表标记。
+func discordEscape(tokens []byte, lineStart bool) []byte {
+	ret := make([]byte, 0, len(tokens)+8)
+	for i, c := range tokens {
+		switch c {
+		case '\\', '*', '_', '~', '`', '|':
+			ret = append(ret, '\\')
+		case '>', '#', '-':
+			if 0 == i && lineStart {
+				ret = append(ret, '\\')
+			}
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+func (r *DiscordRenderer) renderCodeSpan(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var code []byte
+		if content := node.ChildByType(ast.NodeCodeSpanContent); nil != content {
+			code = content.Tokens
+		}
+		r.Write(codeSpan(code))
+	}
+	return ast.WalkSkipChildren
+}
+
+// codeSpan 返回使用反引号包裹 code 的行内代码，反引号串长于 code 中最长的连续反引号，code 首尾是反引号时使用空格隔开。
+func codeSpan(code []byte) (ret []byte) {
+	fence := bytes.Repeat([]byte("`"), maxBacktickRun(code)+1)
+	ret = append(ret, fence...)
+	padding := 0 < len(code) && ('`' == code[0] || '`' == code[len(code)-1])
+	if padding {
+		ret = append(ret, ' ')
+	}
+	ret = append(ret, code...)
+	if padding {
+		ret = append(ret, ' ')
+	}
+	return append(ret, fence...)
+}
+
+// maxBacktickRun 返回 code 中最长的连续反引号的长度。
+func maxBacktickRun(code []byte) (ret int) {
+	for ticks, i := 0, 0; i < len(code); i++ {
+		if '`' != code[i] {
+			ticks = 0
+			continue
+		}
+		if ticks++; ticks > ret {
+			ret = ticks
+		}
+	}
+	return
+}
+
+func (r *DiscordRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteByte('`')
+		if content := node.ChildByType(ast.NodeInlineMathContent); nil != content {
+			r.Write(content.Tokens)
+		}
+		r.WriteByte('`')
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		lang, code := codeBlockLangCode(node)
+		r.codeBlock(node, lang, code)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var code []byte
+		if content := node.ChildByType(ast.NodeMathBlockContent); nil != content {
+			code = content.Tokens
+		}
+		r.codeBlock(node, "latex", code)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderHTMLBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.codeBlock(node, "html", node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('*')
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteString("**")
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderStrikethrough(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteString("~~")
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderUnderline(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteString("__")
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.pushWriter()
+	} else {
+		content := r.popWriter()
+		r.Newline()
+		r.Write(chatQuote(content, "> "))
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("**" + util.BytesToStr(discordEscape([]byte(plainText(node)), false)) + "**")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering && !node.ParentIs(ast.NodeListItem) {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString(chatListItemMarker(node, "  ", "-"))
+	}
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderTaskListItemMarker(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString(chatCheckbox(node))
+		if next := node.Next; nil != next && ast.NodeText == next.Type {
+			next.Tokens = bytes.TrimLeft(next.Tokens, " ")
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("──────────")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	dest := util.BytesToStr(r.resolveLinkPath(linkDest(r.Tree, node), LinkKindLink))
+	if 2 == node.LinkType {
+		if entering {
+			r.WriteString(dest)
+		}
+		return ast.WalkSkipChildren
+	}
+	if entering {
+		r.WriteByte('[')
+	} else {
+		r.WriteString("](" + dest + ")")
+	}
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest := util.BytesToStr(r.resolveLinkPath(linkDest(r.Tree, node), LinkKindImage))
+		if alt := plainText(node); "" != alt {
+			r.WriteString("[" + util.BytesToStr(discordEscape([]byte(alt), false)) + "](" + dest + ")")
+		} else {
+			r.WriteString(dest)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.codeBlock(node, "", []byte(monospaceTable(node)))
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderEmojiUnicode(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if alias := node.ChildByType(ast.NodeEmojiAlias); nil != alias {
+			r.Write(alias.Tokens)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("[" + node.FootnotesRefId + "]")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DiscordRenderer) renderFootnotesDefBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("──────────")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *DiscordRenderer) renderFootnotesDef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("[" + strconv.Itoa(chatFootnotesDefNum(node)) + "] ")
+	}
+	return ast.WalkContinue
+}
@@ -0,0 +1,294 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/util"
+)
+
+var (
+	// crossRefLabel 匹配段落末尾的 {#fig:label} 标签。
+	crossRefLabel = regexp.MustCompile(`\s*\{#((?:fig|tbl|eq):[\p{L}\p{N}_-]+)\}\s*$`)
+	// crossRef 匹配 @fig:label 形式的交叉引用。
+	crossRef = regexp.MustCompile(`@((?:fig|tbl|eq):[\p{L}\p{N}_-]+)`)
+)
+
+// tableCaptionPrefix 是 Pandoc 风格的表格标题前缀。
+const tableCaptionPrefix = "Table:"
+
+// crossRefs 描述了图片、表格和公式的编号以及标签对应的引用文本。
+type crossRefs struct {
+	numbers map[*ast.Node]int // 节点编号
+	labels  map[string]string // 标签对应的引用文本，比如 "fig:arch" 对应 "Figure 3"
+}
+
+// crossRefNumbers 返回文档中图片、表格和公式的编号，首次调用时按文档顺序生成。
+func (r *HtmlRenderer) crossRefNumbers() *crossRefs {
+	if nil != r.crossRefs {
+		return r.crossRefs
+	}
+
+	r.crossRefs = &crossRefs{numbers: map[*ast.Node]int{}, labels: map[string]string{}}
+	var figures, tables, equations int
+	add := func(node *ast.Node, label, kind string, number int) {
+		r.crossRefs.numbers[node] = number
+		if "" != label {
+			r.crossRefs.labels[label] = kind + " " + strconv.Itoa(number)
+		}
+	}
+	ast.Walk(r.Tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+		switch n.Type {
+		case ast.NodeParagraph:
+			if !r.Options.Figure || inTightListItem(n) {
+				// 紧凑列表项中的段落不会渲染为 figure
+				return ast.WalkSkipChildren
+			}
+			if img, label := figureImage(n); nil != img {
+				figures++
+				add(n, label, "Figure", figures)
+			}
+			return ast.WalkSkipChildren
+		case ast.NodeTable:
+			if !r.Options.Figure {
+				// 未开启 Figure 时不会输出表格编号
+				return ast.WalkSkipChildren
+			}
+			label := crossRefIALLabel(n, "tbl:")
+			caption := tableCaption(n)
+			if nil != caption && "" == label {
+				label = captionLabel(caption)
+			}
+			if nil != caption || "" != label {
+				tables++
+				add(n, label, "Table", tables)
+			}
+			return ast.WalkSkipChildren
+		case ast.NodeMathBlock:
+			if label := crossRefIALLabel(n, "eq:"); "" != label {
+				equations++
+				add(n, label, "Equation", equations)
+			}
+			return ast.WalkSkipChildren
+		}
+		return ast.WalkContinue
+	})
+	return r.crossRefs
+}
+
+// equationNumber 返回公式块 mathBlock 的编号，未开启 FigureNumbering 或者公式块没有 eq: 标签时返回 0。
+func (r *HtmlRenderer) equationNumber(mathBlock *ast.Node) int {
+	if !r.Options.FigureNumbering {
+		return 0
+	}
+	return r.crossRefNumbers().numbers[mathBlock]
+}
+
+// renderEquationNumber 在公式块后渲染可见的 (n) 编号，并闭合 renderMathBlock 中打开的 equation 元素。
+func (r *HtmlRenderer) renderEquationNumber(mathBlock *ast.Node) {
+	if n := r.equationNumber(mathBlock); 0 < n {
+		r.WriteString("<span class=\"equation-number\">(" + strconv.Itoa(n) + ")</span>")
+		r.Tag("/div", nil, false)
+	}
+}
+
+// crossRefIALLabel 返回节点 IAL 中以 prefix 开头的 id。
+func crossRefIALLabel(node *ast.Node, prefix string) string {
+	if id := node.IALAttr("id"); strings.HasPrefix(id, prefix) {
+		return id
+	}
+	return ""
+}
+
+// figureImage 判断段落是否仅包含一张图片（以及可选的 {#fig:label} 标签），是的话返回该图片和标签。
+func figureImage(paragraph *ast.Node) (img *ast.Node, label string) {
+	for c := paragraph.FirstChild; nil != c; c = c.Next {
+		switch c.Type {
+		case ast.NodeImage:
+			if nil != img {
+				return nil, ""
+			}
+			img = c
+		case ast.NodeKramdownSpanIAL, ast.NodeSoftBreak:
+		case ast.NodeText:
+			text := util.BytesToStr(c.Tokens)
+			if "" == strings.TrimSpace(text) {
+				continue
+			}
+			if nil != img && nil == c.Next {
+				if m := crossRefLabel.FindStringSubmatchIndex(text); nil != m && "" == strings.TrimSpace(text[:m[0]]) {
+					label = text[m[2]:m[3]]
+					continue
+				}
+			}
+			return nil, ""
+		default:
+			return nil, ""
+		}
+	}
+	if nil == img {
+		return
+	}
+	if "" == label {
+		if label = crossRefIALLabel(paragraph, "fig:"); "" == label {
+			label = crossRefIALLabel(img, "fig:")
+		}
+	}
+	return
+}
+
+// isTableCaption 判断段落是否为紧邻表格的 Table: 标题段落。
+func isTableCaption(paragraph *ast.Node) bool {
+	if !isTableCaptionParagraph(paragraph) {
+		return false
+	}
+	if next := paragraph.Next; nil != next && ast.NodeTable == next.Type && paragraph == tableCaption(next) {
+		return true
+	}
+	if previous := paragraph.Previous; nil != previous && ast.NodeTable == previous.Type && paragraph == tableCaption(previous) {
+		return true
+	}
+	return false
+}
+
+// tableCaption 返回表格的标题段落，优先使用表格后的段落。
+//
+// 两个表格之间的标题段落属于前一个表格，每个标题段落只会作为一个表格的标题。
+func tableCaption(table *ast.Node) *ast.Node {
+	if next := table.Next; isTableCaptionParagraph(next) {
+		return next
+	}
+	if previous := table.Previous; isTableCaptionParagraph(previous) && (nil == previous.Previous || ast.NodeTable != previous.Previous.Type) {
+		return previous
+	}
+	return nil
+}
+
+// isTableCaptionParagraph 判断节点是否为以 Table: 开头的段落。
+func isTableCaptionParagraph(node *ast.Node) bool {
+	return nil != node && ast.NodeParagraph == node.Type && nil != node.FirstChild && ast.NodeText == node.FirstChild.Type &&
+		bytes.HasPrefix(node.FirstChild.Tokens, []byte(tableCaptionPrefix))
+}
+
+// captionLabel 返回标题段落末尾的 {#tbl:label} 标签。
+func captionLabel(caption *ast.Node) string {
+	if last := caption.LastChild; ast.NodeText == last.Type {
+		if m := crossRefLabel.FindSubmatch(last.Tokens); nil != m {
+			return string(m[1])
+		}
+	}
+	return ""
+}
+
+// renderFigure 将独立成段的图片渲染为 figure，图片标题来自 title 或者 alt。
+func (r *HtmlRenderer) renderFigure(paragraph, img *ast.Node, label string) {
+	r.Newline()
+	r.handleKramdownBlockIAL(paragraph)
+	var attrs [][]string
+	attrs = append(attrs, paragraph.KramdownIAL...)
+	if "" != label && "" == paragraph.IALAttr("id") {
+		attrs = append(attrs, []string{"id", label})
+	}
+	r.Tag("figure", attrs, false)
+	r.renderNode(img)
+
+	var caption string
+	if title := img.ChildByType(ast.NodeLinkTitle); nil != title && 0 < len(title.Tokens) {
+		caption = util.BytesToStr(title.Tokens)
+	} else {
+		caption = img.Text()
+	}
+	var number string
+	if r.Options.FigureNumbering {
+		number = "Figure " + strconv.Itoa(r.crossRefNumbers().numbers[paragraph])
+	}
+	if "" != caption || "" != number {
+		r.WriteString("<figcaption>")
+		if "" != number {
+			r.WriteString(number)
+			if "" != caption {
+				r.WriteString(": ")
+			}
+		}
+		r.Write(html.EscapeHTML(util.StrToBytes(caption)))
+		r.WriteString("</figcaption>")
+	}
+	r.Tag("/figure", nil, false)
+	r.Newline()
+}
+
+// renderTableCaption 渲染表格的 caption 元素。
+func (r *HtmlRenderer) renderTableCaption(table *ast.Node) {
+	caption := tableCaption(table)
+	var number string
+	if r.Options.FigureNumbering {
+		if n := r.crossRefNumbers().numbers[table]; 0 < n {
+			number = "Table " + strconv.Itoa(n)
+		}
+	}
+	if nil == caption && "" == number {
+		return
+	}
+
+	r.WriteString("<caption>")
+	if "" != number {
+		r.WriteString(number)
+		if nil != caption {
+			r.WriteString(": ")
+		}
+	}
+	if nil != caption {
+		for c := caption.FirstChild; nil != c; c = c.Next {
+			if ast.NodeText != c.Type || (c != caption.FirstChild && c != caption.LastChild) {
+				r.renderNode(c)
+				continue
+			}
+			tokens := c.Tokens
+			if c == caption.FirstChild {
+				tokens = bytes.TrimLeft(tokens[len(tableCaptionPrefix):], " ")
+			}
+			if c == caption.LastChild {
+				if m := crossRefLabel.FindIndex(tokens); nil != m {
+					tokens = tokens[:m[0]]
+				}
+			}
+			r.Write(html.EscapeHTML(tokens))
+		}
+	}
+	r.WriteString("</caption>")
+	r.Newline()
+}
+
+// renderCrossRefs 将文本中的 @fig:label 交叉引用渲染为指向对应图片、表格或者公式的链接，无法解析的引用保持原样。
+func (r *HtmlRenderer) renderCrossRefs(tokens []byte) {
+	labels := r.crossRefNumbers().labels
+	last := 0
+	for _, m := range crossRef.FindAllSubmatchIndex(tokens, -1) {
+		label := string(tokens[m[2]:m[3]])
+		number, ok := labels[label]
+		if !ok {
+			continue
+		}
+		r.Write(html.EscapeHTML(tokens[last:m[0]]))
+		r.WriteString("<a href=\"#" + html.EscapeHTMLStr(label) + "\" class=\"cross-ref\">" + number + "</a>")
+		last = m[1]
+	}
+	r.Write(html.EscapeHTML(tokens[last:]))
+}
@@ -276,7 +276,7 @@ func (r *FormatRenderer) renderTextMarkAttrs(node *ast.Node) (attrs [][]string)
 			attrs = append(attrs, []string{"data-id", node.TextMarkBlockRefID})
 		} else if "a" == typ {
 			href := node.TextMarkAHref
-			href = string(r.LinkPath([]byte(href)))
+			href = string(r.resolveLinkPath([]byte(href), LinkKindLink))
 
 			if node.ParentIs(ast.NodeTableCell) {
 				href = strings.ReplaceAll(href, "\\|", "|")
@@ -360,9 +360,14 @@ func (r *FormatRenderer) renderKbdCloseMarker(node *ast.Node, entering bool) ast
 func (r *FormatRenderer) renderVideo(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.Newline()
-		tokens := node.Tokens
-		tokens = r.tagSrcPath(tokens)
-		r.Write(tokens)
+		if 0 < len(node.MediaEmbedLink) {
+			// 自动嵌入的媒体还原为原始链接
+			r.Write(node.MediaEmbedLink)
+		} else {
+			tokens := node.Tokens
+			tokens = r.tagSrcPath(tokens)
+			r.Write(tokens)
+		}
 		r.Newline()
 		if !r.isLastNode(r.Tree.Root, node) {
 			r.WriteByte(lex.ItemNewline)
@@ -374,9 +379,14 @@ func (r *FormatRenderer) renderVideo(node *ast.Node, entering bool) ast.WalkStat
 func (r *FormatRenderer) renderAudio(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.Newline()
-		tokens := node.Tokens
-		tokens = r.tagSrcPath(tokens)
-		r.Write(tokens)
+		if 0 < len(node.MediaEmbedLink) {
+			// 自动嵌入的媒体还原为原始链接
+			r.Write(node.MediaEmbedLink)
+		} else {
+			tokens := node.Tokens
+			tokens = r.tagSrcPath(tokens)
+			r.Write(tokens)
+		}
 		r.Newline()
 		if !r.isLastNode(r.Tree.Root, node) {
 			r.WriteByte(lex.ItemNewline)
@@ -388,9 +398,14 @@ func (r *FormatRenderer) renderAudio(node *ast.Node, entering bool) ast.WalkStat
 func (r *FormatRenderer) renderIFrame(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.Newline()
-		tokens := node.Tokens
-		tokens = r.tagSrcPath(tokens)
-		r.Write(tokens)
+		if 0 < len(node.MediaEmbedLink) {
+			// 自动嵌入的媒体还原为原始链接
+			r.Write(node.MediaEmbedLink)
+		} else {
+			tokens := node.Tokens
+			tokens = r.tagSrcPath(tokens)
+			r.Write(tokens)
+		}
 		r.Newline()
 		if !r.isLastNode(r.Tree.Root, node) {
 			r.WriteByte(lex.ItemNewline)
@@ -947,6 +962,14 @@ func (r *FormatRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStat
 		for col := 0; col < len(cells[0]); col++ {
 			for row := 0; row < len(cells) && col < len(cells[row]); row++ {
 				cells[row][col].TableCellContentWidth = cells[row][col].TokenLen()
+				// 自定义自动链接规则生成的链接格式化时仅输出链接文本
+				ast.Walk(cells[row][col], func(n *ast.Node, entering bool) ast.WalkStatus {
+					if entering && ast.NodeLink == n.Type && "" != n.LinkSubtype {
+						cells[row][col].TableCellContentWidth -= n.TokenLen() - lex.BytesShowLength(n.ChildByType(ast.NodeLinkText).Tokens)
+						return ast.WalkSkipChildren
+					}
+					return ast.WalkContinue
+				})
 				// 自动添加空格会导致单元格宽度发生变化
 				if r.Options.AutoSpace {
 					ret := 0
@@ -1030,7 +1053,7 @@ func (r *FormatRenderer) renderLinkTitle(node *ast.Node, entering bool) ast.Walk
 func (r *FormatRenderer) renderLinkDest(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		tokens := node.Tokens
-		tokens = r.LinkPath(tokens)
+		tokens = r.resolveLinkPath(tokens, linkDestKind(node))
 		r.Write(tokens)
 	}
 	return ast.WalkContinue
@@ -1140,6 +1163,11 @@ func (r *FormatRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatu
 			r.Write(dest)
 			return ast.WalkSkipChildren
 		}
+		if "" != node.LinkSubtype {
+			// 自定义自动链接规则生成的链接保持原文
+			r.Write(node.ChildByType(ast.NodeLinkText).Tokens)
+			return ast.WalkSkipChildren
+		}
 	} else {
 		r.LinkTextAutoSpaceNext(node)
 	}
@@ -1429,6 +1457,7 @@ func (r *FormatRenderer) renderCodeBlockCode(node *ast.Node, entering bool) ast.
 func (r *FormatRenderer) renderCodeBlockInfoMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.Write(node.CodeBlockInfo)
+		r.Write(node.CodeBlockInfoAttrs)
 		r.WriteByte(lex.ItemNewline)
 	}
 	return ast.WalkContinue
@@ -0,0 +1,71 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/parse"
+)
+
+// FreeMindRenderer 描述了 FreeMind 脑图（.mm）渲染器。
+//
+// 标题层级和嵌套列表转换为嵌套的 node，备注转换为 NOTE 类型的 richcontent，任务列表项使用 button_ok、button_cancel 图标。
+type FreeMindRenderer struct {
+	*BaseRenderer
+}
+
+// NewFreeMindRenderer 创建一个 FreeMind 脑图渲染器。
+func NewFreeMindRenderer(tree *parse.Tree, options *Options) Renderer {
+	ret := &FreeMindRenderer{NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	return ret
+}
+
+func (r *FreeMindRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.WalkContinue
+	}
+
+	r.WriteString("<map version=\"1.0.1\">\n")
+	r.renderOutline(outlineRoot(outline(node), r.Tree.Name))
+	r.WriteString("</map>\n")
+	return ast.WalkSkipChildren
+}
+
+func (r *FreeMindRenderer) renderOutline(o *outlineNode) {
+	r.WriteString("<node TEXT=\"" + xmlEscape(o.text) + "\"")
+	if !o.task && "" == o.note && 1 > len(o.children) {
+		r.WriteString("/>\n")
+		return
+	}
+
+	r.WriteString(">\n")
+	if o.task {
+		if o.checked {
+			r.WriteString("<icon BUILTIN=\"button_ok\"/>\n")
+		} else {
+			r.WriteString("<icon BUILTIN=\"button_cancel\"/>\n")
+		}
+	}
+	if "" != o.note {
+		r.WriteString("<richcontent TYPE=\"NOTE\"><html><head></head><body>")
+		for _, p := range strings.Split(o.note, "\n\n") {
+			r.WriteString("<p>" + strings.ReplaceAll(xmlEscape(p), "&#xA;", "<br/>") + "</p>")
+		}
+		r.WriteString("</body></html></richcontent>\n")
+	}
+	for _, child := range o.children {
+		r.renderOutline(child)
+	}
+	r.WriteString("</node>\n")
+}
@@ -0,0 +1,348 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/lex"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+// GemtextRenderer 描述了 Gemini gemtext 渲染器。
+//
+// gemtext 是面向行的格式，不支持行内格式，链接必须独占一行，所以段落中的链接和图片会被提到段落之后的 => 行。
+type GemtextRenderer struct {
+	*BaseRenderer
+	NodeWriterStack []*bytes.Buffer // 节点输出缓冲栈
+	linkLines       []string        // 等待输出的 => 链接行
+}
+
+// NewGemtextRenderer 创建一个 Gemini gemtext 渲染器。
+func NewGemtextRenderer(tree *parse.Tree, options *Options) Renderer {
+	ret := &GemtextRenderer{BaseRenderer: NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderText
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeSpanContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeInlineMathContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderText
+	ret.RendererFuncs[ast.NodeEmojiAlias] = ret.renderEmojiAlias
+	ret.RendererFuncs[ast.NodeCodeBlock] = ret.renderCodeBlock
+	ret.RendererFuncs[ast.NodeMathBlock] = ret.renderMathBlock
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeTaskListItemMarker] = ret.renderTaskListItemMarker
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderSoftBreak
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeFootnotesDefBlock] = ret.renderFootnotesDefBlock
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+func (r *GemtextRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *GemtextRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+	} else {
+		// 保留行首空格，它可能是避免识别为行类型标记的前缀
+		buf := bytes.TrimRight(bytes.TrimLeft(r.Writer.Bytes(), "\n"), " \t\n")
+		r.Writer.Reset()
+		r.Write(buf)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) pushWriter() {
+	r.Writer = &bytes.Buffer{}
+	r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+}
+
+func (r *GemtextRenderer) popWriter() (ret []byte) {
+	writer := r.NodeWriterStack[len(r.NodeWriterStack)-1]
+	r.NodeWriterStack = r.NodeWriterStack[:len(r.NodeWriterStack)-1]
+	r.Writer = r.NodeWriterStack[len(r.NodeWriterStack)-1]
+	return bytes.TrimRight(writer.Bytes(), " \t\n")
+}
+
+// flushLinks 输出等待中的链接行，引述块中的链接行需要等到引述块结束后再输出。
+func (r *GemtextRenderer) flushLinks(node *ast.Node) {
+	if 1 > len(r.linkLines) || node.ParentIs(ast.NodeBlockquote) {
+		return
+	}
+	r.Newline()
+	r.WriteString(strings.Join(r.linkLines, "\n"))
+	r.Newline()
+	r.linkLines = nil
+}
+
+// addLink 记录一个等待输出的链接行。
+func (r *GemtextRenderer) addLink(dest []byte, kind LinkKind, text string) {
+	line := "=> " + util.BytesToStr(r.resolveLinkPath(dest, kind))
+	if text = strings.TrimSpace(text); "" != text {
+		line += " " + text
+	}
+	r.linkLines = append(r.linkLines, line)
+}
+
+// preformatted 输出预格式化块，alt 为开始行上的说明文字。
+func (r *GemtextRenderer) preformatted(node *ast.Node, alt string, content []byte) {
+	r.Newline()
+	r.WriteString("```" + alt + "\n")
+	r.Write(bytes.Trim(content, "\n"))
+	r.WriteString("\n```")
+	r.blockEnd(node)
+}
+
+func (r *GemtextRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if !node.ParentIs(ast.NodeListItem) {
+			r.pushWriter()
+		}
+		return ast.WalkContinue
+	}
+
+	if node.ParentIs(ast.NodeListItem) {
+		r.Newline()
+		r.flushLinks(node)
+		return ast.WalkContinue
+	}
+
+	content := r.popWriter()
+	if 0 < len(content) {
+		lines := bytes.Split(content, []byte("\n"))
+		for i, line := range lines {
+			if gemtextLineMarker(line) {
+				// gemtext 没有转义语法，以空格开头避免被识别为行类型标记
+				lines[i] = append([]byte{lex.ItemSpace}, line...)
+			}
+		}
+		if ast.NodeFootnotesDef == node.Parent.Type && node == node.Parent.FirstChild {
+			r.WriteString("[" + strconv.Itoa(chatFootnotesDefNum(node.Parent)) + "] ")
+		}
+		r.Write(bytes.Join(lines, []byte("\n")))
+		r.Newline()
+	}
+	r.flushLinks(node)
+	r.WriteByte(lex.ItemNewline)
+	return ast.WalkContinue
+}
+
+// gemtextLineMarker 判断文本行是否以 gemtext 行类型标记开头。
+func gemtextLineMarker(line []byte) bool {
+	return bytes.HasPrefix(line, []byte("#")) || bytes.HasPrefix(line, []byte("* ")) || bytes.HasPrefix(line, []byte(">")) ||
+		bytes.HasPrefix(line, []byte("=>")) || bytes.HasPrefix(line, []byte("```"))
+}
+
+func (r *GemtextRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderEmojiAlias(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering && ast.NodeEmojiImg == node.Parent.Type {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		lang, code := codeBlockLangCode(node)
+		r.preformatted(node, lang, code)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *GemtextRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var content []byte
+		if c := node.ChildByType(ast.NodeMathBlockContent); nil != c {
+			content = c.Tokens
+		}
+		r.preformatted(node, "latex", content)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *GemtextRenderer) renderHTMLBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.preformatted(node, "html", node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *GemtextRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.pushWriter()
+	} else {
+		content := r.popWriter()
+		r.Newline()
+		r.Write(chatQuote(content, "> "))
+		r.Newline()
+		r.flushLinks(node)
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		level := node.HeadingLevel
+		if 3 < level {
+			level = 3
+		}
+		r.Newline()
+		r.WriteString(strings.Repeat("#", level) + " ")
+	} else {
+		r.Newline()
+		r.flushLinks(node)
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering && !node.ParentIs(ast.NodeListItem) {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		// gemtext 只有一级无序列表，嵌套列表和有序列表都展平为 *
+		r.Newline()
+		r.WriteString("* ")
+		if 1 == node.ListData.Typ || (3 == node.ListData.Typ && 0 == node.ListData.BulletChar) {
+			r.WriteString(strconv.Itoa(node.ListData.Num) + ". ")
+		}
+	} else {
+		r.Newline()
+		r.flushLinks(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderTaskListItemMarker(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if node.TaskListItemChecked {
+			r.WriteString("[x]")
+		} else {
+			r.WriteString("[ ]")
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("---")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderHardBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if node.ParentIs(ast.NodeListItem) || node.ParentIs(ast.NodeHeading) {
+			r.WriteByte(lex.ItemSpace)
+			return ast.WalkContinue
+		}
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderSoftBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		// gemtext 的文本行由客户端自动折行，软换行合并为空格
+		r.WriteByte(lex.ItemSpace)
+	}
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.WalkContinue
+	}
+
+	dest := linkDest(r.Tree, node)
+	if 2 == node.LinkType {
+		r.Write(r.resolveLinkPath(dest, LinkKindLink))
+		r.addLink(dest, LinkKindLink, "")
+		return ast.WalkSkipChildren
+	}
+	r.addLink(dest, LinkKindLink, plainText(node))
+	return ast.WalkContinue
+}
+
+func (r *GemtextRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.addLink(linkDest(r.Tree, node), LinkKindImage, plainText(node))
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *GemtextRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.preformatted(node, "", []byte(monospaceTable(node)))
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *GemtextRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("[" + node.FootnotesRefId + "]")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *GemtextRenderer) renderFootnotesDefBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("---")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
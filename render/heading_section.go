@@ -0,0 +1,59 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"strconv"
+
+	"github.com/88250/lute/ast"
+)
+
+// openSection 在标题 heading 前关闭同级及更低级别的 section，然后打开该标题的 section。
+//
+// section 使用标题的 IAL 属性，没有 IAL id 时使用 section- 加上标题 ID 作为 id，并通过 aria-labelledby 关联标题。
+func (r *HtmlRenderer) openSection(heading *ast.Node, headingID string) {
+	r.closeSections(heading.HeadingLevel)
+
+	class := "level" + strconv.Itoa(heading.HeadingLevel)
+	var attrs [][]string
+	hasID := false
+	for i, attr := range heading.KramdownIAL {
+		switch {
+		case "class" == attr[0]:
+			class += " " + attr[1]
+		case 0 == i && "id" == attr[0]:
+			// 第一项是块 ID，和其他块一样使用 KramdownIALIDRenderName 渲染
+			attrs = append(attrs, []string{r.Options.KramdownIALIDRenderName, attr[1]})
+			hasID = "id" == r.Options.KramdownIALIDRenderName
+		default:
+			attrs = append(attrs, attr)
+			hasID = hasID || "id" == attr[0]
+		}
+	}
+	if !hasID {
+		attrs = append([][]string{{"id", "section-" + headingID}}, attrs...)
+	}
+	attrs = append(attrs, []string{"class", class}, []string{"aria-labelledby", headingID})
+
+	r.Tag("section", attrs, false)
+	r.Newline()
+	r.sections = append(r.sections, heading.HeadingLevel)
+}
+
+// closeSections 关闭级别不高于 level（数值不小于 level）的 section。
+func (r *HtmlRenderer) closeSections(level int) {
+	for 0 < len(r.sections) && level <= r.sections[len(r.sections)-1] {
+		r.sections = r.sections[:len(r.sections)-1]
+		r.Newline()
+		r.WriteString("</section>")
+		r.Newline()
+	}
+}
@@ -0,0 +1,160 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/editor"
+	"golang.org/x/text/unicode/norm"
+)
+
+// HeadingSlugger 描述了标题 ID 生成策略。
+type HeadingSlugger interface {
+	// Slug 根据标题文本生成 ID，重复 ID 的去重由调用方处理。
+	Slug(text string) string
+}
+
+// GitHubSlugger 生成和 GitHub 一致的标题 ID：转为小写，去掉标点符号，空格替换为 -。
+type GitHubSlugger struct{}
+
+func (s *GitHubSlugger) Slug(text string) string {
+	buf := &strings.Builder{}
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case ' ' == r:
+			buf.WriteByte('-')
+		case '-' == r || '_' == r || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// GitLabSlugger 生成和 GitLab 一致的标题 ID：在 GitHub 规则的基础上合并连续的 -。
+type GitLabSlugger struct{}
+
+func (s *GitLabSlugger) Slug(text string) string {
+	buf := &strings.Builder{}
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case ' ' == r || '-' == r:
+			if !strings.HasSuffix(buf.String(), "-") {
+				buf.WriteByte('-')
+			}
+		case '_' == r || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// HugoSlugger 生成和 Hugo（autoHeadingIDType = "github"）一致的标题 ID：转为小写，空格替换为 -，仅保留字母、数字、- 和 _。
+type HugoSlugger struct{}
+
+func (s *HugoSlugger) Slug(text string) string {
+	buf := &strings.Builder{}
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case ' ' == r || '-' == r:
+			buf.WriteByte('-')
+		case '_' == r || unicode.IsLetter(r) || unicode.IsDigit(r):
+			buf.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return buf.String()
+}
+
+// TransliterateSlugger 生成仅包含 ASCII 字符的标题 ID：去掉拉丁字母上的变音符号，中日韩字符通过 Transliterate 转写，其余字符替换为 -。
+//
+// Transliterate 为 nil 时使用内置的 Pinyin 将汉字转写为拼音，没有收录的字符（比如假名和谚文）原样保留。
+type TransliterateSlugger struct {
+	// Transliterate 用于转写单个中日韩字符，比如 "中" 转写为 "zhong"，返回空字符串时保留原字符。
+	Transliterate func(r rune) string
+}
+
+func (s *TransliterateSlugger) Slug(text string) string {
+	var words []string
+	word := &strings.Builder{}
+	endWord := func() {
+		if 0 < word.Len() {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		switch {
+		case unicode.IsMark(r):
+		case isCJK(r):
+			endWord()
+			transliterate := s.Transliterate
+			if nil == transliterate {
+				transliterate = Pinyin
+			}
+			if t := strings.ToLower(transliterate(r)); "" != t {
+				words = append(words, t)
+				continue
+			}
+			words = append(words, string(r))
+		case ('a' <= r && 'z' >= r) || ('0' <= r && '9' >= r) || '_' == r:
+			word.WriteRune(r)
+		default:
+			endWord()
+		}
+	}
+	endWord()
+	return strings.Join(words, "-")
+}
+
+// slugHeading 使用 slugger 生成标题 ID，标题上已经自定义了 ID 的话直接使用自定义 ID。
+func slugHeading(heading *ast.Node, slugger HeadingSlugger) string {
+	if headingID := heading.ChildByType(ast.NodeHeadingID); nil != headingID {
+		id := strings.TrimLeft(string(headingID.Tokens), "#")
+		if id = strings.ReplaceAll(id, editor.Caret, ""); "" != id {
+			return id
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	ast.Walk(heading, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+		switch n.Type {
+		case ast.NodeHeadingID:
+			return ast.WalkSkipChildren
+		case ast.NodeText, ast.NodeLinkText, ast.NodeCodeSpanContent, ast.NodeInlineMathContent:
+			buf.Write(n.Tokens)
+		case ast.NodeTextMark:
+			buf.WriteString(n.TextMarkTextContent)
+		}
+		return ast.WalkContinue
+	})
+	return slugger.Slug(strings.ReplaceAll(buf.String(), editor.Caret, ""))
+}
+
+// uniqueSlug 对重复的 ID 追加 -1、-2 等数字后缀，occurs 记录了已经生成的 ID。
+func uniqueSlug(slug string, occurs map[string]int) (ret string) {
+	ret = slug
+	for {
+		if _, ok := occurs[ret]; !ok {
+			break
+		}
+		occurs[slug]++
+		ret = slug + "-" + strconv.Itoa(occurs[slug])
+	}
+	occurs[ret] = 0
+	return
+}
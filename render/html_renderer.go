@@ -28,11 +28,13 @@ import (
 // HtmlRenderer 描述了 HTML 渲染器。
 type HtmlRenderer struct {
 	*BaseRenderer
+	crossRefs *crossRefs // 图片、表格和公式编号
+	sections  []int      // 已打开的 section 对应的标题级别
 }
 
 // NewHtmlRenderer 创建一个 HTML 渲染器。
 func NewHtmlRenderer(tree *parse.Tree, options *Options) *HtmlRenderer {
-	ret := &HtmlRenderer{NewBaseRenderer(tree, options)}
+	ret := &HtmlRenderer{BaseRenderer: NewBaseRenderer(tree, options)}
 	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
 	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
 	ret.RendererFuncs[ast.NodeText] = ret.renderText
@@ -212,10 +214,13 @@ func (r *HtmlRenderer) renderTextMark(node *ast.Node, entering bool) ast.WalkSta
 		}
 
 		if node.IsTextMarkType("a") {
-			attrs := [][]string{{"href", node.TextMarkAHref}}
+			// 文本标记中保存的是转义后的地址，改写前先反转义
+			href, linkAttrs := r.ResolveLink([]byte(html.UnescapeHTMLStr(node.TextMarkAHref)), LinkKindLink)
+			attrs := [][]string{{"href", util.BytesToStr(html.EscapeHTML(href))}}
 			if "" != node.TextMarkATitle {
 				attrs = append(attrs, []string{"title", node.TextMarkATitle})
 			}
+			attrs = append(attrs, escapeLinkAttrs(linkAttrs)...)
 			r.Tag("a", attrs, false)
 			r.WriteString(textContent)
 			r.WriteString("</a>")
@@ -231,6 +236,14 @@ func (r *HtmlRenderer) renderTextMark(node *ast.Node, entering bool) ast.WalkSta
 				r.WriteString(node.TextMarkInlineMemoContent)
 				r.WriteString(")</sup>")
 			}
+		} else if href, linkAttrs := r.textMarkRefLink(node); 0 < len(href) {
+			attrs := [][]string{{"href", html.EscapeHTMLStr(string(href))}}
+			attrs = append(attrs, r.renderTextMarkAttrs(node)...)
+			r.spanNodeAttrs(node, &attrs)
+			attrs = append(attrs, escapeLinkAttrs(linkAttrs)...)
+			r.Tag("a", attrs, false)
+			r.WriteString(textContent)
+			r.WriteString("</a>")
 		} else {
 			attrs := r.renderTextMarkAttrs(node)
 			r.spanNodeAttrs(node, &attrs)
@@ -529,6 +542,9 @@ func (r *HtmlRenderer) renderBlockQueryEmbedScript(node *ast.Node, entering bool
 }
 
 func (r *HtmlRenderer) renderBlockRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering && r.renderRefLink(node, ast.NodeBlockRefID, LinkKindBlockRef) {
+		return ast.WalkSkipChildren
+	}
 	return ast.WalkContinue
 }
 
@@ -561,9 +577,33 @@ func (r *HtmlRenderer) renderBlockRefDynamicText(node *ast.Node, entering bool)
 }
 
 func (r *HtmlRenderer) renderFileAnnotationRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering && r.renderRefLink(node, ast.NodeFileAnnotationRefID, LinkKindFileAnnotationRef) {
+		return ast.WalkSkipChildren
+	}
 	return ast.WalkContinue
 }
 
+// renderRefLink 将 LinkResolver 改写了地址的块引用、文件注解引用渲染为链接，没有改写时返回 false。
+func (r *HtmlRenderer) renderRefLink(node *ast.Node, idType ast.NodeType, kind LinkKind) bool {
+	id := node.ChildByType(idType)
+	if nil == id {
+		return false
+	}
+	href, linkAttrs := r.ResolveLink(id.Tokens, kind)
+	if 1 > len(href) {
+		return false
+	}
+
+	attrs := [][]string{{"href", util.BytesToStr(html.EscapeHTML(href))}}
+	attrs = append(attrs, escapeLinkAttrs(linkAttrs)...)
+	r.Tag("a", attrs, false)
+	for c := node.FirstChild; nil != c; c = c.Next {
+		r.renderNode(c)
+	}
+	r.Tag("/a", nil, false)
+	return true
+}
+
 func (r *HtmlRenderer) renderFileAnnotationRefID(node *ast.Node, entering bool) ast.WalkStatus {
 	return ast.WalkContinue
 }
@@ -758,12 +798,21 @@ func (r *HtmlRenderer) renderInlineMathOpenMarker(node *ast.Node, entering bool)
 }
 
 func (r *HtmlRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering && r.Options.MathML {
+		if content := node.ChildByType(ast.NodeInlineMathContent); nil != content {
+			if mathML, ok := TeX2MathML(util.BytesToStr(content.Tokens), false); ok {
+				r.WriteString(mathML)
+				return ast.WalkSkipChildren
+			}
+		}
+	}
 	return ast.WalkContinue
 }
 
 func (r *HtmlRenderer) renderMathBlockCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.Tag("/div", nil, false)
+		r.renderEquationNumber(node.Parent)
 	}
 	return ast.WalkContinue
 }
@@ -785,6 +834,23 @@ func (r *HtmlRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkSt
 		attrs := [][]string{{"class", "language-math"}}
 		r.handleKramdownBlockIAL(node)
 		attrs = append(attrs, node.KramdownIAL...)
+		if n := r.equationNumber(node); 0 < n {
+			attrs = append(attrs, []string{"data-equation-number", strconv.Itoa(n)})
+			r.Tag("div", [][]string{{"class", "equation"}}, false)
+		}
+		if r.Options.MathML {
+			if content := node.ChildByType(ast.NodeMathBlockContent); nil != content {
+				if mathML, ok := TeX2MathML(util.BytesToStr(content.Tokens), true); ok {
+					// 转换成功后不再使用 language-math，避免前端再次渲染
+					attrs[0][1] = "math"
+					r.Tag("div", attrs, false)
+					r.WriteString(mathML)
+					r.Tag("/div", nil, false)
+					r.renderEquationNumber(node)
+					return ast.WalkSkipChildren
+				}
+			}
+		}
 		r.Tag("div", attrs, false)
 	}
 	return ast.WalkContinue
@@ -842,8 +908,20 @@ func (r *HtmlRenderer) renderTableHead(node *ast.Node, entering bool) ast.WalkSt
 func (r *HtmlRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.handleKramdownBlockIAL(node)
-		r.Tag("table", node.KramdownIAL, false)
+		var attrs [][]string
+		attrs = append(attrs, node.KramdownIAL...)
+		if r.Options.Figure && "" == node.IALAttr("id") {
+			if caption := tableCaption(node); nil != caption {
+				if label := captionLabel(caption); "" != label {
+					attrs = append(attrs, []string{"id", label})
+				}
+			}
+		}
+		r.Tag("table", attrs, false)
 		r.Newline()
+		if r.Options.Figure {
+			r.renderTableCaption(node)
+		}
 	} else {
 		if nil != node.FirstChild.Next {
 			r.Tag("/tbody", nil, false)
@@ -962,13 +1040,15 @@ func (r *HtmlRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus
 
 			r.WriteString("<img src=\"")
 			destTokens := node.ChildByType(ast.NodeLinkDest).Tokens
-			destTokens = r.LinkPath(destTokens)
+			destTokens, linkAttrs := r.ResolveLink(destTokens, LinkKindImage)
 			if "" != r.Options.ImageLazyLoading {
 				r.Write(html.EscapeHTML(util.StrToBytes(r.Options.ImageLazyLoading)))
 				r.WriteString("\" data-src=\"")
 			}
 			r.Write(html.EscapeHTML(destTokens))
-			r.WriteString("\" alt=\"")
+			r.WriteByte(lex.ItemDoublequote)
+			r.WriteString(linkAttrsStr(linkAttrs))
+			r.WriteString(" alt=\"")
 		}
 		r.DisableTags++
 		return ast.WalkContinue
@@ -1018,11 +1098,15 @@ func (r *HtmlRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus
 				destTokens = nil
 			}
 		}
-		destTokens = r.LinkPath(destTokens)
+		destTokens, linkAttrs := r.ResolveLink(destTokens, LinkKindLink)
 		attrs := [][]string{{"href", util.BytesToStr(html.EscapeHTML(destTokens))}}
 		if title := node.ChildByType(ast.NodeLinkTitle); nil != title && nil != title.Tokens {
 			attrs = append(attrs, []string{"title", util.BytesToStr(html.EscapeHTML(title.Tokens))})
 		}
+		if "" != node.LinkSubtype {
+			attrs = append(attrs, []string{"data-subtype", html.EscapeHTMLStr(node.LinkSubtype)})
+		}
+		attrs = append(attrs, escapeLinkAttrs(linkAttrs)...)
 		r.Tag("a", attrs, false)
 	} else {
 		r.Tag("/a", nil, false)
@@ -1058,6 +1142,9 @@ func (r *HtmlRenderer) renderInlineHTML(node *ast.Node, entering bool) ast.WalkS
 }
 
 func (r *HtmlRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.closeSections(1)
+	}
 	return ast.WalkContinue
 }
 
@@ -1066,6 +1153,19 @@ func (r *HtmlRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkSt
 		return ast.WalkContinue
 	}
 
+	if r.Options.Figure {
+		if isTableCaption(node) {
+			// 表格标题在表格中渲染
+			return ast.WalkSkipChildren
+		}
+		if img, label := figureImage(node); nil != img {
+			if entering {
+				r.renderFigure(node, img, label)
+			}
+			return ast.WalkSkipChildren
+		}
+	}
+
 	if entering {
 		r.Newline()
 		r.handleKramdownBlockIAL(node)
@@ -1094,6 +1194,10 @@ func (r *HtmlRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus
 		if r.Options.FixTermTypo {
 			tokens = r.FixTermTypo(tokens)
 		}
+		if r.Options.FigureNumbering && bytes.Contains(tokens, []byte("@")) {
+			r.renderCrossRefs(tokens)
+			return ast.WalkContinue
+		}
 		r.Write(html.EscapeHTML(tokens))
 	}
 	return ast.WalkContinue
@@ -1241,9 +1345,16 @@ func (r *HtmlRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStat
 	if entering {
 		r.Newline()
 		level := headingLevel[node.HeadingLevel : node.HeadingLevel+1]
+		id := r.headingID(node)
+		sectioned := r.Options.HeadingSections && ast.NodeDocument == node.Parent.Type
+		if sectioned {
+			r.openSection(node, id)
+		}
 		r.WriteString("<h" + level)
-		id := HeadingID(node)
-		if r.Options.ToC || r.Options.HeadingID || r.Options.KramdownBlockIAL {
+		if sectioned {
+			// 标题的 IAL 属性已经渲染到 section 上
+			r.WriteString(" id=\"" + id + "\"")
+		} else if r.Options.ToC || r.Options.HeadingID || r.Options.KramdownBlockIAL {
 			r.WriteString(" id=\"" + id + "\"")
 			if r.Options.KramdownBlockIAL {
 				if "id" != r.Options.KramdownIALIDRenderName && 0 < len(node.KramdownIAL) {
@@ -1258,9 +1369,14 @@ func (r *HtmlRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStat
 			}
 		}
 		r.WriteString(">")
+		if r.Options.ToCNumberHeadings {
+			if number := r.headingNumber(node); "" != number {
+				r.WriteString("<span class=\"heading-number\">" + number + "</span> ")
+			}
+		}
 	} else {
 		if r.Options.HeadingAnchor {
-			id := HeadingID(node)
+			id := r.headingID(node)
 			r.Tag("a", [][]string{{"id", "vditorAnchor-" + id}, {"class", "vditor-anchor"}, {"href", "#" + id}}, false)
 			r.WriteString(`<svg viewBox="0 0 16 16" version="1.1" width="16" height="16"><path fill-rule="evenodd" d="M4 9h1v1H4c-1.5 0-3-1.69-3-3.5S2.55 3 4 3h4c1.45 0 3 1.69 3 3.5 0 1.41-.91 2.72-2 3.25V8.59c.58-.45 1-1.27 1-2.09C10 5.22 8.98 4 8 4H4c-.98 0-2 1.22-2 2.5S3 9 4 9zm9-3h-1v1h1c1 0 2 1.22 2 2.5S13.98 12 13 12H9c-.98 0-2-1.22-2-2.5 0-.83.42-1.64 1-2.09V6.25c-1.09.53-2 1.84-2 3.25C6 11.31 7.55 13 9 13h4c1.45 0 3-1.69 3-3.5S14.5 6 13 6z"></path></svg>`)
 			r.Tag("/a", nil, false)
@@ -1387,7 +1503,7 @@ func (r *HtmlRenderer) renderTextMarkAttrs(node *ast.Node) (attrs [][]string) {
 			attrs = append(attrs, []string{"data-id", node.TextMarkBlockRefID})
 		} else if "a" == typ {
 			href := node.TextMarkAHref
-			href = string(r.LinkPath([]byte(href)))
+			href = string(r.resolveLinkPath([]byte(href), LinkKindLink))
 
 			attrs = append(attrs, []string{"data-href", href})
 			if "" != node.TextMarkATitle {
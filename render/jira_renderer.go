@@ -0,0 +1,516 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/lex"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+// JiraRenderer 描述了 Jira 维基标记渲染器。
+type JiraRenderer struct {
+	*BaseRenderer
+	alertMarker *ast.Node // 当前提示块的 [!TYPE] 标记文本节点
+}
+
+// NewJiraRenderer 创建一个 Jira 维基标记渲染器。
+func NewJiraRenderer(tree *parse.Tree, options *Options) Renderer {
+	ret := &JiraRenderer{BaseRenderer: NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderText
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeSpan] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeInlineMath] = ret.renderInlineMath
+	ret.RendererFuncs[ast.NodeCodeBlock] = ret.renderCodeBlock
+	ret.RendererFuncs[ast.NodeMathBlock] = ret.renderMathBlock
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeEmphasis] = ret.renderEmphasis
+	ret.RendererFuncs[ast.NodeStrong] = ret.renderStrong
+	ret.RendererFuncs[ast.NodeStrikethrough] = ret.renderStrikethrough
+	ret.RendererFuncs[ast.NodeUnderline] = ret.renderUnderline
+	ret.RendererFuncs[ast.NodeSup] = ret.renderSup
+	ret.RendererFuncs[ast.NodeSub] = ret.renderSub
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeTaskListItemMarker] = ret.renderTaskListItemMarker
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderSoftBreak
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeTableRow] = ret.renderTableRow
+	ret.RendererFuncs[ast.NodeTableCell] = ret.renderTableCell
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderEmojiUnicode
+	ret.RendererFuncs[ast.NodeEmojiImg] = ret.renderEmojiImg
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeFootnotesDefBlock] = ret.renderFootnotesDefBlock
+	ret.RendererFuncs[ast.NodeFootnotesDef] = ret.renderFootnotesDef
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+func (r *JiraRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *JiraRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		buf := bytes.TrimSpace(r.Writer.Bytes())
+		r.Writer.Reset()
+		r.Write(buf)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		if node.ParentIs(ast.NodeTableCell) {
+			return ast.WalkContinue
+		}
+		if wikiListItemParagraphFollows(node) {
+			// 维基标记的列表项只能占一行，松散列表项中的段落使用换行连接
+			r.WriteString(" \\\\ ")
+			return ast.WalkContinue
+		}
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		tokens := node.Tokens
+		if node == r.alertMarker {
+			tokens = alertMarkerRemains(node)
+		}
+		if ast.NodeBackslashContent == node.Type && 1 == len(tokens) && bytes.ContainsAny(tokens, "*_-+^~") {
+			// Markdown 中使用反斜杠转义的标记总是需要转义
+			r.WriteByte(lex.ItemBackslash)
+			r.Write(tokens)
+			return ast.WalkContinue
+		}
+
+		// 反斜杠转义会将文本拆分为多个节点，判断词边界时需要参考相邻的节点
+		prev, next := byte(' '), byte(' ')
+		if p := jiraInlineText(node.Previous); 0 < len(p) {
+			prev = p[len(p)-1]
+		}
+		if n := jiraInlineText(node.Next); 0 < len(n) {
+			next = n[0]
+		}
+		r.Write(jiraEscape(tokens, prev, next))
+	}
+	return ast.WalkContinue
+}
+
+// jiraEscape 转义 Jira 维基标记中会被识别为宏、链接和表格分隔的字符，以及位于词边界的 *、_、-、+、^、~ 文本效果标记。
+//
+// prev 和 next 是 tokens 前后相邻的字节，没有的话使用空格。
+func jiraEscape(tokens []byte, prev, next byte) []byte {
+	if !bytes.ContainsAny(tokens, "{}[]|*_-+^~") {
+		return tokens
+	}
+	ret := make([]byte, 0, len(tokens)+8)
+	for i, c := range tokens {
+		switch c {
+		case '{', '}', '[', ']', '|':
+			ret = append(ret, '\\')
+		case '*', '_', '-', '+', '^', '~':
+			if jiraEffectMarker(tokens, i, prev, next) {
+				ret = append(ret, '\\')
+			}
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+// jiraInlineText 返回文本节点或者反斜杠转义节点 node 的文本。
+func jiraInlineText(node *ast.Node) []byte {
+	if nil == node {
+		return nil
+	}
+	switch node.Type {
+	case ast.NodeText:
+		return node.Tokens
+	case ast.NodeBackslash:
+		if nil != node.FirstChild {
+			return node.FirstChild.Tokens
+		}
+	}
+	return nil
+}
+
+// jiraEffectMarker 判断 tokens[i] 是否可能作为文本效果的开始或者结束标记，比如 *b* 中的 *。词内的标记（比如 snake_case 和 well-known）不会被 Jira 识别。
+func jiraEffectMarker(tokens []byte, i int, prev, next byte) bool {
+	if 0 < i {
+		prev = tokens[i-1]
+	}
+	if i+1 < len(tokens) {
+		next = tokens[i+1]
+	}
+	opener := !jiraWordByte(prev) && !lex.IsWhitespace(next)
+	closer := !jiraWordByte(next) && !lex.IsWhitespace(prev)
+	return opener || closer
+}
+
+// jiraWordByte 判断 c 是否为单词中的字节，非 ASCII 字节按照单词处理。
+func jiraWordByte(c byte) bool {
+	return 0x80 <= c || lex.IsASCIILetterNum(c)
+}
+
+func (r *JiraRenderer) renderCodeSpan(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("{{")
+		if content := node.ChildByType(ast.NodeCodeSpanContent); nil != content {
+			r.Write(jiraEscape(content.Tokens, ' ', ' '))
+		}
+		r.WriteString("}}")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *JiraRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("{{")
+		if content := node.ChildByType(ast.NodeInlineMathContent); nil != content {
+			r.Write(jiraEscape(content.Tokens, ' ', ' '))
+		}
+		r.WriteString("}}")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *JiraRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		lang, code := codeBlockLangCode(node)
+		r.Newline()
+		if "" == lang {
+			r.WriteString("{code}\n")
+		} else {
+			r.WriteString("{code:" + lang + "}\n")
+		}
+		r.Write(bytes.TrimRight(code, "\n"))
+		r.WriteString("\n{code}")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *JiraRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("{noformat}\n")
+		if content := node.ChildByType(ast.NodeMathBlockContent); nil != content {
+			r.Write(bytes.TrimSpace(content.Tokens))
+		}
+		r.WriteString("\n{noformat}")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *JiraRenderer) renderHTMLBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("{noformat}\n")
+		r.Write(bytes.TrimSpace(node.Tokens))
+		r.WriteString("\n{noformat}")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *JiraRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('_')
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('*')
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderStrikethrough(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('-')
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderUnderline(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('+')
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderSup(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('^')
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderSub(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('~')
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	typ, marker := blockquoteAlert(node)
+	macro := "quote"
+	switch typ {
+	case "note", "info":
+		macro = "info"
+	case "tip":
+		macro = "tip"
+	case "important":
+		macro = "note"
+	case "warning", "caution":
+		macro = "warning"
+	}
+
+	if entering {
+		if "quote" != macro {
+			r.alertMarker = marker
+		}
+		r.Newline()
+		r.WriteString("{" + macro + "}\n")
+	} else {
+		r.alertMarker = nil
+		r.Writer.Truncate(len(bytes.TrimRight(r.Writer.Bytes(), "\n")))
+		r.WriteString("\n{" + macro + "}")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("h" + headingLevel[node.HeadingLevel:node.HeadingLevel+1] + ". ")
+	} else {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering && !node.ParentIs(ast.NodeListItem) {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString(wikiListPrefix(node) + " ")
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderTaskListItemMarker(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if node.TaskListItemChecked {
+			r.WriteString("(/)")
+		} else {
+			r.WriteString("(off)") // (x) 是 Jira 中表示错误的红色图标
+		}
+	}
+	return ast.WalkContinue
+}
+
+// wikiListItemParagraphFollows 判断列表项中的段落 paragraph 后面是否紧跟着同一列表项中的另一个段落。
+func wikiListItemParagraphFollows(paragraph *ast.Node) bool {
+	if nil == paragraph.Parent || ast.NodeListItem != paragraph.Parent.Type {
+		return false
+	}
+	next := paragraph.Next
+	for nil != next && ast.NodeKramdownBlockIAL == next.Type {
+		next = next.Next
+	}
+	return nil != next && ast.NodeParagraph == next.Type
+}
+
+// wikiListPrefix 根据列表项的嵌套层级生成维基标记列表前缀，无序列表使用 *，有序列表使用 #。
+func wikiListPrefix(listItem *ast.Node) string {
+	var prefix []byte
+	for n := listItem.Parent; nil != n; n = n.Parent {
+		if ast.NodeList != n.Type {
+			continue
+		}
+		if 1 == n.ListData.Typ || (3 == n.ListData.Typ && 0 == n.ListData.BulletChar) {
+			prefix = append([]byte{'#'}, prefix...)
+		} else {
+			prefix = append([]byte{'*'}, prefix...)
+		}
+	}
+	return string(prefix)
+}
+
+func (r *JiraRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("----")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderHardBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if node.ParentIs(ast.NodeListItem) || node.ParentIs(ast.NodeTableCell) {
+			r.WriteString(" \\\\ ")
+			return ast.WalkContinue
+		}
+		r.WriteString("\\\\\n")
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderSoftBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if nil != r.alertMarker && node.Previous == r.alertMarker && 1 > len(alertMarkerRemains(r.alertMarker)) {
+			return ast.WalkContinue
+		}
+		if node.ParentIs(ast.NodeListItem) || node.ParentIs(ast.NodeTableCell) {
+			r.WriteByte(lex.ItemSpace)
+			return ast.WalkContinue
+		}
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	dest := util.BytesToStr(r.resolveLinkPath(linkDest(r.Tree, node), LinkKindLink))
+	if 2 == node.LinkType {
+		if entering {
+			r.WriteString("[" + dest + "]")
+		}
+		return ast.WalkSkipChildren
+	}
+	if entering {
+		r.WriteByte('[')
+	} else {
+		r.WriteString("|" + dest + "]")
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("!" + util.BytesToStr(r.resolveLinkPath(linkDest(r.Tree, node), LinkKindImage)))
+		if alt := node.ChildByType(ast.NodeLinkText); nil != alt && 0 < len(alt.Tokens) {
+			r.WriteString("|alt=" + strings.ReplaceAll(util.BytesToStr(alt.Tokens), ",", " "))
+		}
+		r.WriteByte('!')
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *JiraRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+	} else {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderTableRow(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		if ast.NodeTableHead == node.Parent.Type {
+			r.WriteString("||")
+		} else {
+			r.WriteByte('|')
+		}
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderTableCell(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if ast.NodeTableHead == node.Parent.Parent.Type {
+			r.WriteString("||")
+		} else {
+			r.WriteByte('|')
+		}
+		if nil == node.FirstChild {
+			// 空单元格需要占位，否则 Jira 会合并分隔符
+			r.WriteByte(lex.ItemSpace)
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderEmojiUnicode(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *JiraRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if alias := node.ChildByType(ast.NodeEmojiAlias); nil != alias {
+			r.Write(alias.Tokens)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *JiraRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("^" + node.FootnotesRefId + "^")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *JiraRenderer) renderFootnotesDefBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("----")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *JiraRenderer) renderFootnotesDef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		num := 1
+		for prev := node.Previous; nil != prev; prev = prev.Previous {
+			num++
+		}
+		r.WriteString("^" + strconv.Itoa(num) + "^ ")
+	}
+	return ast.WalkContinue
+}
@@ -13,9 +13,102 @@ package render
 import (
 	"bytes"
 
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
 	"github.com/88250/lute/util"
 )
 
+// LinkKind 描述了地址所属的节点类型。
+type LinkKind int
+
+const (
+	LinkKindLink              LinkKind = iota // 链接
+	LinkKindImage                             // 图片以及 HTML 中的资源地址
+	LinkKindBlockRef                          // 块引用，地址为块 ID
+	LinkKindFileAnnotationRef                 // 文件注解引用，地址为注解 ID
+)
+
+// LinkResolver 描述了链接、图片等地址的改写策略，比如将相对路径的 .md 链接映射为 .html 路由、通过 CDN 访问图片等。
+type LinkResolver interface {
+	// Resolve 改写地址 dest，docPath 为当前文档路径，文档没有路径时使用文档名称。
+	//
+	// 返回的 url 将直接作为渲染结果，不再经过 LinkBase 和 LinkPrefix 处理；块引用和文件注解引用返回空字符串时不生成链接。
+	// attrs 为需要追加到 HTML 元素上的属性，比如 rel="noopener nofollow"，非 HTML 渲染器会忽略该值。
+	Resolve(dest string, kind LinkKind, docPath string) (url string, attrs [][]string)
+}
+
+// LinkResolverFunc 是函数形式的 LinkResolver。
+type LinkResolverFunc func(dest string, kind LinkKind, docPath string) (url string, attrs [][]string)
+
+func (f LinkResolverFunc) Resolve(dest string, kind LinkKind, docPath string) (url string, attrs [][]string) {
+	return f(dest, kind, docPath)
+}
+
+// ResolveLink 使用 Options.LinkResolver 改写地址 dest。
+//
+// 未设置 LinkResolver 时链接和图片使用 LinkPath 处理，块引用和文件注解引用返回 nil。
+func (r *BaseRenderer) ResolveLink(dest []byte, kind LinkKind) (ret []byte, attrs [][]string) {
+	if nil == r.Options.LinkResolver {
+		if LinkKindLink == kind || LinkKindImage == kind {
+			ret = r.LinkPath(dest)
+		}
+		return
+	}
+
+	var docPath string
+	if nil != r.Tree {
+		if docPath = r.Tree.Path; "" == docPath {
+			docPath = r.Tree.Name
+		}
+	}
+	url, attrs := r.Options.LinkResolver.Resolve(util.BytesToStr(dest), kind, docPath)
+	return util.StrToBytes(url), attrs
+}
+
+// resolveLinkPath 使用 ResolveLink 改写地址 dest，忽略追加的属性。
+func (r *BaseRenderer) resolveLinkPath(dest []byte, kind LinkKind) (ret []byte) {
+	ret, _ = r.ResolveLink(dest, kind)
+	return
+}
+
+// textMarkRefLink 使用 ResolveLink 改写块引用、文件注解引用类型的文本标记节点 node 的地址。
+func (r *BaseRenderer) textMarkRefLink(node *ast.Node) ([]byte, [][]string) {
+	if node.IsTextMarkType("block-ref") {
+		return r.ResolveLink([]byte(node.TextMarkBlockRefID), LinkKindBlockRef)
+	}
+	if node.IsTextMarkType("file-annotation-ref") {
+		return r.ResolveLink([]byte(node.TextMarkFileAnnotationRefID), LinkKindFileAnnotationRef)
+	}
+	return nil, nil
+}
+
+// escapeLinkAttrs 转义 LinkResolver 返回的属性值。
+func escapeLinkAttrs(attrs [][]string) (ret [][]string) {
+	for _, attr := range attrs {
+		if 2 > len(attr) || "" == attr[0] {
+			continue
+		}
+		ret = append(ret, []string{attr[0], html.EscapeHTMLStr(attr[1])})
+	}
+	return
+}
+
+// linkAttrsStr 将 LinkResolver 返回的属性转换为 HTML 属性字符串，非空时以空格开头。
+func linkAttrsStr(attrs [][]string) (ret string) {
+	for _, attr := range escapeLinkAttrs(attrs) {
+		ret += " " + attr[0] + "=\"" + attr[1] + "\""
+	}
+	return
+}
+
+// linkDestKind 返回链接地址节点 dest 所属的节点类型。
+func linkDestKind(dest *ast.Node) LinkKind {
+	if nil != dest.Parent && ast.NodeImage == dest.Parent.Type {
+		return LinkKindImage
+	}
+	return LinkKindLink
+}
+
 func (r *BaseRenderer) LinkPath(dest []byte) []byte {
 	dest = r.RelativePath(dest)
 	dest = r.PrefixPath(dest)
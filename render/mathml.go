@@ -0,0 +1,691 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/88250/lute/html"
+)
+
+// TeX2MathML 将 TeX 公式转换为 MathML，display 为 true 时输出块级公式。
+//
+// 仅支持常用的 LaTeX 数学子集（分式、根式、上下标、矩阵、运算符和希腊字母等），遇到不支持的命令时 ok 返回 false。
+func TeX2MathML(tex string, display bool) (mathML string, ok bool) {
+	p := &texParser{src: tex, display: display}
+	content, ok := p.parseTop()
+	if !ok {
+		return "", false
+	}
+
+	buf := &strings.Builder{}
+	buf.WriteString("<math xmlns=\"http://www.w3.org/1998/Math/MathML\"")
+	if display {
+		buf.WriteString(" display=\"block\"")
+	}
+	buf.WriteString("><semantics>")
+	buf.WriteString(mrow(content))
+	buf.WriteString("<annotation encoding=\"application/x-tex\">")
+	buf.WriteString(html.EscapeHTMLStr(strings.TrimSpace(tex)))
+	buf.WriteString("</annotation></semantics></math>")
+	return buf.String(), true
+}
+
+// texParser 描述了 TeX 公式解析器，解析时直接生成 MathML 元素。
+type texParser struct {
+	src     string // TeX 源码
+	pos     int    // 当前解析位置
+	display bool   // 是否为块级公式
+	variant string // 当前字体变体，比如 bold、double-struck
+	failed  bool   // 是否遇到了不支持的语法
+}
+
+func (p *texParser) parseTop() (ret []string, ok bool) {
+	rows := p.parseRows("")
+	if p.failed || p.pos < len(p.src) {
+		return nil, false
+	}
+	if 1 == len(rows) && 1 == len(rows[0]) {
+		return rows[0][0], true
+	}
+	return []string{mtable(rows, "")}, true
+}
+
+// parseRows 解析由 & 和 \\ 分隔的表格，env 为所在环境名，顶层时为空。
+func (p *texParser) parseRows(env string) (rows [][][]string) {
+	var row [][]string
+	for {
+		cell := p.parseRow()
+		row = append(row, cell)
+		if p.failed {
+			return
+		}
+		switch {
+		case p.consume("&"):
+		case p.consume(`\\`):
+			rows = append(rows, row)
+			row = nil
+		default:
+			if 1 < len(row) || 0 < len(row[0]) || 0 == len(rows) {
+				rows = append(rows, row)
+			}
+			if "" != env {
+				if !p.consume(`\end`) || env != p.parseName() {
+					p.failed = true
+				}
+			}
+			return
+		}
+	}
+}
+
+// parseRow 解析一行公式元素，直到输入结束或者遇到 }、&、\\、\right、\end 和 \middle。
+func (p *texParser) parseRow() (ret []string) {
+	for !p.failed {
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.peekAny("}", "&", `\\`, `\right`, `\end`, `\middle`) {
+			return
+		}
+		ret = append(ret, p.parseScripts())
+	}
+	return
+}
+
+// parseScripts 解析一个元素以及它的上下标。
+func (p *texParser) parseScripts() string {
+	base, limits := p.parseAtom(false)
+	if p.failed {
+		return ""
+	}
+
+	var sub, sup, primes string
+	for {
+		p.skipSpace()
+		switch {
+		case p.consume(`\limits`):
+			limits = true
+			continue
+		case p.consume(`\nolimits`):
+			limits = false
+			continue
+		case p.consume("'"):
+			primes += "<mo>′</mo>"
+			continue
+		case "" == sub && p.consume("_"):
+			sub = p.parseArg()
+			continue
+		case "" == sup && p.consume("^"):
+			sup = p.parseArg()
+			continue
+		}
+		break
+	}
+	if "" != primes {
+		if sup = primes + sup; sup != "<mo>′</mo>" {
+			sup = "<mrow>" + sup + "</mrow>"
+		}
+	}
+	if p.failed || ("" == sub && "" == sup) {
+		return base
+	}
+
+	if "" == base {
+		base = "<mrow></mrow>"
+	}
+	under, over, tag := "msub", "msup", "msubsup"
+	if limits && p.display {
+		under, over, tag = "munder", "mover", "munderover"
+	}
+	switch {
+	case "" == sup:
+		return "<" + under + ">" + base + sub + "</" + under + ">"
+	case "" == sub:
+		return "<" + over + ">" + base + sup + "</" + over + ">"
+	}
+	return "<" + tag + ">" + base + sub + sup + "</" + tag + ">"
+}
+
+// parseArg 解析命令或者上下标的参数，参数为 {...} 分组或者单个元素。
+func (p *texParser) parseArg() string {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		p.failed = true
+		return ""
+	}
+	ret, _ := p.parseAtom(true)
+	if "" == ret {
+		ret = "<mrow></mrow>"
+	}
+	return ret
+}
+
+// parseGroup 解析 {...} 分组。
+func (p *texParser) parseGroup() string {
+	p.skipSpace()
+	if !p.consume("{") {
+		p.failed = true
+		return ""
+	}
+	items := p.parseRow()
+	if !p.consume("}") {
+		p.failed = true
+		return ""
+	}
+	return mrow(items)
+}
+
+// parseAtom 解析单个元素，single 为 true 时数字只取一位，limits 返回元素是否为在块级公式中上下标位于上下方的运算符。
+func (p *texParser) parseAtom(single bool) (ret string, limits bool) {
+	r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	switch {
+	case '{' == r:
+		return p.parseGroup(), false
+	case '\\' == r:
+		return p.parseCommand()
+	case unicode.IsDigit(r) || ('.' == r && p.pos+1 < len(p.src) && unicode.IsDigit(rune(p.src[p.pos+1]))):
+		start := p.pos
+		p.pos += size
+		if !single {
+			for p.pos < len(p.src) && (isASCIIDigit(p.src[p.pos]) || ('.' == p.src[p.pos] && p.pos+1 < len(p.src) && isASCIIDigit(p.src[p.pos+1]))) {
+				p.pos++
+			}
+		}
+		return "<mn>" + p.variantText(p.src[start:p.pos]) + "</mn>", false
+	case unicode.IsLetter(r):
+		p.pos += size
+		return p.mi(string(r)), false
+	case '~' == r:
+		p.pos += size
+		return "<mtext> </mtext>", false
+	}
+
+	if op, ok := texOperatorChars[r]; ok {
+		p.pos += size
+		return op, false
+	}
+	p.failed = true
+	return "", false
+}
+
+// parseCommand 解析 \ 开头的命令。
+func (p *texParser) parseCommand() (ret string, limits bool) {
+	p.pos++ // \
+	if p.pos >= len(p.src) {
+		p.failed = true
+		return
+	}
+
+	name := p.parseCommandName()
+	if s, ok := texSpaces[name]; ok {
+		return "<mspace width=\"" + s + "\"></mspace>", false
+	}
+	if s, ok := texIdentifiers[name]; ok {
+		if unicode.IsUpper([]rune(s)[0]) && unicode.Is(unicode.Greek, []rune(s)[0]) {
+			return "<mi mathvariant=\"normal\">" + s + "</mi>", false
+		}
+		return "<mi>" + s + "</mi>", false
+	}
+	if s, ok := texOperators[name]; ok {
+		return "<mo>" + s + "</mo>", false
+	}
+	if s, ok := texBigOperators[name]; ok {
+		return "<mo>" + s + "</mo>", !strings.HasPrefix(name, "i") && !strings.HasPrefix(name, "oint")
+	}
+	if texFunctions[name] {
+		return "<mi>" + name + "</mi>", texLimitFunctions[name]
+	}
+	if s, ok := texAccents[name]; ok {
+		arg := p.parseArg()
+		stretchy := ""
+		if strings.HasPrefix(name, "wide") || strings.HasPrefix(name, "over") {
+			stretchy = " stretchy=\"true\""
+		}
+		return "<mover accent=\"true\">" + arg + "<mo" + stretchy + ">" + s + "</mo></mover>", false
+	}
+	if v, ok := texVariants[name]; ok {
+		variant := p.variant
+		p.variant = v
+		ret = p.parseArg()
+		p.variant = variant
+		return ret, false
+	}
+
+	switch name {
+	case "frac", "dfrac", "tfrac", "cfrac":
+		num := p.parseArg()
+		den := p.parseArg()
+		return "<mfrac>" + num + den + "</mfrac>", false
+	case "binom", "dbinom", "tbinom":
+		top := p.parseArg()
+		bottom := p.parseArg()
+		return "<mrow><mo>(</mo><mfrac linethickness=\"0\">" + top + bottom + "</mfrac><mo>)</mo></mrow>", false
+	case "sqrt":
+		p.skipSpace()
+		if p.consume("[") {
+			var index []string
+			for !p.failed && p.pos < len(p.src) && !p.peekAny("]") {
+				index = append(index, p.parseScripts())
+				p.skipSpace()
+			}
+			if !p.consume("]") {
+				p.failed = true
+			}
+			radicand := p.parseArg()
+			return "<mroot>" + radicand + mrow(index) + "</mroot>", false
+		}
+		return "<msqrt>" + p.parseArg() + "</msqrt>", false
+	case "underline":
+		return "<munder accentunder=\"true\">" + p.parseArg() + "<mo stretchy=\"true\">_</mo></munder>", false
+	case "underbrace":
+		return "<munder>" + p.parseArg() + "<mo stretchy=\"true\">⏟</mo></munder>", true
+	case "overbrace":
+		return "<mover>" + p.parseArg() + "<mo stretchy=\"true\">⏞</mo></mover>", true
+	case "overset", "stackrel":
+		over := p.parseArg()
+		base := p.parseArg()
+		return "<mover>" + base + over + "</mover>", false
+	case "underset":
+		under := p.parseArg()
+		base := p.parseArg()
+		return "<munder>" + base + under + "</munder>", false
+	case "text", "textrm", "textup", "textnormal", "mbox", "textbf", "textit", "texttt":
+		text := p.parseRawArg()
+		attr := ""
+		switch name {
+		case "textbf":
+			attr = " mathvariant=\"bold\""
+		case "textit":
+			attr = " mathvariant=\"italic\""
+		case "texttt":
+			attr = " mathvariant=\"monospace\""
+		}
+		return "<mtext" + attr + ">" + html.EscapeHTMLStr(text) + "</mtext>", false
+	case "operatorname":
+		return "<mi>" + html.EscapeHTMLStr(strings.TrimSpace(p.parseRawArg())) + "</mi>", false
+	case "left":
+		open := p.parseDelimiter()
+		items := p.parseRow()
+		for !p.failed && p.consume(`\middle`) {
+			items = append(items, "<mo>"+p.parseDelimiter()+"</mo>")
+			items = append(items, p.parseRow()...)
+		}
+		if !p.consume(`\right`) {
+			p.failed = true
+			return
+		}
+		closing := p.parseDelimiter()
+		buf := &strings.Builder{}
+		buf.WriteString("<mrow>")
+		if "" != open {
+			buf.WriteString("<mo fence=\"true\">" + open + "</mo>")
+		}
+		buf.WriteString(strings.Join(items, ""))
+		if "" != closing {
+			buf.WriteString("<mo fence=\"true\">" + closing + "</mo>")
+		}
+		buf.WriteString("</mrow>")
+		return buf.String(), false
+	case "big", "Big", "bigg", "Bigg", "bigl", "Bigl", "biggl", "Biggl", "bigr", "Bigr", "biggr", "Biggr", "bigm", "Bigm":
+		return "<mo>" + p.parseDelimiter() + "</mo>", false
+	case "not":
+		p.skipSpace()
+		next, _ := p.parseAtom(true)
+		if !strings.HasSuffix(next, "</mo>") && !strings.HasSuffix(next, "</mi>") {
+			p.failed = true
+			return
+		}
+		return next[:len(next)-5] + "̸" + next[len(next)-5:], false
+	case "bmod":
+		return "<mo lspace=\"0.2222em\" rspace=\"0.2222em\">mod</mo>", false
+	case "pmod":
+		arg := p.parseArg()
+		return "<mspace width=\"1em\"></mspace><mo>(</mo><mi>mod</mi><mspace width=\"0.3333em\"></mspace>" + arg + "<mo>)</mo>", false
+	case "displaystyle", "textstyle", "scriptstyle", "nonumber", "notag":
+		return "", false
+	case "begin":
+		return p.parseEnvironment(), false
+	}
+
+	p.failed = true
+	return
+}
+
+// parseEnvironment 解析 \begin{env} ... \end{env} 矩阵环境。
+func (p *texParser) parseEnvironment() string {
+	env := p.parseName()
+	open, closing, align := "", "", ""
+	switch env {
+	case "matrix", "smallmatrix":
+	case "pmatrix":
+		open, closing = "(", ")"
+	case "bmatrix":
+		open, closing = "[", "]"
+	case "Bmatrix":
+		open, closing = "{", "}"
+	case "vmatrix":
+		open, closing = "|", "|"
+	case "Vmatrix":
+		open, closing = "‖", "‖"
+	case "cases":
+		open, align = "{", "left left"
+	case "aligned", "align", "align*", "split", "alignedat":
+		align = "right left"
+		if "alignedat" == env {
+			p.parseRawArg()
+		}
+	case "gathered", "gather", "gather*":
+	case "array":
+		p.parseRawArg() // 列格式
+	default:
+		p.failed = true
+		return ""
+	}
+
+	rows := p.parseRows(env)
+	if p.failed {
+		return ""
+	}
+	table := mtable(rows, align)
+	if "" == open && "" == closing {
+		return table
+	}
+	ret := "<mrow><mo fence=\"true\">" + open + "</mo>" + table
+	if "" != closing {
+		ret += "<mo fence=\"true\">" + closing + "</mo>"
+	}
+	return ret + "</mrow>"
+}
+
+// parseDelimiter 解析 \left、\right 等命令后的定界符，. 表示空定界符。
+func (p *texParser) parseDelimiter() string {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		p.failed = true
+		return ""
+	}
+	if p.consume(".") {
+		return ""
+	}
+	if p.consume(`\`) {
+		name := p.parseCommandName()
+		if s, ok := texDelimiters[name]; ok {
+			return s
+		}
+		p.failed = true
+		return ""
+	}
+	r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	if strings.ContainsRune("()[]|/", r) {
+		p.pos += size
+		return string(r)
+	}
+	if '<' == r || '>' == r {
+		p.pos += size
+		return map[rune]string{'<': "⟨", '>': "⟩"}[r]
+	}
+	p.failed = true
+	return ""
+}
+
+// parseCommandName 解析命令名，由字母组成的命令名或者单个非字母字符。
+func (p *texParser) parseCommandName() string {
+	start := p.pos
+	for p.pos < len(p.src) && isASCIILetter(p.src[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		_, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		p.pos += size
+	} else if p.pos < len(p.src) && '*' == p.src[p.pos] && "operatorname" == p.src[start:p.pos] {
+		p.pos++
+		return "operatorname"
+	}
+	return p.src[start:p.pos]
+}
+
+// parseName 解析 {name} 形式的环境名。
+func (p *texParser) parseName() string {
+	return strings.TrimSpace(p.parseRawArg())
+}
+
+// parseRawArg 返回 {...} 中未经解析的原始文本。
+func (p *texParser) parseRawArg() string {
+	p.skipSpace()
+	if !p.consume("{") {
+		p.failed = true
+		return ""
+	}
+	depth := 1
+	start := p.pos
+	for ; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case '\\':
+			if p.pos+1 < len(p.src) {
+				p.pos++ // 跳过转义字符，末尾的反斜杠不能越界
+			}
+		case '{':
+			depth++
+		case '}':
+			if depth--; 0 == depth {
+				p.pos++
+				return p.src[start : p.pos-1]
+			}
+		}
+	}
+	p.failed = true
+	return ""
+}
+
+func (p *texParser) skipSpace() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// consume 在当前位置为 token 时前进并返回 true，字母命令后不能紧跟字母。
+func (p *texParser) consume(token string) bool {
+	if !p.peekAny(token) {
+		return false
+	}
+	p.pos += len(token)
+	return true
+}
+
+func (p *texParser) peekAny(tokens ...string) bool {
+	for _, token := range tokens {
+		if !strings.HasPrefix(p.src[p.pos:], token) {
+			continue
+		}
+		end := p.pos + len(token)
+		if 1 < len(token) && '\\' == token[0] && isASCIILetter(token[len(token)-1]) && end < len(p.src) && isASCIILetter(p.src[end]) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// mi 生成标识符元素，使用当前字体变体。
+func (p *texParser) mi(s string) string {
+	if "normal" == p.variant {
+		return "<mi mathvariant=\"normal\">" + html.EscapeHTMLStr(s) + "</mi>"
+	}
+	return "<mi>" + p.variantText(s) + "</mi>"
+}
+
+// variantText 将字母和数字转换为当前字体变体对应的 Unicode 数学字母数字符号。
+func (p *texParser) variantText(s string) string {
+	if "" == p.variant || "normal" == p.variant {
+		return html.EscapeHTMLStr(s)
+	}
+	buf := &strings.Builder{}
+	for _, r := range s {
+		buf.WriteRune(mathVariantRune(p.variant, r))
+	}
+	return html.EscapeHTMLStr(buf.String())
+}
+
+// mathVariantRune 返回 r 在数学字母数字符号区（U+1D400 起）中对应的字符。
+func mathVariantRune(variant string, r rune) rune {
+	if exceptions, ok := mathVariantExceptions[variant]; ok {
+		if e, ok := exceptions[r]; ok {
+			return e
+		}
+	}
+	base, ok := mathVariantBases[variant]
+	if !ok {
+		return r
+	}
+	switch {
+	case 'A' <= r && 'Z' >= r:
+		return base + r - 'A'
+	case 'a' <= r && 'z' >= r:
+		return base + 26 + r - 'a'
+	case '0' <= r && '9' >= r:
+		if digits, ok := mathVariantDigits[variant]; ok {
+			return digits + r - '0'
+		}
+	}
+	return r
+}
+
+func mrow(items []string) string {
+	if 1 == len(items) {
+		return items[0]
+	}
+	return "<mrow>" + strings.Join(items, "") + "</mrow>"
+}
+
+func mtable(rows [][][]string, align string) string {
+	buf := &strings.Builder{}
+	buf.WriteString("<mtable")
+	if "" != align {
+		buf.WriteString(" columnalign=\"" + align + "\"")
+	}
+	buf.WriteString(">")
+	for _, row := range rows {
+		buf.WriteString("<mtr>")
+		for _, cell := range row {
+			buf.WriteString("<mtd>" + mrow(cell) + "</mtd>")
+		}
+		buf.WriteString("</mtr>")
+	}
+	buf.WriteString("</mtable>")
+	return buf.String()
+}
+
+func isASCIILetter(b byte) bool {
+	return ('a' <= b && 'z' >= b) || ('A' <= b && 'Z' >= b)
+}
+
+func isASCIIDigit(b byte) bool {
+	return '0' <= b && '9' >= b
+}
+
+var texOperatorChars = map[rune]string{
+	'+': "<mo>+</mo>", '-': "<mo>−</mo>", '=': "<mo>=</mo>", '<': "<mo>&lt;</mo>", '>': "<mo>&gt;</mo>",
+	'*': "<mo>∗</mo>", '/': "<mo>/</mo>", ',': "<mo separator=\"true\">,</mo>", ';': "<mo separator=\"true\">;</mo>",
+	':': "<mo>:</mo>", '!': "<mo>!</mo>", '?': "<mo>?</mo>", '.': "<mo>.</mo>", '|': "<mo stretchy=\"false\">|</mo>",
+	'(': "<mo stretchy=\"false\">(</mo>", ')': "<mo stretchy=\"false\">)</mo>",
+	'[': "<mo stretchy=\"false\">[</mo>", ']': "<mo stretchy=\"false\">]</mo>",
+}
+
+var texSpaces = map[string]string{
+	",": "0.1667em", ":": "0.2222em", ">": "0.2222em", ";": "0.2778em", " ": "0.3333em", "!": "-0.1667em",
+	"quad": "1em", "qquad": "2em", "enspace": "0.5em", "thinspace": "0.1667em", "medspace": "0.2222em", "thickspace": "0.2778em",
+}
+
+var texIdentifiers = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ", "varepsilon": "ε", "zeta": "ζ", "eta": "η",
+	"theta": "θ", "vartheta": "ϑ", "iota": "ι", "kappa": "κ", "lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ",
+	"omicron": "ο", "pi": "π", "varpi": "ϖ", "rho": "ρ", "varrho": "ϱ", "sigma": "σ", "varsigma": "ς", "tau": "τ",
+	"upsilon": "υ", "phi": "ϕ", "varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π", "Sigma": "Σ", "Upsilon": "Υ",
+	"Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+	"infty": "∞", "partial": "∂", "nabla": "∇", "emptyset": "∅", "varnothing": "∅", "aleph": "ℵ", "hbar": "ℏ",
+	"ell": "ℓ", "Re": "ℜ", "Im": "ℑ", "wp": "℘", "imath": "ı", "jmath": "ȷ", "%": "%", "$": "$", "#": "#", "&": "&amp;", "_": "_",
+}
+
+var texOperators = map[string]string{
+	"times": "×", "cdot": "⋅", "pm": "±", "mp": "∓", "div": "÷", "ast": "∗", "star": "⋆", "circ": "∘", "bullet": "∙",
+	"oplus": "⊕", "ominus": "⊖", "otimes": "⊗", "oslash": "⊘", "odot": "⊙", "setminus": "∖", "wedge": "∧", "land": "∧",
+	"vee": "∨", "lor": "∨", "cap": "∩", "cup": "∪", "sqcap": "⊓", "sqcup": "⊔", "uplus": "⊎", "amalg": "⨿",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠", "ll": "≪", "gg": "≫", "approx": "≈",
+	"equiv": "≡", "sim": "∼", "simeq": "≃", "cong": "≅", "propto": "∝", "prec": "≺", "succ": "≻", "preceq": "⪯",
+	"succeq": "⪰", "doteq": "≐", "models": "⊨", "vdash": "⊢", "dashv": "⊣", "perp": "⊥", "parallel": "∥", "mid": "∣",
+	"nmid": "∤", "in": "∈", "notin": "∉", "ni": "∋", "subset": "⊂", "supset": "⊃", "subseteq": "⊆", "supseteq": "⊇",
+	"subsetneq": "⊊", "supsetneq": "⊋", "sqsubseteq": "⊑", "sqsupseteq": "⊒", "forall": "∀", "exists": "∃",
+	"nexists": "∄", "neg": "¬", "lnot": "¬", "to": "→", "rightarrow": "→", "leftarrow": "←", "gets": "←",
+	"leftrightarrow": "↔", "Rightarrow": "⇒", "Leftarrow": "⇐", "Leftrightarrow": "⇔", "implies": "⟹",
+	"impliedby": "⟸", "iff": "⟺", "mapsto": "↦", "longrightarrow": "⟶", "longleftarrow": "⟵",
+	"Longrightarrow": "⟹", "Longleftarrow": "⟸", "longmapsto": "⟼", "uparrow": "↑", "downarrow": "↓",
+	"Uparrow": "⇑", "Downarrow": "⇓", "updownarrow": "↕", "nearrow": "↗", "searrow": "↘", "swarrow": "↙",
+	"nwarrow": "↖", "hookrightarrow": "↪", "hookleftarrow": "↩", "rightleftharpoons": "⇌",
+	"ldots": "…", "dots": "…", "cdots": "⋯", "vdots": "⋮", "ddots": "⋱", "angle": "∠", "triangle": "△",
+	"colon": ":", "prime": "′", "lbrace": "{", "rbrace": "}", "{": "{", "}": "}", "|": "‖", "langle": "⟨",
+	"rangle": "⟩", "lfloor": "⌊", "rfloor": "⌋", "lceil": "⌈", "rceil": "⌉", "vert": "|", "Vert": "‖",
+	"lvert": "|", "rvert": "|", "lVert": "‖", "rVert": "‖", "backslash": "∖", "therefore": "∴", "because": "∵",
+}
+
+var texDelimiters = map[string]string{
+	"{": "{", "}": "}", "|": "‖", "lbrace": "{", "rbrace": "}", "langle": "⟨", "rangle": "⟩", "lfloor": "⌊",
+	"rfloor": "⌋", "lceil": "⌈", "rceil": "⌉", "vert": "|", "Vert": "‖", "lvert": "|", "rvert": "|", "lVert": "‖",
+	"rVert": "‖", "backslash": "∖", "uparrow": "↑", "downarrow": "↓", "lbrack": "[", "rbrack": "]",
+}
+
+var texBigOperators = map[string]string{
+	"sum": "∑", "prod": "∏", "coprod": "∐", "int": "∫", "iint": "∬", "iiint": "∭", "oint": "∮",
+	"bigcup": "⋃", "bigcap": "⋂", "bigoplus": "⨁", "bigotimes": "⨂", "bigodot": "⨀", "bigvee": "⋁",
+	"bigwedge": "⋀", "bigsqcup": "⨆", "biguplus": "⨄",
+}
+
+var texFunctions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true, "sinh": true, "cosh": true,
+	"tanh": true, "coth": true, "arcsin": true, "arccos": true, "arctan": true, "exp": true, "log": true, "ln": true,
+	"lg": true, "lim": true, "liminf": true, "limsup": true, "max": true, "min": true, "sup": true, "inf": true,
+	"det": true, "gcd": true, "deg": true, "dim": true, "ker": true, "arg": true, "hom": true, "Pr": true,
+}
+
+var texLimitFunctions = map[string]bool{
+	"lim": true, "liminf": true, "limsup": true, "max": true, "min": true, "sup": true, "inf": true, "det": true,
+	"gcd": true, "Pr": true,
+}
+
+var texAccents = map[string]string{
+	"hat": "^", "widehat": "^", "bar": "¯", "overline": "‾", "vec": "→", "overrightarrow": "→",
+	"overleftarrow": "←", "dot": "˙", "ddot": "¨", "tilde": "~", "widetilde": "~", "check": "ˇ", "breve": "˘",
+	"acute": "´", "grave": "`",
+}
+
+var texVariants = map[string]string{
+	"mathrm": "normal", "mathbf": "bold", "boldsymbol": "bold-italic", "bm": "bold-italic", "mathit": "italic",
+	"mathbb": "double-struck", "mathcal": "script", "mathscr": "script", "mathfrak": "fraktur", "mathsf": "sans-serif",
+	"mathtt": "monospace",
+}
+
+var mathVariantBases = map[string]rune{
+	"bold": 0x1D400, "italic": 0x1D434, "bold-italic": 0x1D468, "script": 0x1D49C, "fraktur": 0x1D504,
+	"double-struck": 0x1D538, "sans-serif": 0x1D5A0, "monospace": 0x1D670,
+}
+
+var mathVariantDigits = map[string]rune{
+	"bold": 0x1D7CE, "double-struck": 0x1D7D8, "sans-serif": 0x1D7E2, "monospace": 0x1D7F6,
+}
+
+// mathVariantExceptions 是数学字母数字符号区中空缺、位于字母式符号区的字符。
+var mathVariantExceptions = map[string]map[rune]rune{
+	"italic":        {'h': 'ℎ'},
+	"script":        {'B': 'ℬ', 'E': 'ℰ', 'F': 'ℱ', 'H': 'ℋ', 'I': 'ℐ', 'L': 'ℒ', 'M': 'ℳ', 'R': 'ℛ', 'e': 'ℯ', 'g': 'ℊ', 'o': 'ℴ'},
+	"fraktur":       {'C': 'ℭ', 'H': 'ℌ', 'I': 'ℑ', 'R': 'ℜ', 'Z': 'ℨ'},
+	"double-struck": {'C': 'ℂ', 'H': 'ℍ', 'N': 'ℕ', 'P': 'ℙ', 'Q': 'ℚ', 'R': 'ℝ', 'Z': 'ℤ'},
+}
@@ -0,0 +1,508 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/88250/lute/html"
+)
+
+// MathML2TeX 将 MathML 元素 math 转换为 TeX 公式。
+//
+// 元素中包含 TeX 注解（<annotation encoding="application/x-tex">）时直接使用注解，否则按照 TeX2MathML 支持的子集进行转换。
+func MathML2TeX(math *html.Node) string {
+	if annotation := mathMLAnnotation(math); nil != annotation {
+		return strings.TrimSpace(mathMLText(annotation))
+	}
+	return strings.TrimSpace(mathML2TeX(math))
+}
+
+// mathMLAnnotation 返回 n 下的 TeX 注解元素。
+func mathMLAnnotation(n *html.Node) *html.Node {
+	if html.ElementNode == n.Type && "annotation" == n.Data {
+		if encoding := mathMLAttr(n, "encoding"); "application/x-tex" == encoding || "TeX" == encoding {
+			return n
+		}
+	}
+	for c := n.FirstChild; nil != c; c = c.NextSibling {
+		if ret := mathMLAnnotation(c); nil != ret {
+			return ret
+		}
+	}
+	return nil
+}
+
+func mathML2TeX(n *html.Node) string {
+	if html.TextNode == n.Type {
+		return ""
+	}
+
+	switch n.Data {
+	case "annotation", "annotation-xml", "none", "mprescripts":
+		return ""
+	case "semantics":
+		if nil == n.FirstChild {
+			return ""
+		}
+		return mathML2TeX(n.FirstChild)
+	case "mi":
+		return mathMLIdentifier(n)
+	case "mn":
+		return mathMLEscape(mathMLText(n))
+	case "mo":
+		return mathMLOperator(n)
+	case "mtext", "ms":
+		text := mathMLText(n)
+		if "" == strings.TrimSpace(text) {
+			return "\\ "
+		}
+		if "ms" == n.Data {
+			text = "\"" + text + "\""
+		}
+		return "\\text{" + mathMLEscape(text) + "}"
+	case "mspace":
+		return mathMLSpace(mathMLAttr(n, "width"))
+	case "msup":
+		args := mathMLArgs(n)
+		if 2 > len(args) {
+			return strings.Join(args, "")
+		}
+		return texBase(args[0]) + "^" + texGroup(args[1])
+	case "msub":
+		args := mathMLArgs(n)
+		if 2 > len(args) {
+			return strings.Join(args, "")
+		}
+		return texBase(args[0]) + "_" + texGroup(args[1])
+	case "msubsup", "munderover":
+		args := mathMLArgs(n)
+		if 3 > len(args) {
+			return strings.Join(args, "")
+		}
+		return texBase(args[0]) + "_" + texGroup(args[1]) + "^" + texGroup(args[2])
+	case "mfrac":
+		args := mathMLArgs(n)
+		if 2 > len(args) {
+			return strings.Join(args, "")
+		}
+		if thickness := mathMLAttr(n, "linethickness"); "0" == thickness || strings.HasPrefix(thickness, "0px") || strings.HasPrefix(thickness, "0em") {
+			return "\\genfrac{}{}{0pt}{}{" + args[0] + "}{" + args[1] + "}"
+		}
+		return "\\frac{" + args[0] + "}{" + args[1] + "}"
+	case "msqrt":
+		return "\\sqrt{" + texJoin(mathMLArgs(n)) + "}"
+	case "mroot":
+		args := mathMLArgs(n)
+		if 2 > len(args) {
+			return "\\sqrt{" + texJoin(args) + "}"
+		}
+		return "\\sqrt[" + args[1] + "]{" + args[0] + "}"
+	case "mover":
+		return mathMLOver(n)
+	case "munder":
+		return mathMLUnder(n)
+	case "mtable":
+		return mathMLTable(n)
+	case "mfenced":
+		open, close := "(", ")"
+		if v, ok := mathMLAttrOK(n, "open"); ok {
+			open = v
+		}
+		if v, ok := mathMLAttrOK(n, "close"); ok {
+			close = v
+		}
+		separator := ","
+		if v, ok := mathMLAttrOK(n, "separators"); ok {
+			separator = strings.TrimSpace(v)
+		}
+		return "\\left" + texDelimiter(open) + strings.Join(mathMLArgs(n), separator) + "\\right" + texDelimiter(close)
+	case "menclose":
+		content := texJoin(mathMLArgs(n))
+		notation := mathMLAttr(n, "notation")
+		switch {
+		case strings.Contains(notation, "box"):
+			return "\\boxed{" + content + "}"
+		case strings.Contains(notation, "updiagonalstrike"):
+			return "\\cancel{" + content + "}"
+		}
+		return content
+	case "mphantom":
+		return "\\phantom{" + texJoin(mathMLArgs(n)) + "}"
+	}
+
+	// math、mrow、mstyle、mpadded 等容器元素
+	args := mathMLArgs(n)
+	if 2 < len(args) {
+		first, last := n.FirstChild, n.LastChild
+		for nil != first && html.ElementNode != first.Type {
+			first = first.NextSibling
+		}
+		for nil != last && html.ElementNode != last.Type {
+			last = last.PrevSibling
+		}
+		if isMathMLFence(first) && isMathMLFence(last) && first != last {
+			return "\\left" + texDelimiter(mathMLText(first)) + texJoin(args[1:len(args)-1]) + "\\right" + texDelimiter(mathMLText(last))
+		}
+	}
+	return texJoin(args)
+}
+
+// mathMLArgs 返回 n 的子元素转换后的 TeX 公式列表。
+func mathMLArgs(n *html.Node) (ret []string) {
+	for c := n.FirstChild; nil != c; c = c.NextSibling {
+		if html.ElementNode != c.Type {
+			continue
+		}
+		ret = append(ret, mathML2TeX(c))
+	}
+	return
+}
+
+func mathMLIdentifier(n *html.Node) string {
+	text := strings.TrimSpace(mathMLText(n))
+	if "" == text {
+		return ""
+	}
+
+	variant := mathMLAttr(n, "mathvariant")
+	if 1 == utf8.RuneCountInString(text) {
+		r, _ := utf8.DecodeRuneInString(text)
+		if v, letter := mathVariantLetter(r); "" != v {
+			variant, text = v, string(letter)
+		}
+		if name, ok := mathMLSymbols[text]; ok {
+			if "normal" == variant {
+				variant = "" // 直立的大写希腊字母等符号
+			}
+			text = "\\" + name
+		} else {
+			text = mathMLEscape(text)
+		}
+		if command := mathMLVariantCommands[variant]; "" != command {
+			return "\\" + command + "{" + text + "}"
+		}
+		return text
+	}
+
+	if command := mathMLVariantCommands[variant]; "" != command && "normal" != variant {
+		return "\\" + command + "{" + mathMLEscape(text) + "}"
+	}
+	if texFunctions[text] {
+		return "\\" + text
+	}
+	return "\\mathrm{" + mathMLEscape(text) + "}"
+}
+
+func mathMLOperator(n *html.Node) string {
+	text := strings.TrimSpace(mathMLText(n))
+	switch text {
+	case "", "\u2061", "\u2062", "\u2063", "\u2064": // 不可见的函数应用、乘号、分隔符和加号
+		return ""
+	case "−":
+		return "-"
+	case "∗":
+		return "*"
+	case "′":
+		return "'"
+	}
+	if name, ok := mathMLSymbols[text]; ok {
+		return "\\" + name
+	}
+	if 1 < utf8.RuneCountInString(text) && isASCIILetter(text[0]) {
+		if texFunctions[text] {
+			return "\\" + text
+		}
+		return "\\operatorname{" + text + "}"
+	}
+	return mathMLEscape(text)
+}
+
+func mathMLOver(n *html.Node) string {
+	args := mathMLArgs(n)
+	if 2 > len(args) {
+		return texJoin(args)
+	}
+	base, over := args[0], args[1]
+	if overNode := mathMLChild(n, 1); nil != overNode && "mo" == overNode.Data {
+		switch mark := strings.TrimSpace(mathMLText(overNode)); mark {
+		case "⏞":
+			return "\\overbrace{" + base + "}"
+		case "‾", "_":
+			return "\\overline{" + base + "}"
+		default:
+			if command := mathMLAccents[mark]; "" != command {
+				return "\\" + command + "{" + base + "}"
+			}
+		}
+	}
+	if isTeXLimitsBase(base) {
+		return base + "^" + texGroup(over)
+	}
+	return "\\overset{" + over + "}{" + base + "}"
+}
+
+func mathMLUnder(n *html.Node) string {
+	args := mathMLArgs(n)
+	if 2 > len(args) {
+		return texJoin(args)
+	}
+	base, under := args[0], args[1]
+	if underNode := mathMLChild(n, 1); nil != underNode && "mo" == underNode.Data {
+		switch strings.TrimSpace(mathMLText(underNode)) {
+		case "⏟":
+			return "\\underbrace{" + base + "}"
+		case "_", "‾", "\u0332":
+			return "\\underline{" + base + "}"
+		}
+	}
+	if isTeXLimitsBase(base) {
+		return base + "_" + texGroup(under)
+	}
+	return "\\underset{" + under + "}{" + base + "}"
+}
+
+func mathMLTable(n *html.Node) string {
+	var rows []string
+	for tr := n.FirstChild; nil != tr; tr = tr.NextSibling {
+		if html.ElementNode != tr.Type {
+			continue
+		}
+		var cells []string
+		for td := tr.FirstChild; nil != td; td = td.NextSibling {
+			if html.ElementNode != td.Type {
+				continue
+			}
+			cells = append(cells, strings.TrimSpace(mathML2TeX(td)))
+		}
+		rows = append(rows, strings.Join(cells, " & "))
+	}
+	return "\\begin{matrix}" + strings.Join(rows, " \\\\ ") + "\\end{matrix}"
+}
+
+func mathMLSpace(width string) string {
+	width = strings.TrimSpace(width)
+	if !strings.HasSuffix(width, "em") {
+		return "\\ "
+	}
+	em, err := strconv.ParseFloat(strings.TrimSuffix(width, "em"), 64)
+	if nil != err {
+		return "\\ "
+	}
+	switch {
+	case 2 <= em:
+		return "\\qquad "
+	case 1 <= em:
+		return "\\quad "
+	case 0.27 <= em:
+		return "\\;"
+	case 0.22 <= em:
+		return "\\:"
+	case 0 < em:
+		return "\\,"
+	case 0 > em:
+		return "\\!"
+	}
+	return ""
+}
+
+// mathMLChild 返回 n 的第 i 个子元素。
+func mathMLChild(n *html.Node, i int) *html.Node {
+	for c := n.FirstChild; nil != c; c = c.NextSibling {
+		if html.ElementNode != c.Type {
+			continue
+		}
+		if 0 == i {
+			return c
+		}
+		i--
+	}
+	return nil
+}
+
+func mathMLText(n *html.Node) string {
+	buf := &strings.Builder{}
+	var text func(n *html.Node)
+	text = func(n *html.Node) {
+		if html.TextNode == n.Type {
+			buf.WriteString(n.Data)
+		}
+		for c := n.FirstChild; nil != c; c = c.NextSibling {
+			text(c)
+		}
+	}
+	text(n)
+	return buf.String()
+}
+
+func mathMLAttr(n *html.Node, name string) string {
+	ret, _ := mathMLAttrOK(n, name)
+	return ret
+}
+
+func mathMLAttrOK(n *html.Node, name string) (string, bool) {
+	for _, attr := range n.Attr {
+		if name == attr.Key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+func isMathMLFence(n *html.Node) bool {
+	if nil == n || "mo" != n.Data || "false" == mathMLAttr(n, "stretchy") {
+		return false
+	}
+	texClass := mathMLAttr(n, "data-mjx-texclass")
+	return "true" == mathMLAttr(n, "fence") || "OPEN" == texClass || "CLOSE" == texClass
+}
+
+// isTeXLimitsBase 判断 base 是否为大型运算符或者 \lim 这类上下标写在正上下方的函数。
+func isTeXLimitsBase(base string) bool {
+	name := strings.TrimPrefix(base, "\\")
+	if _, ok := texBigOperators[name]; ok {
+		return true
+	}
+	return texLimitFunctions[name]
+}
+
+func texDelimiter(d string) string {
+	d = strings.TrimSpace(d)
+	switch d {
+	case "":
+		return "."
+	case "{", "}":
+		return "\\" + d
+	}
+	if name, ok := mathMLSymbols[d]; ok {
+		return "\\" + name
+	}
+	return d
+}
+
+var texCommandSuffix = regexp.MustCompile(`\\[a-zA-Z]+$`)
+var texScriptSuffix = regexp.MustCompile(`[\^_][a-zA-Z0-9]$`)
+
+// texJoin 拼接 TeX 片段，在命令或者单字符上下标和紧随的字母之间插入空格。
+func texJoin(parts []string) string {
+	buf := &strings.Builder{}
+	for _, part := range parts {
+		if "" == part {
+			continue
+		}
+		if 0 < buf.Len() && (isASCIILetter(part[0]) || isASCIIDigit(part[0])) {
+			if prev := buf.String(); texScriptSuffix.MatchString(prev) || (isASCIILetter(part[0]) && texCommandSuffix.MatchString(prev)) {
+				buf.WriteByte(' ')
+			}
+		}
+		buf.WriteString(part)
+	}
+	return buf.String()
+}
+
+// texGroup 在需要时使用花括号包裹上下标等参数。
+func texGroup(s string) string {
+	if 1 == utf8.RuneCountInString(s) || ("" != s && texCommandSuffix.MatchString(s) && 0 == strings.LastIndex(s, "\\")) {
+		return s
+	}
+	return "{" + s + "}"
+}
+
+// texBase 在需要时使用花括号包裹上下标的底数。
+func texBase(s string) string {
+	if strings.HasPrefix(s, "\\left") && !strings.Contains(s[1:], "\\left") {
+		return s
+	}
+	return texGroup(s)
+}
+
+func mathMLEscape(s string) string {
+	buf := &strings.Builder{}
+	for _, r := range s {
+		switch r {
+		case '{', '}', '&', '%', '#', '$', '_':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case '\\':
+			buf.WriteString("\\backslash ")
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// mathVariantLetter 返回数学字母数字符号 r 对应的字体变体和 ASCII 字母或者数字。
+func mathVariantLetter(r rune) (variant string, letter rune) {
+	if 0x2100 > r || (0x214F < r && 0x1D400 > r) || 0x1D7FF < r {
+		return "", r
+	}
+	for v, exceptions := range mathVariantExceptions {
+		for l, e := range exceptions {
+			if e == r {
+				return v, l
+			}
+		}
+	}
+	for v, base := range mathVariantBases {
+		if base <= r && base+52 > r {
+			if offset := r - base; 26 > offset {
+				return v, 'A' + offset
+			} else {
+				return v, 'a' + offset - 26
+			}
+		}
+	}
+	for v, digits := range mathVariantDigits {
+		if digits <= r && digits+10 > r {
+			return v, '0' + r - digits
+		}
+	}
+	return "", r
+}
+
+// mathMLSymbols 是 Unicode 符号到 TeX 命令名的映射。
+var mathMLSymbols = map[string]string{}
+
+// mathMLAccents 是重音符号到 TeX 命令名的映射。
+var mathMLAccents = map[string]string{
+	"^": "hat", "ˆ": "hat", "¯": "bar", "ˉ": "bar", "→": "vec", "\u20d7": "vec", "˙": "dot", "¨": "ddot", "~": "tilde", "˜": "tilde",
+	"ˇ": "check", "˘": "breve", "´": "acute", "`": "grave",
+}
+
+// mathMLVariantCommands 是 mathvariant 到 TeX 字体命令名的映射。
+var mathMLVariantCommands = map[string]string{
+	"normal": "mathrm", "bold": "mathbf", "bold-italic": "boldsymbol", "double-struck": "mathbb", "script": "mathcal",
+	"fraktur": "mathfrak", "sans-serif": "mathsf", "monospace": "mathtt",
+}
+
+// mathMLPreferredSymbols 用于在多个 TeX 命令对应同一个符号时选择常用的命令。
+var mathMLPreferredSymbols = map[string]string{
+	"∅": "emptyset", "∖": "setminus", "∧": "wedge", "∨": "vee", "≤": "leq", "≥": "geq", "≠": "neq", "¬": "neg",
+	"→": "to", "←": "leftarrow", "⟹": "implies", "⟸": "impliedby", "…": "ldots", "‖": "|",
+}
+
+func init() {
+	for _, symbols := range []map[string]string{texIdentifiers, texOperators, texBigOperators} {
+		for name, symbol := range symbols {
+			if r, size := utf8.DecodeRuneInString(symbol); utf8.RuneSelf > r || size != len(symbol) {
+				continue
+			}
+			mathMLSymbols[symbol] = name
+		}
+	}
+	for symbol, name := range mathMLPreferredSymbols {
+		mathMLSymbols[symbol] = name
+	}
+}
@@ -0,0 +1,444 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/lex"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+// MediaWikiRenderer 描述了 MediaWiki 维基标记渲染器。
+type MediaWikiRenderer struct {
+	*BaseRenderer
+}
+
+// NewMediaWikiRenderer 创建一个 MediaWiki 维基标记渲染器。
+func NewMediaWikiRenderer(tree *parse.Tree, options *Options) Renderer {
+	ret := &MediaWikiRenderer{NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderText
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderInlineHTML
+	ret.RendererFuncs[ast.NodeCodeSpan] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeInlineMath] = ret.renderInlineMath
+	ret.RendererFuncs[ast.NodeCodeBlock] = ret.renderCodeBlock
+	ret.RendererFuncs[ast.NodeMathBlock] = ret.renderMathBlock
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeEmphasis] = ret.renderEmphasis
+	ret.RendererFuncs[ast.NodeStrong] = ret.renderStrong
+	ret.RendererFuncs[ast.NodeStrikethrough] = ret.renderStrikethrough
+	ret.RendererFuncs[ast.NodeUnderline] = ret.renderUnderline
+	ret.RendererFuncs[ast.NodeSup] = ret.renderSup
+	ret.RendererFuncs[ast.NodeSub] = ret.renderSub
+	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeTaskListItemMarker] = ret.renderTaskListItemMarker
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderSoftBreak
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeTableRow] = ret.renderTableRow
+	ret.RendererFuncs[ast.NodeTableCell] = ret.renderTableCell
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderEmojiUnicode
+	ret.RendererFuncs[ast.NodeEmojiImg] = ret.renderEmojiImg
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeFootnotesDefBlock] = ret.renderFootnotesDefBlock
+	ret.RendererFuncs[ast.NodeToC] = ret.renderToC
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+func (r *MediaWikiRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		buf := bytes.TrimSpace(r.Writer.Bytes())
+		r.Writer.Reset()
+		r.Write(buf)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering && !node.ParentIs(ast.NodeTableCell) {
+		if wikiListItemParagraphFollows(node) {
+			// 使用 <br /> 连接同一列表项中的段落，输出换行会结束列表项
+			r.Tag("br", nil, true)
+			return ast.WalkContinue
+		}
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		tokens := html.EscapeHTML(node.Tokens)
+		if node.ParentIs(ast.NodeTableCell) {
+			tokens = bytes.ReplaceAll(tokens, []byte("|"), []byte("&#124;"))
+		}
+		r.Write(tokens)
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderInlineHTML(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderCodeSpan(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("<code><nowiki>")
+		if content := node.ChildByType(ast.NodeCodeSpanContent); nil != content {
+			r.Write(html.EscapeHTML(content.Tokens))
+		}
+		r.WriteString("</nowiki></code>")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("<math>")
+		if content := node.ChildByType(ast.NodeInlineMathContent); nil != content {
+			r.Write(content.Tokens)
+		}
+		r.WriteString("</math>")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		lang, code := codeBlockLangCode(node)
+		code = bytes.TrimRight(code, "\n")
+		r.Newline()
+		if "" == lang {
+			r.WriteString("<pre>")
+			r.Write(html.EscapeHTML(code))
+			r.WriteString("</pre>")
+		} else {
+			r.WriteString("<syntaxhighlight lang=\"" + lang + "\">\n")
+			r.Write(code)
+			r.WriteString("\n</syntaxhighlight>")
+		}
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("<math display=\"block\">")
+		if content := node.ChildByType(ast.NodeMathBlockContent); nil != content {
+			r.Write(bytes.TrimSpace(content.Tokens))
+		}
+		r.WriteString("</math>")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderHTMLBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.Write(bytes.TrimSpace(node.Tokens))
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteString("''")
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteString("'''")
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderStrikethrough(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("s", entering)
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderUnderline(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("u", entering)
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderSup(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("sup", entering)
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderSub(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("sub", entering)
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderMark(node *ast.Node, entering bool) ast.WalkStatus {
+	r.inlineTag("mark", entering)
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) inlineTag(name string, entering bool) {
+	if entering {
+		r.Tag(name, nil, false)
+	} else {
+		r.Tag("/"+name, nil, false)
+	}
+}
+
+func (r *MediaWikiRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("<blockquote>\n")
+	} else {
+		r.Writer.Truncate(len(bytes.TrimRight(r.Writer.Bytes(), "\n")))
+		r.WriteString("\n</blockquote>")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	marker := strings.Repeat("=", node.HeadingLevel)
+	if entering {
+		r.Newline()
+		r.WriteString(marker + " ")
+	} else {
+		r.WriteString(" " + marker)
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering && !node.ParentIs(ast.NodeListItem) {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString(wikiListPrefix(node) + " ")
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderTaskListItemMarker(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if node.TaskListItemChecked {
+			r.WriteString("☑")
+		} else {
+			r.WriteString("☐")
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("----")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderHardBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Tag("br", nil, true)
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderSoftBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if node.ParentIs(ast.NodeListItem) || node.ParentIs(ast.NodeTableCell) {
+			r.WriteByte(lex.ItemSpace)
+			return ast.WalkContinue
+		}
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	dest := util.BytesToStr(r.resolveLinkPath(linkDest(r.Tree, node), LinkKindLink))
+	if 2 == node.LinkType {
+		if entering {
+			r.WriteString(dest)
+		}
+		return ast.WalkSkipChildren
+	}
+	if entering {
+		r.WriteString("[" + dest + " ")
+	} else {
+		r.WriteByte(']')
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest := util.BytesToStr(r.resolveLinkPath(linkDest(r.Tree, node), LinkKindImage))
+		if strings.Contains(dest, "://") {
+			// 外部图片直接输出地址，由 $wgAllowExternalImages 控制是否内联显示
+			r.WriteString(dest)
+			return ast.WalkSkipChildren
+		}
+
+		r.WriteString("[[File:" + path.Base(dest))
+		if alt := node.ChildByType(ast.NodeLinkText); nil != alt && 0 < len(alt.Tokens) {
+			r.WriteString("|alt=" + util.BytesToStr(alt.Tokens))
+		}
+		r.WriteString("]]")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("{| class=\"wikitable\"\n")
+	} else {
+		r.WriteString("|}")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderTableRow(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if ast.NodeTableHead != node.Parent.Type {
+			r.WriteString("|-\n")
+		}
+	} else {
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderTableCell(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		marker := "|"
+		if ast.NodeTableHead == node.Parent.Parent.Type {
+			marker = "!"
+		}
+		if nil != node.Previous {
+			marker = " " + marker + marker
+		}
+		r.WriteString(marker + " ")
+		switch node.TableCellAlign {
+		case 1:
+			r.WriteString("style=\"text-align: left;\" | ")
+		case 2:
+			r.WriteString("style=\"text-align: center;\" | ")
+		case 3:
+			r.WriteString("style=\"text-align: right;\" | ")
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *MediaWikiRenderer) renderEmojiUnicode(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if alias := node.ChildByType(ast.NodeEmojiAlias); nil != alias {
+			r.Write(alias.Tokens)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		// MediaWiki 使用 <ref> 就地定义脚注
+		r.WriteString("<ref>")
+		if _, def := r.Tree.FindFootnotesDef(node.FootnotesRefLabel); nil != def {
+			writer := r.Writer
+			r.Writer = &bytes.Buffer{}
+			for c := def.FirstChild; nil != c; c = c.Next {
+				ast.Walk(c, func(n *ast.Node, entering bool) ast.WalkStatus {
+					if render := r.RendererFuncs[n.Type]; nil != render {
+						return render(n, entering)
+					}
+					return r.DefaultRendererFunc(n, entering)
+				})
+			}
+			content := bytes.TrimSpace(r.Writer.Bytes())
+			r.Writer = writer
+			r.Write(content)
+		}
+		r.WriteString("</ref>")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderFootnotesDefBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("<references />")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *MediaWikiRenderer) renderToC(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("__TOC__")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
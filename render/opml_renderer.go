@@ -0,0 +1,76 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/parse"
+)
+
+// OPMLRenderer 描述了 OPML 2.0 大纲渲染器。
+//
+// 标题层级和嵌套列表转换为嵌套的 outline，备注使用 OmniOutliner、Workflowy 等大纲工具通用的 _note 属性，任务列表项使用 _status 属性。
+type OPMLRenderer struct {
+	*BaseRenderer
+}
+
+// NewOPMLRenderer 创建一个 OPML 2.0 大纲渲染器。
+func NewOPMLRenderer(tree *parse.Tree, options *Options) Renderer {
+	ret := &OPMLRenderer{NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	return ret
+}
+
+func (r *OPMLRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.WalkContinue
+	}
+
+	r.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	r.WriteString("<opml version=\"2.0\">\n")
+	r.WriteString("  <head>\n")
+	r.WriteString("    <title>" + xmlEscape(r.Tree.Name) + "</title>\n")
+	r.WriteString("  </head>\n")
+	r.WriteString("  <body>\n")
+	for _, o := range outline(node) {
+		r.renderOutline(o, 2)
+	}
+	r.WriteString("  </body>\n")
+	r.WriteString("</opml>\n")
+	return ast.WalkSkipChildren
+}
+
+func (r *OPMLRenderer) renderOutline(o *outlineNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	r.WriteString(indent + "<outline text=\"" + xmlEscape(o.text) + "\"")
+	if "" != o.note {
+		r.WriteString(" _note=\"" + xmlEscape(o.note) + "\"")
+	}
+	if o.task {
+		if o.checked {
+			r.WriteString(" _status=\"checked\"")
+		} else {
+			r.WriteString(" _status=\"unchecked\"")
+		}
+	}
+	if 1 > len(o.children) {
+		r.WriteString("/>\n")
+		return
+	}
+
+	r.WriteString(">\n")
+	for _, child := range o.children {
+		r.renderOutline(child, depth+1)
+	}
+	r.WriteString(indent + "</outline>\n")
+}
@@ -0,0 +1,528 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+// OrgRenderer 描述了 Emacs Org 渲染器。
+type OrgRenderer struct {
+	*BaseRenderer
+	NodeWriterStack []*bytes.Buffer // 节点输出缓冲栈
+}
+
+// NewOrgRenderer 创建一个 Emacs Org 渲染器。
+func NewOrgRenderer(tree *parse.Tree, options *Options) Renderer {
+	ret := &OrgRenderer{BaseRenderer: NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeSpan] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeCodeBlock] = ret.renderCodeBlock
+	ret.RendererFuncs[ast.NodeMathBlock] = ret.renderMathBlock
+	ret.RendererFuncs[ast.NodeInlineMath] = ret.renderInlineMath
+	ret.RendererFuncs[ast.NodeEmphasis] = ret.renderEmphasis
+	ret.RendererFuncs[ast.NodeStrong] = ret.renderStrong
+	ret.RendererFuncs[ast.NodeStrikethrough] = ret.renderStrikethrough
+	ret.RendererFuncs[ast.NodeUnderline] = ret.renderUnderline
+	ret.RendererFuncs[ast.NodeSup] = ret.renderSup
+	ret.RendererFuncs[ast.NodeSub] = ret.renderSub
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeTaskListItemMarker] = ret.renderTaskListItemMarker
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderSoftBreak
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeIFrame] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeVideo] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeAudio] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderInlineHTML
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeBlockRef] = ret.renderBlockRef
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeTableHead] = ret.renderTableHead
+	ret.RendererFuncs[ast.NodeTableRow] = ret.renderTableRow
+	ret.RendererFuncs[ast.NodeTableCell] = ret.renderTableCell
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderEmojiUnicode
+	ret.RendererFuncs[ast.NodeEmojiImg] = ret.renderEmojiImg
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeFootnotesDef] = ret.renderFootnotesDef
+	ret.RendererFuncs[ast.NodeToC] = ret.renderToC
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+func (r *OrgRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+		if title := node.IALAttr("title"); "" != title {
+			r.WriteString("#+TITLE: " + title + "\n\n")
+		}
+	} else {
+		buf := bytes.TrimSpace(r.Writer.Bytes())
+		r.Writer.Reset()
+		if 0 < len(buf) {
+			r.Write(buf)
+			r.WriteByte('\n')
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) pushWriter() {
+	r.Writer = &bytes.Buffer{}
+	r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+}
+
+func (r *OrgRenderer) popWriter() (ret []byte) {
+	writer := r.NodeWriterStack[len(r.NodeWriterStack)-1]
+	r.NodeWriterStack = r.NodeWriterStack[:len(r.NodeWriterStack)-1]
+	r.Writer = r.NodeWriterStack[len(r.NodeWriterStack)-1]
+	return bytes.TrimRight(writer.Bytes(), " \t\n")
+}
+
+// blockEnd 输出块结束换行，紧凑列表中的块之间不空行。
+func (r *OrgRenderer) blockEnd(node *ast.Node) {
+	if nil != node.Parent && ast.NodeListItem == node.Parent.Type && node.Parent.ListData.Tight {
+		r.WriteByte('\n')
+		return
+	}
+	r.WriteString("\n\n")
+}
+
+func (r *OrgRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderCodeSpan(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		content := node.ChildByType(ast.NodeCodeSpanContent)
+		if nil == content {
+			return ast.WalkSkipChildren
+		}
+		marker := "~"
+		if bytes.Contains(content.Tokens, []byte("~")) {
+			marker = "="
+		}
+		r.WriteString(marker)
+		r.Write(content.Tokens)
+		r.WriteString(marker)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var code []byte
+		lang := ""
+		if !node.IsFencedCodeBlock {
+			code = node.FirstChild.Tokens
+		} else {
+			if c := node.ChildByType(ast.NodeCodeBlockCode); nil != c {
+				code = c.Tokens
+			}
+			if info := strings.Fields(util.BytesToStr(node.CodeBlockInfo)); 0 < len(info) {
+				lang = info[0]
+			}
+		}
+		if "" == lang {
+			r.WriteString("#+BEGIN_EXAMPLE\n")
+		} else {
+			r.WriteString("#+BEGIN_SRC " + lang + "\n")
+		}
+		code = bytes.TrimRight(code, "\n")
+		if 0 < len(code) {
+			r.Write(code)
+			r.WriteByte('\n')
+		}
+		if "" == lang {
+			r.WriteString("#+END_EXAMPLE")
+		} else {
+			r.WriteString("#+END_SRC")
+		}
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("\\[\n")
+		if content := node.ChildByType(ast.NodeMathBlockContent); nil != content {
+			r.Write(bytes.TrimSpace(content.Tokens))
+			r.WriteByte('\n')
+		}
+		r.WriteString("\\]")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("\\(")
+		if content := node.ChildByType(ast.NodeInlineMathContent); nil != content {
+			r.Write(content.Tokens)
+		}
+		r.WriteString("\\)")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('/')
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('*')
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderStrikethrough(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('+')
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderUnderline(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('_')
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderSup(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("^{")
+	} else {
+		r.WriteByte('}')
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderSub(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("_{")
+	} else {
+		r.WriteByte('}')
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.pushWriter()
+	} else {
+		content := r.popWriter()
+		r.WriteString("#+BEGIN_QUOTE\n")
+		if 0 < len(content) {
+			r.Write(content)
+			r.WriteByte('\n')
+		}
+		r.WriteString("#+END_QUOTE")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString(strings.Repeat("*", node.HeadingLevel) + " ")
+		return ast.WalkContinue
+	}
+
+	if tags := node.IALAttr("tags"); "" != tags {
+		r.WriteString(" :" + strings.Join(strings.Split(tags, ","), ":") + ":")
+	}
+	r.WriteByte('\n')
+
+	var properties [][]string
+	for _, kv := range node.KramdownIAL {
+		if "tags" == kv[0] {
+			continue
+		}
+		properties = append(properties, kv)
+	}
+	if 0 < len(properties) {
+		r.WriteString(":PROPERTIES:\n")
+		for _, kv := range properties {
+			r.WriteString(":" + strings.ToUpper(kv[0]) + ": " + kv[1] + "\n")
+		}
+		r.WriteString(":END:\n")
+	}
+	r.WriteByte('\n')
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering && (nil == node.Parent || ast.NodeListItem != node.Parent.Type) {
+		r.WriteByte('\n')
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.pushWriter()
+		return ast.WalkContinue
+	}
+
+	content := r.popWriter()
+	marker := "-"
+	if 1 == node.ListData.Typ || (3 == node.ListData.Typ && 0 == node.ListData.BulletChar) {
+		delimiter := node.ListData.Delimiter
+		if ')' != delimiter {
+			delimiter = '.'
+		}
+		marker = strconv.Itoa(node.ListData.Num) + string(delimiter)
+	}
+	r.WriteString(marker + " ")
+	indent := strings.Repeat(" ", len(marker)+1)
+	lines := bytes.Split(content, []byte("\n"))
+	for i, line := range lines {
+		if 0 < i {
+			r.WriteByte('\n')
+			if 0 < len(line) {
+				r.WriteString(indent)
+			}
+		}
+		r.Write(line)
+	}
+	r.WriteByte('\n')
+	if !node.ListData.Tight && nil != node.Next {
+		r.WriteByte('\n')
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderTaskListItemMarker(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if node.TaskListItemChecked {
+			r.WriteString("[X] ")
+		} else {
+			r.WriteString("[ ] ")
+		}
+		if next := node.Next; nil != next && ast.NodeText == next.Type {
+			next.Tokens = bytes.TrimLeft(next.Tokens, " ")
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("-----")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderHardBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("\\\\\n")
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderSoftBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteByte('\n')
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderHTMLBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("#+BEGIN_EXPORT html\n")
+		r.Write(bytes.TrimSpace(node.Tokens))
+		r.WriteString("\n#+END_EXPORT")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderInlineHTML(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("@@html:")
+		r.Write(node.Tokens)
+		r.WriteString("@@")
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest := node.ChildByType(ast.NodeLinkDest)
+		if 3 == node.LinkType {
+			if def := r.Tree.FindLinkRefDefLink(node.LinkRefLabel); nil != def {
+				dest = def.ChildByType(ast.NodeLinkDest)
+			}
+		}
+		if nil == dest {
+			return ast.WalkContinue
+		}
+		destStr := util.BytesToStr(dest.Tokens)
+		if 2 == node.LinkType {
+			r.WriteString("[[" + destStr + "]]")
+			return ast.WalkSkipChildren
+		}
+		r.WriteString("[[" + destStr + "][")
+	} else {
+		r.WriteString("]]")
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if dest := node.ChildByType(ast.NodeLinkDest); nil != dest {
+			destStr := util.BytesToStr(dest.Tokens)
+			if !strings.Contains(destStr, "://") && !strings.HasPrefix(destStr, "file:") {
+				destStr = "file:" + destStr
+			}
+			r.WriteString("[[" + destStr + "]]")
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderBlockRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		id := node.ChildByType(ast.NodeBlockRefID)
+		if nil == id {
+			return ast.WalkSkipChildren
+		}
+		text := node.ChildByType(ast.NodeBlockRefText)
+		if nil == text {
+			text = node.ChildByType(ast.NodeBlockRefDynamicText)
+		}
+		r.WriteString("[[id:")
+		r.Write(id.Tokens)
+		if nil != text {
+			r.WriteString("][")
+			r.Write(text.Tokens)
+		}
+		r.WriteString("]]")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderTableHead(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		var cols []string
+		for cell := node.FirstChild.FirstChild; nil != cell; cell = cell.Next {
+			if ast.NodeTableCell == cell.Type {
+				cols = append(cols, "---")
+			}
+		}
+		r.WriteString("|" + strings.Join(cols, "+") + "|\n")
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderTableRow(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.WriteString("|")
+		if nil != node.Next || ast.NodeTableHead == node.Parent.Type {
+			r.WriteByte('\n')
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderTableCell(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("| ")
+	} else {
+		r.WriteByte(' ')
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderEmojiUnicode(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if alias := node.ChildByType(ast.NodeEmojiAlias); nil != alias {
+			r.Write(alias.Tokens)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("[fn:" + strings.TrimPrefix(util.BytesToStr(node.Tokens), "^") + "]")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *OrgRenderer) renderFootnotesDef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("[fn:" + strings.TrimPrefix(util.BytesToStr(node.Tokens), "^") + "] ")
+	}
+	return ast.WalkContinue
+}
+
+func (r *OrgRenderer) renderToC(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("#+TOC: headlines")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
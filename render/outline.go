@@ -0,0 +1,161 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/editor"
+)
+
+// outlineNode 描述了大纲（OPML、FreeMind 脑图）中的一个节点。
+type outlineNode struct {
+	text     string         // 节点文本
+	note     string         // 备注，多个段落之间使用空行分隔
+	task     bool           // 是否为任务列表项
+	checked  bool           // 任务列表项是否已完成
+	children []*outlineNode // 子节点
+}
+
+// appendNote 将 text 作为一个段落追加到备注中。
+func (o *outlineNode) appendNote(text string) {
+	if "" == text {
+		return
+	}
+	if "" != o.note {
+		o.note += "\n\n"
+	}
+	o.note += text
+}
+
+// outline 将文档 root 转换为大纲。
+//
+// 标题按照级别嵌套，标题下的列表项作为标题的子节点，列表项的第一个段落是节点文本，其他块作为备注；不在列表中的块作为所属标题的备注。
+func outline(root *ast.Node) (ret []*outlineNode) {
+	type section struct {
+		level int
+		node  *outlineNode
+	}
+	var sections []*section
+	appendChild := func(o *outlineNode) {
+		if 0 < len(sections) {
+			parent := sections[len(sections)-1].node
+			parent.children = append(parent.children, o)
+			return
+		}
+		ret = append(ret, o)
+	}
+
+	for c := root.FirstChild; nil != c; c = c.Next {
+		switch c.Type {
+		case ast.NodeHeading:
+			for 0 < len(sections) && sections[len(sections)-1].level >= c.HeadingLevel {
+				sections = sections[:len(sections)-1]
+			}
+			o := &outlineNode{text: outlineText(c, false)}
+			appendChild(o)
+			sections = append(sections, &section{c.HeadingLevel, o})
+		case ast.NodeList:
+			for _, o := range outlineList(c) {
+				appendChild(o)
+			}
+		case ast.NodeKramdownBlockIAL, ast.NodeYamlFrontMatter, ast.NodeLinkRefDefBlock, ast.NodeThematicBreak, ast.NodeToC:
+		default:
+			text := outlineText(c, true)
+			if 0 < len(sections) {
+				sections[len(sections)-1].node.appendNote(text)
+			} else if "" != text {
+				ret = append(ret, &outlineNode{text: text})
+			}
+		}
+	}
+	return
+}
+
+// outlineList 将列表 list 的列表项转换为大纲节点。
+func outlineList(list *ast.Node) (ret []*outlineNode) {
+	for li := list.FirstChild; nil != li; li = li.Next {
+		if ast.NodeListItem != li.Type {
+			continue
+		}
+
+		o := &outlineNode{}
+		first := true
+		for c := li.FirstChild; nil != c; c = c.Next {
+			switch c.Type {
+			case ast.NodeList:
+				o.children = append(o.children, outlineList(c)...)
+			case ast.NodeKramdownBlockIAL:
+			default:
+				if first {
+					if marker := c.ChildByType(ast.NodeTaskListItemMarker); nil != marker {
+						o.task, o.checked = true, marker.TaskListItemChecked
+					}
+					o.text = outlineText(c, false)
+					first = false
+					continue
+				}
+				o.appendNote(outlineText(c, true))
+			}
+		}
+		ret = append(ret, o)
+	}
+	return
+}
+
+// outlineText 返回块 node 的纯文本，multiline 为 false 时将换行替换为空格。
+func outlineText(node *ast.Node, multiline bool) string {
+	buf := &bytes.Buffer{}
+	ast.Walk(node, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+
+		switch n.Type {
+		case ast.NodeTag:
+			buf.WriteString("#" + n.Text() + "#")
+			return ast.WalkSkipChildren
+		case ast.NodeKramdownBlockIAL, ast.NodeKramdownSpanIAL, ast.NodeTaskListItemMarker:
+			return ast.WalkSkipChildren
+		case ast.NodeSoftBreak, ast.NodeHardBreak:
+			buf.WriteByte('\n')
+		case ast.NodeText, ast.NodeLinkText, ast.NodeCodeSpanContent, ast.NodeCodeBlockCode, ast.NodeMathBlockContent, ast.NodeInlineMathContent,
+			ast.NodeEmojiUnicode, ast.NodeBackslashContent, ast.NodeHTMLEntity, ast.NodeBlockRefText, ast.NodeBlockRefDynamicText:
+			buf.Write(n.Tokens)
+		}
+		return ast.WalkContinue
+	})
+
+	ret := strings.ReplaceAll(buf.String(), editor.Caret, "")
+	ret = strings.TrimSpace(ret)
+	if !multiline {
+		ret = strings.Join(strings.Fields(ret), " ")
+	}
+	return ret
+}
+
+// outlineRoot 返回脑图的中心节点：大纲只有一个顶层节点时使用该节点，否则使用以 title 为文本的节点作为中心节点。
+func outlineRoot(nodes []*outlineNode, title string) *outlineNode {
+	if 1 == len(nodes) {
+		return nodes[0]
+	}
+	return &outlineNode{text: title, children: nodes}
+}
+
+// xmlEscape 转义 XML 文本和属性值。
+func xmlEscape(str string) string {
+	buf := &bytes.Buffer{}
+	xml.EscapeText(buf, []byte(str))
+	return buf.String()
+}
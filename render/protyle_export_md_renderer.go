@@ -247,7 +247,7 @@ func (r *ProtyleExportMdRenderer) renderMdMarker(node *ast.Node, entering bool)
 			switch typ {
 			case "a":
 				href := node.TextMarkAHref
-				href = string(r.LinkPath([]byte(href)))
+				href = string(r.resolveLinkPath([]byte(href), LinkKindLink))
 				href = html.UnescapeHTMLStr(href)
 				ret += "["
 
@@ -257,6 +257,10 @@ func (r *ProtyleExportMdRenderer) renderMdMarker(node *ast.Node, entering bool)
 
 				return
 			case "block-ref":
+				if link := r.refMdLink(node); "" != link {
+					ret += link
+					break
+				}
 				node.TextMarkTextContent = strings.ReplaceAll(node.TextMarkTextContent, "'", "&apos;")
 				ret += "((" + node.TextMarkBlockRefID
 				if "s" == node.TextMarkBlockRefSubtype {
@@ -266,6 +270,10 @@ func (r *ProtyleExportMdRenderer) renderMdMarker(node *ast.Node, entering bool)
 				}
 				ret += "))"
 			case "file-annotation-ref":
+				if link := r.refMdLink(node); "" != link {
+					ret += link
+					break
+				}
 				node.TextMarkTextContent = strings.ReplaceAll(node.TextMarkTextContent, "'", "&apos;")
 				ret += "<<" + node.TextMarkFileAnnotationRefID
 				ret += " \"" + node.TextMarkTextContent + "\""
@@ -289,7 +297,7 @@ func (r *ProtyleExportMdRenderer) renderMdMarker(node *ast.Node, entering bool)
 			switch typ {
 			case "a":
 				href := node.TextMarkAHref
-				href = string(r.LinkPath([]byte(href)))
+				href = string(r.resolveLinkPath([]byte(href), LinkKindLink))
 				href = html.UnescapeHTMLStr(href)
 				ret += string(lex.EscapeProtyleMarkers([]byte(node.TextMarkTextContent)))
 				for _, typ := range types {
@@ -313,6 +321,15 @@ func (r *ProtyleExportMdRenderer) renderMdMarker(node *ast.Node, entering bool)
 	return
 }
 
+// refMdLink 返回块引用、文件注解引用经 LinkResolver 改写后的 Markdown 链接 [text](url)，未改写时返回空字符串。
+func (r *ProtyleExportMdRenderer) refMdLink(node *ast.Node) string {
+	href, _ := r.textMarkRefLink(node)
+	if 1 > len(href) {
+		return ""
+	}
+	return "[" + string(lex.EscapeProtyleMarkers([]byte(node.TextMarkTextContent))) + "](" + string(href) + ")"
+}
+
 func reverse(ss []string) {
 	last := len(ss) - 1
 	for i := 0; i < len(ss)/2; i++ {
@@ -324,7 +341,7 @@ func (r *ProtyleExportMdRenderer) renderMdMarker0(node *ast.Node, currentTextmar
 	switch currentTextmarkType {
 	case "a":
 		href := node.TextMarkAHref
-		href = string(r.LinkPath([]byte(href)))
+		href = string(r.resolveLinkPath([]byte(href), LinkKindLink))
 		href = html.UnescapeHTMLStr(href)
 		if entering {
 			ret += "[" + node.TextMarkTextContent + "](" + href
@@ -335,6 +352,10 @@ func (r *ProtyleExportMdRenderer) renderMdMarker0(node *ast.Node, currentTextmar
 		}
 	case "block-ref":
 		if entering {
+			if link := r.refMdLink(node); "" != link {
+				ret += link
+				break
+			}
 			node.TextMarkTextContent = strings.ReplaceAll(node.TextMarkTextContent, "'", "&apos;")
 			ret += "((" + node.TextMarkBlockRefID
 			if "s" == node.TextMarkBlockRefSubtype {
@@ -346,6 +367,10 @@ func (r *ProtyleExportMdRenderer) renderMdMarker0(node *ast.Node, currentTextmar
 		}
 	case "file-annotation-ref":
 		if entering {
+			if link := r.refMdLink(node); "" != link {
+				ret += link
+				break
+			}
 			node.TextMarkTextContent = strings.ReplaceAll(node.TextMarkTextContent, "'", "&apos;")
 			ret += "<<" + node.TextMarkFileAnnotationRefID
 			ret += " \"" + node.TextMarkTextContent + "\""
@@ -1131,7 +1156,7 @@ func (r *ProtyleExportMdRenderer) renderLinkTitle(node *ast.Node, entering bool)
 func (r *ProtyleExportMdRenderer) renderLinkDest(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		tokens := node.Tokens
-		tokens = r.LinkPath(tokens)
+		tokens = r.resolveLinkPath(tokens, linkDestKind(node))
 		r.Write(tokens)
 	}
 	return ast.WalkContinue
@@ -310,7 +310,7 @@ func (r *ProtyleExportRenderer) renderVideo(node *ast.Node, entering bool) ast.W
 			tokens = sanitize(tokens)
 		}
 		dataSrc := r.tagSrc(tokens)
-		src := r.LinkPath(dataSrc)
+		src := r.resolveLinkPath(dataSrc, LinkKindImage)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
 		r.Write(tokens)
 	} else {
@@ -335,7 +335,7 @@ func (r *ProtyleExportRenderer) renderAudio(node *ast.Node, entering bool) ast.W
 			tokens = sanitize(tokens)
 		}
 		dataSrc := r.tagSrc(tokens)
-		src := r.LinkPath(dataSrc)
+		src := r.resolveLinkPath(dataSrc, LinkKindImage)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
 		r.Write(tokens)
 		r.WriteString(editor.Zwsp)
@@ -359,7 +359,7 @@ func (r *ProtyleExportRenderer) renderWidget(node *ast.Node, entering bool) ast.
 			tokens = sanitize(tokens)
 		}
 		dataSrc := r.tagSrc(tokens)
-		src := r.LinkPath(dataSrc)
+		src := r.resolveLinkPath(dataSrc, LinkKindImage)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
 		r.Write(tokens)
 	} else {
@@ -383,7 +383,7 @@ func (r *ProtyleExportRenderer) renderIFrame(node *ast.Node, entering bool) ast.
 			tokens = sanitize(tokens)
 		}
 		dataSrc := r.tagSrc(tokens)
-		src := r.LinkPath(dataSrc)
+		src := r.resolveLinkPath(dataSrc, LinkKindImage)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
 		r.Write(tokens)
 	} else {
@@ -927,7 +927,7 @@ func (r *ProtyleExportRenderer) renderEmojiAlias(node *ast.Node, entering bool)
 func (r *ProtyleExportRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		dataSrc := r.tagSrc(node.Tokens)
-		src := r.LinkPath(dataSrc[1:])
+		src := r.resolveLinkPath(dataSrc[1:], LinkKindImage)
 		tokens := bytes.ReplaceAll(node.Tokens, dataSrc, src)
 		r.Write(tokens)
 	}
@@ -1235,7 +1235,7 @@ func (r *ProtyleExportRenderer) renderImage(node *ast.Node, entering bool) ast.W
 		destTokens = bytes.ReplaceAll(destTokens, editor.CaretTokens, nil)
 		dataSrcTokens := destTokens
 		dataSrc := util.BytesToStr(dataSrcTokens)
-		src := util.BytesToStr(r.LinkPath(destTokens))
+		src := util.BytesToStr(r.resolveLinkPath(destTokens, LinkKindImage))
 		attrs := [][]string{{"src", src}, {"data-src", dataSrc}}
 		alt := node.ChildByType(ast.NodeLinkText)
 		if nil != alt && 0 < len(alt.Tokens) {
@@ -1292,7 +1292,7 @@ func (r *ProtyleExportRenderer) renderLink(node *ast.Node, entering bool) ast.Wa
 			destTokens = sanitize(destTokens)
 		}
 
-		destTokens = r.LinkPath(destTokens)
+		destTokens = r.resolveLinkPath(destTokens, LinkKindLink)
 
 		caretInDest := bytes.Contains(destTokens, editor.CaretTokens)
 		if caretInDest {
@@ -1745,7 +1745,7 @@ func (r *ProtyleExportRenderer) renderTextMarkAttrs(node *ast.Node) (attrs [][]s
 			attrs = append(attrs, []string{"data-id", node.TextMarkBlockRefID})
 		} else if "a" == typ {
 			href := node.TextMarkAHref
-			href = string(r.LinkPath([]byte(href)))
+			href = string(r.resolveLinkPath([]byte(href), LinkKindLink))
 
 			attrs = append(attrs, []string{"data-href", href})
 			if "" != node.TextMarkATitle {
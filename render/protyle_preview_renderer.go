@@ -972,13 +972,15 @@ func (r *ProtylePreviewRenderer) renderImage(node *ast.Node, entering bool) ast.
 			r.Tag("span", attrs, false)
 			r.WriteString("<img src=\"")
 			destTokens := node.ChildByType(ast.NodeLinkDest).Tokens
-			destTokens = r.LinkPath(destTokens)
+			destTokens, linkAttrs := r.ResolveLink(destTokens, LinkKindImage)
 			if "" != r.Options.ImageLazyLoading {
 				r.Write(html.EscapeHTML(util.StrToBytes(r.Options.ImageLazyLoading)))
 				r.WriteString("\" data-src=\"")
 			}
 			r.Write(html.EscapeHTML(destTokens))
-			r.WriteString("\" alt=\"")
+			r.WriteByte(lex.ItemDoublequote)
+			r.WriteString(linkAttrsStr(linkAttrs))
+			r.WriteString(" alt=\"")
 		}
 		r.DisableTags++
 		return ast.WalkContinue
@@ -1032,11 +1034,12 @@ func (r *ProtylePreviewRenderer) renderLink(node *ast.Node, entering bool) ast.W
 				destTokens = nil
 			}
 		}
-		destTokens = r.LinkPath(destTokens)
+		destTokens, linkAttrs := r.ResolveLink(destTokens, LinkKindLink)
 		attrs := [][]string{{"href", util.BytesToStr(html.EscapeHTML(destTokens))}}
 		if title := node.ChildByType(ast.NodeLinkTitle); nil != title && nil != title.Tokens {
 			attrs = append(attrs, []string{"title", util.BytesToStr(html.EscapeHTML(title.Tokens))})
 		}
+		attrs = append(attrs, escapeLinkAttrs(linkAttrs)...)
 		r.Tag("a", attrs, false)
 	} else {
 		r.Tag("/a", nil, false)
@@ -1246,7 +1249,7 @@ func (r *ProtylePreviewRenderer) renderHeading(node *ast.Node, entering bool) as
 		r.WriteString("<h" + level)
 		id := node.ID
 		if "" == id {
-			id = HeadingID(node)
+			id = r.headingID(node)
 		}
 		if r.Options.ToC || r.Options.HeadingID || r.Options.KramdownBlockIAL {
 			r.WriteString(" id=\"" + id + "\"")
@@ -1265,7 +1268,7 @@ func (r *ProtylePreviewRenderer) renderHeading(node *ast.Node, entering bool) as
 		r.WriteString(">")
 	} else {
 		if r.Options.HeadingAnchor {
-			id := HeadingID(node)
+			id := r.headingID(node)
 			r.Tag("a", [][]string{{"id", "vditorAnchor-" + id}, {"class", "vditor-anchor"}, {"href", "#" + id}}, false)
 			r.WriteString(`<svg viewBox="0 0 16 16" version="1.1" width="16" height="16"><path fill-rule="evenodd" d="M4 9h1v1H4c-1.5 0-3-1.69-3-3.5S2.55 3 4 3h4c1.45 0 3 1.69 3 3.5 0 1.41-.91 2.72-2 3.25V8.59c.58-.45 1-1.27 1-2.09C10 5.22 8.98 4 8 4H4c-.98 0-2 1.22-2 2.5S3 9 4 9zm9-3h-1v1h1c1 0 2 1.22 2 2.5S13.98 12 13 12H9c-.98 0-2-1.22-2-2.5 0-.83.42-1.64 1-2.09V6.25c-1.09.53-2 1.84-2 3.25C6 11.31 7.55 13 9 13h4c1.45 0 3-1.69 3-3.5S14.5 6 13 6z"></path></svg>`)
 			r.Tag("/a", nil, false)
@@ -1382,7 +1385,7 @@ func (r *ProtylePreviewRenderer) renderTextMarkAttrs(node *ast.Node) (attrs [][]
 			attrs = append(attrs, []string{"data-id", node.TextMarkBlockRefID})
 		} else if "a" == typ {
 			href := node.TextMarkAHref
-			href = string(r.LinkPath([]byte(href)))
+			href = string(r.resolveLinkPath([]byte(href), LinkKindLink))
 
 			attrs = append(attrs, []string{"data-href", href})
 			if "" != node.TextMarkATitle {
@@ -344,7 +344,7 @@ func (r *ProtyleRenderer) renderVideo(node *ast.Node, entering bool) ast.WalkSta
 			tokens = sanitize(tokens)
 		}
 		dataSrc := r.tagSrc(tokens)
-		src := r.LinkPath(dataSrc)
+		src := r.resolveLinkPath(dataSrc, LinkKindImage)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
 		r.Write(tokens)
 	} else {
@@ -368,7 +368,7 @@ func (r *ProtyleRenderer) renderAudio(node *ast.Node, entering bool) ast.WalkSta
 			tokens = sanitize(tokens)
 		}
 		dataSrc := r.tagSrc(tokens)
-		src := r.LinkPath(dataSrc)
+		src := r.resolveLinkPath(dataSrc, LinkKindImage)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
 		r.Write(tokens)
 		r.WriteString(editor.Zwsp)
@@ -392,7 +392,7 @@ func (r *ProtyleRenderer) renderWidget(node *ast.Node, entering bool) ast.WalkSt
 			tokens = sanitize(tokens)
 		}
 		dataSrc := r.tagSrc(tokens)
-		src := r.LinkPath(dataSrc)
+		src := r.resolveLinkPath(dataSrc, LinkKindImage)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
 		r.Write(tokens)
 	} else {
@@ -416,7 +416,7 @@ func (r *ProtyleRenderer) renderIFrame(node *ast.Node, entering bool) ast.WalkSt
 			tokens = sanitize(tokens)
 		}
 		dataSrc := r.tagSrc(tokens)
-		src := r.LinkPath(dataSrc)
+		src := r.resolveLinkPath(dataSrc, LinkKindImage)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
 		r.Write(tokens)
 	} else {
@@ -1262,7 +1262,7 @@ func (r *ProtyleRenderer) renderImage(node *ast.Node, entering bool) ast.WalkSta
 		destTokens = bytes.ReplaceAll(destTokens, editor.CaretTokens, nil)
 		dataSrcTokens := destTokens
 		dataSrc := util.BytesToStr(dataSrcTokens)
-		src := util.BytesToStr(r.LinkPath(destTokens))
+		src := util.BytesToStr(r.resolveLinkPath(destTokens, LinkKindImage))
 		attrs := [][]string{{"src", src}, {"data-src", dataSrc}}
 		alt := node.ChildByType(ast.NodeLinkText)
 		if nil != alt && 0 < len(alt.Tokens) {
@@ -1334,7 +1334,7 @@ func (r *ProtyleRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStat
 				destTokens = nil
 			}
 		}
-		destTokens = r.LinkPath(destTokens)
+		destTokens = r.resolveLinkPath(destTokens, LinkKindLink)
 
 		caretInDest := bytes.Contains(destTokens, editor.CaretTokens)
 		if caretInDest {
@@ -1836,7 +1836,7 @@ func (r *ProtyleRenderer) renderTextMarkAttrs(node *ast.Node) (attrs [][]string)
 			attrs = append(attrs, []string{"data-id", node.TextMarkBlockRefID})
 		} else if "a" == typ {
 			href := node.TextMarkAHref
-			href = string(r.LinkPath([]byte(href)))
+			href = string(r.resolveLinkPath([]byte(href), LinkKindLink))
 			if node.ParentIs(ast.NodeTableCell) {
 				href = strings.ReplaceAll(href, "\\|", "|")
 			}
@@ -87,6 +87,20 @@ type Options struct {
 	Terms map[string]string
 	// ToC 设置是否打开“目录”支持。
 	ToC bool
+	// ToCMinLevel 设置目录中包含的最小标题级别，默认为 1。
+	ToCMinLevel int
+	// ToCMaxLevel 设置目录中包含的最大标题级别，默认为 6。
+	ToCMaxLevel int
+	// ToCOrdered 设置目录是否使用有序列表。
+	ToCOrdered bool
+	// ToCNumbering 设置是否在目录项前输出章节编号，比如 1.2.3。
+	ToCNumbering bool
+	// ToCNumberHeadings 设置是否在标题前输出章节编号，仅在 HTML 渲染器 HtmlRenderer 中支持。
+	ToCNumberHeadings bool
+	// ToCNestedHeadings 设置目录是否包含嵌套在其他块（比如引述、超级块）中的标题。
+	ToCNestedHeadings bool
+	// ToCNav 设置是否将目录渲染为 <nav> 元素，目录项使用指向标题的链接。
+	ToCNav bool
 	// HeadingID 设置是否打开“自定义标题 ID”支持。
 	HeadingID bool
 	// KramdownIALIDRenderName 设置 kramdown 内联属性列表中出现 id 属性时渲染 id 属性用的 name(key) 名称，默认为 "id"。
@@ -109,6 +123,8 @@ type Options struct {
 	// 比如 LinkPrefix 设置为 http://domain.com，对于使用绝对路径的 ![foo](/local/path/bar.png) 则渲染为 <img src="http://domain.com/local/path/bar.png" alt="foo" />；
 	// 在 LinkBase 和 LinkPrefix 同时设置的情况下，会先处理 LinkBase 逻辑，最后再在 LinkBase 处理结果上加上 LinkPrefix。
 	LinkPrefix string
+	// LinkResolver 设置链接、图片、块引用和文件注解引用地址的改写策略，设置后 LinkBase 和 LinkPrefix 不再生效。
+	LinkResolver LinkResolver
 	// NodeIndexStart 用于设置块级节点编号起始值。
 	NodeIndexStart int
 	// ProtyleContenteditable 设置 Protyle 渲染时标签中的 contenteditable 属性。
@@ -119,6 +135,24 @@ type Options struct {
 	ProtyleMarkNetImg bool
 	// Spellcheck 设置是否启用拼写检查
 	Spellcheck bool
+	// SlidesHeadingLevel 设置幻灯片渲染器 SlidesRenderer 按标题拆分幻灯片的级别，0 表示仅按分隔线拆分。
+	// 该级别的标题开始一张新幻灯片，更高级别（数值更小）的标题开始一组幻灯片，组内幻灯片纵向排列。
+	SlidesHeadingLevel int
+	// SlidesRevealJS 设置 reveal.js 的资源地址，比如 https://cdn.jsdelivr.net/npm/reveal.js@5。
+	// 非空时 SlidesRenderer 输出引用该地址下样式和脚本并完成初始化的完整 HTML 文档，为空时仅输出幻灯片 HTML 片段。
+	SlidesRevealJS string
+	// Figure 设置是否将独立成段的图片渲染为带标题的 figure，并支持表格前后的 "Table: 标题" 段落作为表格标题。
+	Figure bool
+	// FigureNumbering 设置是否对图片、表格和带 eq: 标签的公式块自动编号，并将 @fig:label 形式的交叉引用渲染为链接。
+	// 图片和表格仅在 Figure 开启时编号，公式块的编号以 (n) 的形式显示在公式后。
+	FigureNumbering bool
+	// MathML 设置是否在渲染时将 TeX 公式转换为 MathML，转换失败的公式保持原样输出。
+	MathML bool
+	// HeadingSlugger 设置标题 ID 生成策略，重复的 ID 使用 -1、-2 等数字后缀区分。
+	// 为 nil 时使用默认策略：将非字母数字字符替换为 -，重复的 ID 追加 -。
+	HeadingSlugger HeadingSlugger
+	// HeadingSections 设置是否按标题层级将文档顶层内容嵌套到 <section> 中，标题的 IAL 属性渲染到 section 上。
+	HeadingSections bool
 }
 
 func NewOptions() *Options {
@@ -138,6 +172,8 @@ func NewOptions() *Options {
 		ChineseParagraphBeginningSpace: false,
 		FixTermTypo:                    false,
 		ToC:                            false,
+		ToCMinLevel:                    1,
+		ToCMaxLevel:                    6,
 		HeadingID:                      false,
 		KramdownIALIDRenderName:        "id",
 		GFMTaskListItemClass:           "vditor-task",
@@ -166,6 +202,7 @@ type BaseRenderer struct {
 	DisableTags         int                              // 标签嵌套计数器，用于判断不可能出现标签嵌套的情况，比如语法树允许图片节点包含链接节点，但是 HTML <img> 不能包含 <a>
 	FootnotesDefs       []*ast.Node                      // 脚注定义集
 	RenderingFootnotes  bool                             // 是否正在渲染脚注定义
+	headingNumbers      map[*ast.Node]string             // 标题章节编号
 }
 
 // NewBaseRenderer 构造一个 BaseRenderer。
@@ -181,8 +218,14 @@ func (r *BaseRenderer) Render() (output []byte) {
 	r.LastOut = lex.ItemNewline
 	r.Writer = &bytes.Buffer{}
 	r.Writer.Grow(4096)
+	r.renderNode(r.Tree.Root)
+	output = r.Writer.Bytes()
+	return
+}
 
-	ast.Walk(r.Tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+// renderNode 从 node 开始遍历并渲染。
+func (r *BaseRenderer) renderNode(node *ast.Node) {
+	ast.Walk(node, func(n *ast.Node, entering bool) ast.WalkStatus {
 		extRender := r.ExtRendererFuncs[n.Type]
 		if nil != extRender {
 			output, status := extRender(n, entering)
@@ -199,9 +242,6 @@ func (r *BaseRenderer) Render() (output []byte) {
 		}
 		return render(n, entering)
 	})
-
-	output = r.Writer.Bytes()
-	return
 }
 
 func (r *BaseRenderer) renderDefault(n *ast.Node, entering bool) ast.WalkStatus {
@@ -239,6 +279,14 @@ func (r *BaseRenderer) Newline() {
 	}
 }
 
+// blockEnd 输出块结束换行：列表项中的块仅换行，其他块后再输出一个空行。
+func (r *BaseRenderer) blockEnd(node *ast.Node) {
+	r.Newline()
+	if !node.ParentIs(ast.NodeListItem) {
+		r.WriteByte(lex.ItemNewline)
+	}
+}
+
 func (r *BaseRenderer) TextAutoSpacePrevious(node *ast.Node) {
 	if !r.Options.AutoSpace {
 		return
@@ -350,13 +398,25 @@ func SubStr(str string, length int) (ret string) {
 }
 
 func HeadingID(heading *ast.Node) (ret string) {
+	return HeadingSlugID(heading, nil)
+}
+
+// HeadingSlugID 使用 slugger 生成标题 ID，slugger 为 nil 时使用默认策略。
+//
+// 文档中所有标题的 ID 会在首次调用时一并生成并缓存在节点上。
+func HeadingSlugID(heading *ast.Node, slugger HeadingSlugger) (ret string) {
 	if 0 == len(util.StrToBytes(heading.HeadingNormalizedID)) {
-		headingID0(heading)
+		headingID0(heading, slugger)
 	}
 	return heading.HeadingNormalizedID
 }
 
-func headingID0(heading *ast.Node) {
+// headingID 使用 Options.HeadingSlugger 生成标题 ID。
+func (r *BaseRenderer) headingID(heading *ast.Node) string {
+	return HeadingSlugID(heading, r.Options.HeadingSlugger)
+}
+
+func headingID0(heading *ast.Node, slugger HeadingSlugger) {
 	var root *ast.Node
 	for root = heading.Parent; ast.NodeDocument != root.Type; root = root.Parent {
 	}
@@ -365,11 +425,15 @@ func headingID0(heading *ast.Node) {
 	ast.Walk(root, func(n *ast.Node, entering bool) ast.WalkStatus {
 		if entering {
 			if ast.NodeHeading == n.Type {
-				id := normalizeHeadingID(n)
-				for ; 0 < idOccurs[id]; id += "-" {
+				if nil == slugger {
+					id := normalizeHeadingID(n)
+					for ; 0 < idOccurs[id]; id += "-" {
+					}
+					n.HeadingNormalizedID = id
+					idOccurs[id] = 1
+				} else {
+					n.HeadingNormalizedID = uniqueSlug(slugHeading(n, slugger), idOccurs)
 				}
-				n.HeadingNormalizedID = id
-				idOccurs[id] = 1
 			}
 		}
 		return ast.WalkContinue
@@ -405,21 +469,28 @@ type Heading struct {
 	HPath    string     `json:"hPath"`
 	Content  string     `json:"content"`
 	Level    int        `json:"level"`
+	Number   string     `json:"number"`
 	Children []*Heading `json:"children"`
 	parent   *Heading
+	node     *ast.Node
 }
 
 func (r *BaseRenderer) renderToC(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		headings := r.headings()
 		length := len(headings)
+		if r.Options.ToCNav {
+			if 0 < length {
+				r.WriteString("<nav class=\"toc\">")
+				r.renderToCList(headings)
+				r.WriteString("</nav>")
+			}
+			return ast.WalkContinue
+		}
+
 		r.WriteString("<div class=\"vditor-toc\" data-block=\"0\" data-type=\"toc-block\" contenteditable=\"false\">")
 		if 0 < length {
-			r.WriteString("<ul>")
-			for _, child := range headings {
-				r.renderToC0(child)
-			}
-			r.WriteString("</ul>")
+			r.renderToCList(headings)
 		} else {
 			r.WriteString("[toc]<br>")
 		}
@@ -428,17 +499,36 @@ func (r *BaseRenderer) renderToC(node *ast.Node, entering bool) ast.WalkStatus {
 	return ast.WalkContinue
 }
 
+func (r *BaseRenderer) renderToCList(headings []*Heading) {
+	tag := "ul"
+	if r.Options.ToCOrdered {
+		tag = "ol"
+	}
+	r.WriteString("<" + tag + ">")
+	for _, heading := range headings {
+		r.renderToC0(heading)
+	}
+	r.WriteString("</" + tag + ">")
+}
+
 func (r *BaseRenderer) renderToC0(heading *Heading) {
 	r.WriteString("<li>")
-	r.Tag("span", [][]string{{"data-target-id", heading.ID}}, false)
+	if r.Options.ToCNav {
+		r.Tag("a", [][]string{{"href", "#" + heading.ID}}, false)
+	} else {
+		r.Tag("span", [][]string{{"data-target-id", heading.ID}}, false)
+	}
+	if r.Options.ToCNumbering {
+		r.WriteString(heading.Number + " ")
+	}
 	r.WriteString(heading.Content)
-	r.Tag("/span", nil, false)
+	if r.Options.ToCNav {
+		r.Tag("/a", nil, false)
+	} else {
+		r.Tag("/span", nil, false)
+	}
 	if 0 < len(heading.Children) {
-		r.WriteString("<ul>")
-		for _, child := range heading.Children {
-			r.renderToC0(child)
-		}
-		r.WriteString("</ul>")
+		r.renderToCList(heading.Children)
 	}
 	r.WriteString("</li>")
 }
@@ -461,15 +551,44 @@ func (r *BaseRenderer) Tag(name string, attrs [][]string, selfclosing bool) {
 	r.WriteString(">")
 }
 
+// Headings 返回文档标题构成的标题树，包含的标题受 Options.ToCMinLevel、ToCMaxLevel 和 ToCNestedHeadings 控制。
+func (r *BaseRenderer) Headings() []*Heading {
+	return r.headings()
+}
+
+// headingNumber 返回标题的章节编号，不在目录中的标题返回空字符串。
+func (r *BaseRenderer) headingNumber(heading *ast.Node) string {
+	if nil == r.headingNumbers {
+		r.headingNumbers = map[*ast.Node]string{}
+		var walk func(headings []*Heading)
+		walk = func(headings []*Heading) {
+			for _, h := range headings {
+				r.headingNumbers[h.node] = h.Number
+				walk(h.Children)
+			}
+		}
+		walk(r.headings())
+	}
+	return r.headingNumbers[heading]
+}
+
 func (r *BaseRenderer) headings() (ret []*Heading) {
+	minLevel, maxLevel := r.Options.ToCMinLevel, r.Options.ToCMaxLevel
+	if 1 > maxLevel {
+		maxLevel = 6
+	}
+
 	headings := r.Tree.Root.ChildrenByType(ast.NodeHeading)
 	var tip *Heading
 	for _, heading := range headings {
-		if r.Tree.Root != heading.Parent {
+		if r.Tree.Root != heading.Parent && !r.Options.ToCNestedHeadings {
+			continue
+		}
+		if minLevel > heading.HeadingLevel || maxLevel < heading.HeadingLevel {
 			continue
 		}
 
-		id := HeadingID(heading)
+		id := r.headingID(heading)
 		if r.Options.VditorWYSIWYG {
 			id = "wysiwyg-" + id
 		} else if r.Options.VditorIR {
@@ -492,6 +611,7 @@ func (r *BaseRenderer) headings() (ret []*Heading) {
 			HPath:   r.Tree.HPath,
 			Content: headingText(heading),
 			Level:   heading.HeadingLevel,
+			node:    heading,
 		}
 
 		if nil == tip {
@@ -511,9 +631,18 @@ func (r *BaseRenderer) headings() (ret []*Heading) {
 		}
 		tip = h
 	}
+	numberHeadings(ret, "")
 	return
 }
 
+// numberHeadings 为标题树生成 1.2.3 形式的章节编号。
+func numberHeadings(headings []*Heading, prefix string) {
+	for i, h := range headings {
+		h.Number = prefix + strconv.Itoa(i+1)
+		numberHeadings(h.Children, h.Number+".")
+	}
+}
+
 func parentTip(currentHeading, tip *Heading) *Heading {
 	if nil == tip.parent {
 		return nil
@@ -537,6 +666,8 @@ func headingText(n *ast.Node) (ret string) {
 		switch n.Type {
 		case ast.NodeLinkText, ast.NodeBlockRefText, ast.NodeBlockRefDynamicText, ast.NodeFileAnnotationRefText:
 			buf.Write(n.Tokens)
+		case ast.NodeHTMLEntity:
+			buf.Write(html.EscapeHTML(n.Tokens))
 		case ast.NodeInlineMathContent:
 			buf.WriteString("<span class=\"language-math\">")
 			buf.Write(html.EscapeHTML(n.Tokens))
@@ -631,7 +762,7 @@ func (r *BaseRenderer) tagSrcPath(tokens []byte) []byte {
 		if 1 > len(bytes.ReplaceAll(src, editor.CaretTokens, nil)) {
 			return tokens
 		}
-		targetSrc := r.LinkPath(src)
+		targetSrc := r.resolveLinkPath(src, LinkKindImage)
 		originSrc := string(targetSrc)
 		if bytes.HasPrefix(targetSrc, []byte("//")) {
 			originSrc = "https:" + originSrc
@@ -0,0 +1,361 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/lex"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+// SlackRenderer 描述了 Slack mrkdwn 渲染器。
+type SlackRenderer struct {
+	*BaseRenderer
+	NodeWriterStack []*bytes.Buffer // 节点输出缓冲栈
+}
+
+// NewSlackRenderer 创建一个 Slack mrkdwn 渲染器。
+func NewSlackRenderer(tree *parse.Tree, options *Options) Renderer {
+	ret := &SlackRenderer{BaseRenderer: NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderText
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeSpan] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeInlineMath] = ret.renderInlineMath
+	ret.RendererFuncs[ast.NodeCodeBlock] = ret.renderCodeBlock
+	ret.RendererFuncs[ast.NodeMathBlock] = ret.renderMathBlock
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeEmphasis] = ret.renderEmphasis
+	ret.RendererFuncs[ast.NodeStrong] = ret.renderStrong
+	ret.RendererFuncs[ast.NodeStrikethrough] = ret.renderStrikethrough
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeTaskListItemMarker] = ret.renderTaskListItemMarker
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderBreak
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderEmojiUnicode
+	ret.RendererFuncs[ast.NodeEmojiImg] = ret.renderEmojiImg
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeFootnotesDefBlock] = ret.renderFootnotesDefBlock
+	ret.RendererFuncs[ast.NodeFootnotesDef] = ret.renderFootnotesDef
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+func (r *SlackRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+	} else {
+		buf := bytes.TrimSpace(r.Writer.Bytes())
+		r.Writer.Reset()
+		r.Write(buf)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) pushWriter() {
+	r.Writer = &bytes.Buffer{}
+	r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+}
+
+func (r *SlackRenderer) popWriter() (ret []byte) {
+	writer := r.NodeWriterStack[len(r.NodeWriterStack)-1]
+	r.NodeWriterStack = r.NodeWriterStack[:len(r.NodeWriterStack)-1]
+	r.Writer = r.NodeWriterStack[len(r.NodeWriterStack)-1]
+	return bytes.TrimRight(writer.Bytes(), " \t\n")
+}
+
+// codeBlock 输出 Slack 代码块，Slack 不支持指定代码语言。
+func (r *SlackRenderer) codeBlock(node *ast.Node, code []byte) {
+	r.Newline()
+	r.WriteString("```\n")
+	r.Write(slackEscape(bytes.Trim(code, "\n")))
+	r.WriteString("\n```")
+	r.blockEnd(node)
+}
+
+func (r *SlackRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(slackEscape(node.Tokens))
+	}
+	return ast.WalkContinue
+}
+
+// slackEscape 转义 Slack 控制字符 &、< 和 >。
+func slackEscape(tokens []byte) []byte {
+	if !bytes.ContainsAny(tokens, "&<>") {
+		return tokens
+	}
+	ret := make([]byte, 0, len(tokens)+8)
+	for _, c := range tokens {
+		switch c {
+		case '&':
+			ret = append(ret, "&amp;"...)
+		case '<':
+			ret = append(ret, "&lt;"...)
+		case '>':
+			ret = append(ret, "&gt;"...)
+		default:
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+func (r *SlackRenderer) renderCodeSpan(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var code []byte
+		if content := node.ChildByType(ast.NodeCodeSpanContent); nil != content {
+			code = slackEscape(content.Tokens)
+		}
+		if 1 > maxBacktickRun(code) {
+			r.WriteByte('`')
+			r.Write(code)
+			r.WriteByte('`')
+			return ast.WalkSkipChildren
+		}
+
+		// Slack 的行内代码不能包含反引号，这时改用代码块，内容首尾的反引号使用空格与围栏隔开
+		r.WriteString("```")
+		if '`' == code[0] {
+			r.WriteByte(' ')
+		}
+		r.Write(code)
+		if '`' == code[len(code)-1] {
+			r.WriteByte(' ')
+		}
+		r.WriteString("```")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteByte('`')
+		if content := node.ChildByType(ast.NodeInlineMathContent); nil != content {
+			r.Write(slackEscape(content.Tokens))
+		}
+		r.WriteByte('`')
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		_, code := codeBlockLangCode(node)
+		r.codeBlock(node, code)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var code []byte
+		if content := node.ChildByType(ast.NodeMathBlockContent); nil != content {
+			code = content.Tokens
+		}
+		r.codeBlock(node, code)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderHTMLBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.codeBlock(node, node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('_')
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('*')
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderStrikethrough(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('~')
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.pushWriter()
+	} else {
+		content := r.popWriter()
+		r.Newline()
+		r.Write(chatQuote(content, "> "))
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("*" + util.BytesToStr(slackEscape([]byte(plainText(node)))) + "*")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering && !node.ParentIs(ast.NodeListItem) {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString(chatListItemMarker(node, "    ", "•"))
+	}
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderTaskListItemMarker(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString(chatCheckbox(node))
+		if next := node.Next; nil != next && ast.NodeText == next.Type {
+			next.Tokens = bytes.TrimLeft(next.Tokens, " ")
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("──────────")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	dest := util.BytesToStr(r.resolveLinkPath(linkDest(r.Tree, node), LinkKindLink))
+	if 2 == node.LinkType {
+		if entering {
+			r.WriteString("<" + dest + ">")
+		}
+		return ast.WalkSkipChildren
+	}
+	if entering {
+		r.WriteString("<" + dest + "|")
+	} else {
+		r.WriteByte('>')
+	}
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest := util.BytesToStr(r.resolveLinkPath(linkDest(r.Tree, node), LinkKindImage))
+		if alt := plainText(node); "" != alt {
+			r.WriteString("<" + dest + "|" + util.BytesToStr(slackEscape([]byte(alt))) + ">")
+		} else {
+			r.WriteString("<" + dest + ">")
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.codeBlock(node, []byte(monospaceTable(node)))
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderEmojiUnicode(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if alias := node.ChildByType(ast.NodeEmojiAlias); nil != alias {
+			r.Write(alias.Tokens)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("[" + node.FootnotesRefId + "]")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *SlackRenderer) renderFootnotesDefBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("──────────")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *SlackRenderer) renderFootnotesDef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("[" + strconv.Itoa(chatFootnotesDefNum(node)) + "] ")
+	}
+	return ast.WalkContinue
+}
@@ -0,0 +1,253 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/lex"
+	"github.com/88250/lute/parse"
+)
+
+// SlidesRenderer 描述了幻灯片渲染器，输出 reveal.js 兼容的 HTML 结构。
+//
+// 文档在分隔线和 Options.SlidesHeadingLevel 指定级别的标题处拆分为幻灯片，幻灯片内容使用 HtmlRenderer 渲染。
+// 设置 Options.SlidesRevealJS 后输出可以直接打开的完整 HTML 文档。
+type SlidesRenderer struct {
+	*HtmlRenderer
+}
+
+// slide 描述了一张幻灯片。
+type slide struct {
+	attrs  [][]string  // section 元素属性
+	notes  string      // 来自块级 IAL 的演讲者备注
+	blocks []*ast.Node // 幻灯片内容块
+}
+
+// NewSlidesRenderer 创建一个幻灯片渲染器。
+func NewSlidesRenderer(tree *parse.Tree, options *Options) Renderer {
+	return &SlidesRenderer{HtmlRenderer: NewHtmlRenderer(tree, options)}
+}
+
+// Render 渲染幻灯片。
+func (r *SlidesRenderer) Render() (output []byte) {
+	r.LastOut = lex.ItemNewline
+	r.Writer = &bytes.Buffer{}
+	r.Writer.Grow(4096)
+
+	stacks, hidden := r.slides()
+	revealJS := strings.TrimSuffix(r.Options.SlidesRevealJS, "/")
+	if "" != revealJS {
+		r.renderDocumentHead(revealJS)
+	}
+	r.WriteString("<div class=\"reveal\">\n<div class=\"slides\">\n")
+	for _, stack := range stacks {
+		// 同一组中的多张幻灯片嵌套在一个 section 中纵向排列
+		vertical := 1 < len(stack)
+		if vertical {
+			r.WriteString("<section>\n")
+		}
+		for _, s := range stack {
+			r.renderSlide(s)
+		}
+		if vertical {
+			r.WriteString("</section>\n")
+		}
+	}
+	for _, block := range hidden {
+		r.renderNode(block)
+	}
+	if footnotes := r.RenderFootnotes(); 0 < len(footnotes) {
+		r.WriteString("<section class=\"footnotes\">\n")
+		r.Write(footnotes)
+		r.Newline()
+		r.WriteString("</section>\n")
+	}
+	r.WriteString("</div>\n</div>\n")
+	if "" != revealJS {
+		r.WriteString("<script src=\"" + html.EscapeHTMLStr(revealJS) + "/dist/reveal.js\"></script>\n")
+		r.WriteString("<script src=\"" + html.EscapeHTMLStr(revealJS) + "/plugin/notes/notes.js\"></script>\n")
+		r.WriteString("<script>Reveal.initialize({hash: true, plugins: [RevealNotes]});</script>\n")
+		r.WriteString("</body>\n</html>\n")
+	}
+	output = r.Writer.Bytes()
+	return
+}
+
+// renderDocumentHead 渲染完整 HTML 文档的开头部分，引用 reveal.js 资源地址 revealJS 下的样式，标题取自文档中的第一个标题。
+func (r *SlidesRenderer) renderDocumentHead(revealJS string) {
+	title := &bytes.Buffer{}
+	if heading := r.Tree.Root.ChildByType(ast.NodeHeading); nil != heading {
+		ast.Walk(heading, func(n *ast.Node, entering bool) ast.WalkStatus {
+			if !entering {
+				return ast.WalkContinue
+			}
+			switch n.Type {
+			case ast.NodeText, ast.NodeLinkText, ast.NodeHTMLEntity, ast.NodeBackslashContent, ast.NodeCodeSpanContent, ast.NodeInlineMathContent:
+				title.Write(n.Tokens)
+			case ast.NodeKramdownSpanIAL:
+				return ast.WalkSkipChildren
+			}
+			return ast.WalkContinue
+		})
+	}
+	revealJS = html.EscapeHTMLStr(revealJS)
+	r.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	r.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\">\n")
+	r.WriteString("<title>" + html.EscapeHTMLStr(strings.TrimSpace(title.String())) + "</title>\n")
+	r.WriteString("<link rel=\"stylesheet\" href=\"" + revealJS + "/dist/reveal.css\">\n")
+	r.WriteString("<link rel=\"stylesheet\" href=\"" + revealJS + "/dist/theme/white.css\">\n")
+	r.WriteString("</head>\n<body>\n")
+}
+
+// slides 将文档顶层块拆分为幻灯片组，hidden 返回不属于任何幻灯片的块，比如脚注定义和链接引用定义。
+func (r *SlidesRenderer) slides() (stacks [][]*slide, hidden []*ast.Node) {
+	level := r.Options.SlidesHeadingLevel
+	var stack []*slide
+	var current *slide
+	var section bool // 当前幻灯片组是否由高于拆分级别的标题开始
+	pushStack := func() {
+		var visible []*slide
+		for _, s := range stack {
+			if 0 < len(s.blocks) || "" != s.notes {
+				visible = append(visible, s)
+			}
+		}
+		if 0 < len(visible) {
+			stacks = append(stacks, visible)
+		}
+		stack = nil
+	}
+	open := func(horizontal bool, opener *ast.Node) {
+		if nil != current && 1 > len(current.blocks) && nil != opener && ast.NodeHeading == opener.Type {
+			// 分隔线后紧跟的标题不再开始新的幻灯片
+			attrs, notes := slideAttrs(opener)
+			current.attrs = append(current.attrs, attrs...)
+			if "" != notes {
+				current.notes = notes
+			}
+			return
+		}
+		if horizontal {
+			pushStack()
+		}
+		current = &slide{}
+		if nil != opener {
+			current.attrs, current.notes = slideAttrs(opener)
+		}
+		stack = append(stack, current)
+	}
+
+	for c := r.Tree.Root.FirstChild; nil != c; c = c.Next {
+		switch c.Type {
+		case ast.NodeYamlFrontMatter:
+			continue
+		case ast.NodeFootnotesDefBlock, ast.NodeLinkRefDefBlock:
+			hidden = append(hidden, c)
+			continue
+		case ast.NodeKramdownBlockIAL:
+			if nil != c.Previous && ast.NodeThematicBreak == c.Previous.Type {
+				continue
+			}
+		case ast.NodeThematicBreak:
+			open(true, c)
+			section = false
+			continue
+		case ast.NodeHeading:
+			if 0 < level && c.HeadingLevel < level {
+				open(true, c)
+				section = true
+			} else if level == c.HeadingLevel {
+				open(!section, c)
+			}
+		}
+		if nil == current {
+			open(true, nil)
+		}
+		current.blocks = append(current.blocks, c)
+	}
+	pushStack()
+	return
+}
+
+// slideAttrs 从块级 IAL 中获取幻灯片属性和演讲者备注。
+func slideAttrs(node *ast.Node) (attrs [][]string, notes string) {
+	for _, kv := range node.KramdownIAL {
+		switch {
+		case "background" == kv[0] || "transition" == kv[0]:
+			attrs = append(attrs, []string{"data-" + kv[0], kv[1]})
+		case strings.HasPrefix(kv[0], "data-"):
+			attrs = append(attrs, kv)
+		case "notes" == kv[0]:
+			notes = kv[1]
+		}
+	}
+	return
+}
+
+func (r *SlidesRenderer) renderSlide(s *slide) {
+	r.Tag("section", s.attrs, false)
+	r.Newline()
+	inNotes := false
+	for _, block := range s.blocks {
+		if !inNotes && isSlideNoteParagraph(block) {
+			// Note: 段落及其后的内容都是演讲者备注
+			inNotes = true
+			r.WriteString("<aside class=\"notes\">\n")
+			r.renderSlideNoteParagraph(block)
+			continue
+		}
+		r.renderNode(block)
+	}
+	if "" != s.notes {
+		if !inNotes {
+			inNotes = true
+			r.WriteString("<aside class=\"notes\">\n")
+		}
+		// IAL 中的值可能已经转义过，先反转义再转义，避免重复转义
+		r.WriteString("<p>" + html.EscapeHTMLStr(html.UnescapeHTMLStr(s.notes)) + "</p>\n")
+	}
+	if inNotes {
+		r.WriteString("</aside>\n")
+	}
+	r.WriteString("</section>\n")
+}
+
+// isSlideNoteParagraph 判断块是否为以 Note: 开头的演讲者备注段落。
+func isSlideNoteParagraph(node *ast.Node) bool {
+	return ast.NodeParagraph == node.Type && nil != node.FirstChild && ast.NodeText == node.FirstChild.Type &&
+		bytes.HasPrefix(node.FirstChild.Tokens, []byte("Note:"))
+}
+
+// renderSlideNoteParagraph 渲染去掉 Note: 前缀后的备注段落。
+func (r *SlidesRenderer) renderSlideNoteParagraph(node *ast.Node) {
+	text := node.FirstChild
+	tokens := bytes.TrimLeft(text.Tokens[len("Note:"):], " ")
+	next := text.Next
+	if 1 > len(tokens) {
+		for nil != next && (ast.NodeSoftBreak == next.Type || ast.NodeHardBreak == next.Type) {
+			next = next.Next
+		}
+		if nil == next {
+			return
+		}
+	}
+
+	r.WriteString("<p>")
+	r.Write(html.EscapeHTML(tokens))
+	for ; nil != next; next = next.Next {
+		r.renderNode(next)
+	}
+	r.WriteString("</p>\n")
+}
@@ -0,0 +1,352 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package render
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/lex"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+// TelegramRenderer 描述了 Telegram MarkdownV2 渲染器。
+type TelegramRenderer struct {
+	*BaseRenderer
+	NodeWriterStack []*bytes.Buffer // 节点输出缓冲栈
+}
+
+// NewTelegramRenderer 创建一个 Telegram MarkdownV2 渲染器。
+func NewTelegramRenderer(tree *parse.Tree, options *Options) Renderer {
+	ret := &TelegramRenderer{BaseRenderer: NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderText
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeSpan] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeInlineMath] = ret.renderInlineMath
+	ret.RendererFuncs[ast.NodeCodeBlock] = ret.renderCodeBlock
+	ret.RendererFuncs[ast.NodeMathBlock] = ret.renderMathBlock
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderHTMLBlock
+	ret.RendererFuncs[ast.NodeEmphasis] = ret.renderEmphasis
+	ret.RendererFuncs[ast.NodeStrong] = ret.renderStrong
+	ret.RendererFuncs[ast.NodeStrikethrough] = ret.renderStrikethrough
+	ret.RendererFuncs[ast.NodeUnderline] = ret.renderUnderline
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeTaskListItemMarker] = ret.renderTaskListItemMarker
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderBreak
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderEmojiUnicode
+	ret.RendererFuncs[ast.NodeEmojiImg] = ret.renderEmojiImg
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeFootnotesDefBlock] = ret.renderFootnotesDefBlock
+	ret.RendererFuncs[ast.NodeFootnotesDef] = ret.renderFootnotesDef
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+func (r *TelegramRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+	} else {
+		buf := bytes.TrimSpace(r.Writer.Bytes())
+		r.Writer.Reset()
+		r.Write(buf)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) pushWriter() {
+	r.Writer = &bytes.Buffer{}
+	r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+}
+
+func (r *TelegramRenderer) popWriter() (ret []byte) {
+	writer := r.NodeWriterStack[len(r.NodeWriterStack)-1]
+	r.NodeWriterStack = r.NodeWriterStack[:len(r.NodeWriterStack)-1]
+	r.Writer = r.NodeWriterStack[len(r.NodeWriterStack)-1]
+	return bytes.TrimRight(writer.Bytes(), " \t\n")
+}
+
+// codeBlock 输出 Telegram 预格式化块，lang 为空时不指定代码语言。
+func (r *TelegramRenderer) codeBlock(node *ast.Node, lang string, code []byte) {
+	r.Newline()
+	r.WriteString("```" + lang + "\n")
+	r.Write(telegramEscape(bytes.Trim(code, "\n"), telegramCodeChars))
+	r.WriteString("\n```")
+	r.blockEnd(node)
+}
+
+func (r *TelegramRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(telegramEscape(node.Tokens, telegramTextChars))
+	}
+	return ast.WalkContinue
+}
+
+// Telegram MarkdownV2 在不同上下文中需要转义的字符。
+const (
+	telegramTextChars = "_*[]()~`>#+-=|{}.!\\"
+	telegramCodeChars = "`\\"
+	telegramLinkChars = ")\\"
+)
+
+// telegramEscape 使用反斜杠转义 tokens 中属于 chars 的字符。
+func telegramEscape(tokens []byte, chars string) []byte {
+	if !bytes.ContainsAny(tokens, chars) {
+		return tokens
+	}
+	ret := make([]byte, 0, len(tokens)+8)
+	for _, c := range tokens {
+		if 0 <= bytes.IndexByte([]byte(chars), c) {
+			ret = append(ret, '\\')
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+func (r *TelegramRenderer) renderCodeSpan(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteByte('`')
+		if content := node.ChildByType(ast.NodeCodeSpanContent); nil != content {
+			r.Write(telegramEscape(content.Tokens, telegramCodeChars))
+		}
+		r.WriteByte('`')
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteByte('`')
+		if content := node.ChildByType(ast.NodeInlineMathContent); nil != content {
+			r.Write(telegramEscape(content.Tokens, telegramCodeChars))
+		}
+		r.WriteByte('`')
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		lang, code := codeBlockLangCode(node)
+		r.codeBlock(node, lang, code)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var code []byte
+		if content := node.ChildByType(ast.NodeMathBlockContent); nil != content {
+			code = content.Tokens
+		}
+		r.codeBlock(node, "latex", code)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderHTMLBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.codeBlock(node, "html", node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('_')
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('*')
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderStrikethrough(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteByte('~')
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderUnderline(node *ast.Node, entering bool) ast.WalkStatus {
+	r.WriteString("__")
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.pushWriter()
+	} else {
+		content := r.popWriter()
+		r.Newline()
+		r.Write(chatQuote(content, ">"))
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("*" + util.BytesToStr(telegramEscape([]byte(plainText(node)), telegramTextChars)) + "*")
+		r.blockEnd(node)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering && !node.ParentIs(ast.NodeListItem) {
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.Write(telegramEscape([]byte(chatListItemMarker(node, "    ", "•")), telegramTextChars))
+	}
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderTaskListItemMarker(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString(chatCheckbox(node))
+		if next := node.Next; nil != next && ast.NodeText == next.Type {
+			next.Tokens = bytes.TrimLeft(next.Tokens, " ")
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("──────────")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	dest := r.resolveLinkPath(linkDest(r.Tree, node), LinkKindLink)
+	if 2 == node.LinkType {
+		if entering {
+			r.Write(telegramEscape(dest, telegramTextChars))
+		}
+		return ast.WalkSkipChildren
+	}
+	if entering {
+		r.WriteByte('[')
+	} else {
+		r.WriteString("](" + util.BytesToStr(telegramEscape(dest, telegramLinkChars)) + ")")
+	}
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest := r.resolveLinkPath(linkDest(r.Tree, node), LinkKindImage)
+		if alt := plainText(node); "" != alt {
+			r.WriteString("[" + util.BytesToStr(telegramEscape([]byte(alt), telegramTextChars)) + "](" + util.BytesToStr(telegramEscape(dest, telegramLinkChars)) + ")")
+		} else {
+			r.Write(telegramEscape(dest, telegramTextChars))
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.codeBlock(node, "", []byte(monospaceTable(node)))
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderEmojiUnicode(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Write(node.Tokens)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if alias := node.ChildByType(ast.NodeEmojiAlias); nil != alias {
+			r.Write(telegramEscape(alias.Tokens, telegramTextChars))
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("\\[" + node.FootnotesRefId + "\\]")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TelegramRenderer) renderFootnotesDefBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("──────────")
+		r.blockEnd(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TelegramRenderer) renderFootnotesDef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("\\[" + strconv.Itoa(chatFootnotesDefNum(node)) + "\\] ")
+	}
+	return ast.WalkContinue
+}
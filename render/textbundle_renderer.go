@@ -56,9 +56,14 @@ func (r *TextBundleRenderer) renderLinkDest(node *ast.Node, entering bool) ast.W
 		for _, linkPrefix := range r.linkPrefixes {
 			if "" != linkPrefix && strings.HasPrefix(dest, linkPrefix) {
 				r.originalLink = append(r.originalLink, dest)
-				dest = "assets" + dest[len(linkPrefix):]
+				r.WriteString("assets" + dest[len(linkPrefix):])
+				return ast.WalkContinue
 			}
 		}
+		if nil != r.Options.LinkResolver {
+			// 打包到 assets 中的资源保持相对路径，其余地址交由 LinkResolver 改写
+			dest = util.BytesToStr(r.resolveLinkPath(node.Tokens, linkDestKind(node)))
+		}
 		r.WriteString(dest)
 	}
 	return ast.WalkContinue
@@ -651,7 +651,7 @@ func (r *VditorRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStat
 			r.WriteString("<img src=\"")
 			link := r.Tree.FindLinkRefDefLink(node.LinkRefLabel)
 			destTokens := link.ChildByType(ast.NodeLinkDest).Tokens
-			destTokens = r.LinkPath(destTokens)
+			destTokens = r.resolveLinkPath(destTokens, LinkKindImage)
 			destTokens = bytes.ReplaceAll(destTokens, editor.CaretTokens, nil)
 			r.Write(destTokens)
 			r.WriteString("\" alt=\"")
@@ -684,7 +684,7 @@ func (r *VditorRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStat
 		if 0 == r.DisableTags {
 			r.WriteString("<img src=\"")
 			destTokens := node.ChildByType(ast.NodeLinkDest).Tokens
-			destTokens = r.LinkPath(destTokens)
+			destTokens = r.resolveLinkPath(destTokens, LinkKindImage)
 			destTokens = bytes.ReplaceAll(destTokens, editor.CaretTokens, nil)
 			r.Write(destTokens)
 			r.WriteString("\" alt=\"")
@@ -756,7 +756,7 @@ func (r *VditorRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatu
 				destTokens = nil
 			}
 		}
-		destTokens = r.LinkPath(destTokens)
+		destTokens = r.resolveLinkPath(destTokens, LinkKindLink)
 		caretInDest := bytes.Contains(destTokens, editor.CaretTokens)
 		if caretInDest {
 			text := node.ChildByType(ast.NodeLinkText)
@@ -1008,7 +1008,7 @@ func (r *VditorRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkSt
 			r.WriteString(" data-id=\"" + id + "\"")
 		}
 		if "" == id {
-			id = HeadingID(node)
+			id = r.headingID(node)
 		}
 		r.WriteString(" id=\"wysiwyg-" + id + "\"")
 		if !node.HeadingSetext {
@@ -1021,7 +1021,7 @@ func (r *VditorRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkSt
 			}
 		}
 		if r.Options.HeadingAnchor {
-			id := HeadingID(node)
+			id := r.headingID(node)
 			r.Tag("a", [][]string{{"id", "vditorAnchor-" + id}, {"class", "vditor-anchor"}, {"href", "#" + id}}, false)
 			r.WriteString(`<svg viewBox="0 0 16 16" version="1.1" width="16" height="16"><path fill-rule="evenodd" d="M4 9h1v1H4c-1.5 0-3-1.69-3-3.5S2.55 3 4 3h4c1.45 0 3 1.69 3 3.5 0 1.41-.91 2.72-2 3.25V8.59c.58-.45 1-1.27 1-2.09C10 5.22 8.98 4 8 4H4c-.98 0-2 1.22-2 2.5S3 9 4 9zm9-3h-1v1h1c1 0 2 1.22 2 2.5S13.98 12 13 12H9c-.98 0-2-1.22-2-2.5 0-.83.42-1.64 1-2.09V6.25c-1.09.53-2 1.84-2 3.25C6 11.31 7.55 13 9 13h4c1.45 0 3-1.69 3-3.5S14.5 6 13 6z"></path></svg>`)
 			r.Tag("/a", nil, false)
@@ -0,0 +1,315 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/render"
+	"github.com/88250/lute/util"
+)
+
+// StandaloneHTMLOptions 描述了独立 HTML 文档的导出选项。
+type StandaloneHTMLOptions struct {
+	Title         string // 文档标题，为空时依次使用 Front Matter 中的 title、第一个一级标题和文档名称
+	Lang          string // 语言，为空时使用 Front Matter 中的 lang，仍为空则使用 zh-CN
+	Theme         string // 主题：light（默认）、dark 或者 auto（跟随系统深浅色）
+	CodeStyle     string // 浅色主题的代码高亮样式，为空时使用 github，可选值见 chroma-styles 目录
+	DarkCodeStyle string // 深色主题的代码高亮样式，为空时使用 monokai
+	ToC           bool   // 是否嵌入目录侧边栏
+	BaseDir       string // 本地图片相对路径的基础目录
+	CSS           string // 追加到主题样式之后的自定义样式
+}
+
+// Markdown2StandaloneHTML 将 Markdown 转换为单文件的 HTML5 文档。
+//
+// 标题等元数据来自 YAML Front Matter，样式来自内置主题，本地图片以 data URI 形式内嵌，生成的文档不依赖任何外部资源。
+func (lute *Lute) Markdown2StandaloneHTML(name, markdown string, opts *StandaloneHTMLOptions) (ret string, err error) {
+	if nil == opts {
+		opts = &StandaloneHTMLOptions{}
+	}
+	css, err := standaloneCSS(opts)
+	if nil != err {
+		return
+	}
+
+	parseOptions := *lute.ParseOptions
+	parseOptions.YamlFrontMatter = true
+	parseOptions.DataImage = true // 内嵌的图片以 data:image 形式引用
+	tree := parse.Parse(name, []byte(markdown), &parseOptions)
+	meta := map[string][]string{}
+	if frontMatter := tree.Root.ChildByType(ast.NodeYamlFrontMatter); nil != frontMatter {
+		if content := frontMatter.ChildByType(ast.NodeYamlFrontMatterContent); nil != content {
+			meta = standaloneFrontMatter(util.BytesToStr(content.Tokens))
+		}
+		frontMatter.Unlink()
+	}
+	standaloneEmbedImages(tree, opts.BaseDir)
+
+	options := *lute.RenderOptions
+	options.HeadingID = options.HeadingID || opts.ToC // 目录需要链接到标题
+	options.ImageLazyLoading = ""
+	options.CodeSyntaxHighlightInlineStyle = false // 代码高亮使用主题中的样式
+	options.MathML = true                          // 文档不引入脚本，公式需要预先转换
+	renderer := render.NewHtmlRenderer(tree, &options)
+	for nodeType, rendererFunc := range lute.Md2HTMLRendererFuncs {
+		renderer.ExtRendererFuncs[nodeType] = rendererFunc
+	}
+	body := renderer.Render()
+
+	title := opts.Title
+	if "" == title {
+		title = standaloneMetaValue(meta, "title")
+	}
+	if h1 := tree.Root.ChildByType(ast.NodeHeading); "" == title && nil != h1 && 1 == h1.HeadingLevel {
+		title = h1.Text()
+	}
+	if "" == title {
+		title = name
+	}
+	lang := opts.Lang
+	if "" == lang {
+		if lang = standaloneMetaValue(meta, "lang"); "" == lang {
+			lang = "zh-CN"
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("<!DOCTYPE html>\n<html lang=\"" + html.EscapeHTMLStr(lang) + "\">\n<head>\n")
+	buf.WriteString("<meta charset=\"UTF-8\">\n<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	buf.WriteString("<title>" + html.EscapeHTMLStr(title) + "</title>\n")
+	for _, m := range [][]string{{"author", "author", "authors"}, {"description", "description", "summary"}, {"keywords", "keywords", "tags"}, {"dcterms.date", "date"}} {
+		for _, key := range m[1:] {
+			if values := meta[key]; 0 < len(values) {
+				buf.WriteString("<meta name=\"" + m[0] + "\" content=\"" + html.EscapeHTMLStr(strings.Join(values, ", ")) + "\">\n")
+				break
+			}
+		}
+	}
+	buf.WriteString("<style>\n" + css + "</style>\n</head>\n")
+	if headings := renderer.Headings(); opts.ToC && 0 < len(headings) {
+		buf.WriteString("<body class=\"with-toc\">\n<nav class=\"toc\">")
+		standaloneToC(buf, headings)
+		buf.WriteString("</nav>\n")
+	} else {
+		buf.WriteString("<body>\n")
+	}
+	buf.WriteString("<main class=\"markdown-body\">\n")
+	buf.Write(bytes.TrimSpace(body))
+	buf.WriteString("\n</main>\n</body>\n</html>\n")
+	ret = buf.String()
+	return
+}
+
+// standaloneCSS 根据主题和代码高亮样式生成文档样式。
+func standaloneCSS(opts *StandaloneHTMLOptions) (ret string, err error) {
+	codeStyle, darkCodeStyle := opts.CodeStyle, opts.DarkCodeStyle
+	if "" == codeStyle {
+		codeStyle = "github"
+	}
+	if "" == darkCodeStyle {
+		darkCodeStyle = "monokai"
+	}
+	style := func(name string) (css string) {
+		css, styleErr := chromaStyle(name)
+		if nil != styleErr && nil == err {
+			err = styleErr
+		}
+		return
+	}
+
+	buf := &strings.Builder{}
+	switch opts.Theme {
+	case "", "light":
+		buf.WriteString(standaloneLightVars)
+		buf.WriteString(style(codeStyle))
+	case "dark":
+		buf.WriteString(standaloneDarkVars)
+		buf.WriteString(style(darkCodeStyle))
+	case "auto":
+		buf.WriteString(standaloneLightVars)
+		buf.WriteString(style(codeStyle))
+		buf.WriteString("@media (prefers-color-scheme: dark) {\n")
+		buf.WriteString(standaloneDarkVars)
+		buf.WriteString(style(darkCodeStyle))
+		buf.WriteString("}\n")
+	default:
+		return "", errors.New("not found theme [" + opts.Theme + "]")
+	}
+	if nil != err {
+		return
+	}
+	buf.WriteString(standaloneBaseCSS)
+	if "" != opts.CSS {
+		buf.WriteString(opts.CSS + "\n")
+	}
+	ret = buf.String()
+	return
+}
+
+// standaloneEmbedImages 将 tree 中引用的 baseDir 下的本地图片改写为 data URI，其他图片保留原地址。
+func standaloneEmbedImages(tree *parse.Tree, baseDir string) {
+	dataURIs := map[string][]byte{}
+	ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeImage != n.Type {
+			return ast.WalkContinue
+		}
+		dest := n.ChildByType(ast.NodeLinkDest)
+		if nil == dest || !epubLocalPath(util.BytesToStr(dest.Tokens)) {
+			return ast.WalkContinue
+		}
+
+		if !localImageInBaseDir(util.BytesToStr(dest.Tokens)) {
+			return ast.WalkContinue
+		}
+		p := localImagePath(baseDir, util.BytesToStr(dest.Tokens))
+		dataURI, ok := dataURIs[p]
+		if !ok {
+			mediaType, data, readErr := readLocalImage(p)
+			if nil != readErr {
+				// 读取失败的图片保留原地址
+				return ast.WalkContinue
+			}
+			dataURI = []byte("data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data))
+			dataURIs[p] = dataURI
+		}
+		dest.Tokens = dataURI
+		return ast.WalkContinue
+	})
+}
+
+// standaloneFrontMatter 解析 YAML Front Matter 中的顶层标量和列表字段，键名转为小写，不支持嵌套结构。
+func standaloneFrontMatter(yaml string) (ret map[string][]string) {
+	ret = map[string][]string{}
+	var key string
+	for _, line := range strings.Split(yaml, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if "" == trimmed || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") {
+			if "" != key {
+				ret[key] = append(ret[key], yamlScalar(trimmed[2:]))
+			}
+			continue
+		}
+		if ' ' == line[0] || '\t' == line[0] {
+			continue
+		}
+
+		k, v, found := strings.Cut(line, ":")
+		if !found {
+			key = ""
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(k))
+		if v = strings.TrimSpace(v); strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
+			for _, item := range strings.Split(v[1:len(v)-1], ",") {
+				if item = yamlScalar(item); "" != item {
+					ret[key] = append(ret[key], item)
+				}
+			}
+		} else if v = yamlScalar(v); "" != v {
+			ret[key] = []string{v}
+		}
+	}
+	return
+}
+
+// yamlScalar 去掉 YAML 标量两端的引号和行尾注释。
+func yamlScalar(str string) string {
+	str = strings.TrimSpace(str)
+	if 2 <= len(str) && ('"' == str[0] || '\'' == str[0]) && str[0] == str[len(str)-1] {
+		return str[1 : len(str)-1]
+	}
+	if idx := strings.Index(str, " #"); 0 <= idx {
+		str = strings.TrimSpace(str[:idx])
+	}
+	return str
+}
+
+// standaloneMetaValue 返回元数据 key 的第一个值。
+func standaloneMetaValue(meta map[string][]string, key string) string {
+	if values := meta[key]; 0 < len(values) {
+		return values[0]
+	}
+	return ""
+}
+
+// standaloneToC 生成目录侧边栏中的列表。
+func standaloneToC(buf *bytes.Buffer, headings []*render.Heading) {
+	buf.WriteString("<ul>")
+	for _, heading := range headings {
+		buf.WriteString("<li><a href=\"#" + html.EscapeHTMLStr(heading.ID) + "\">" + heading.Content + "</a>")
+		if 0 < len(heading.Children) {
+			standaloneToC(buf, heading.Children)
+		}
+		buf.WriteString("</li>")
+	}
+	buf.WriteString("</ul>")
+}
+
+const standaloneLightVars = `:root {
+  color-scheme: light;
+  --text: #24292f;
+  --muted: #57606a;
+  --background: #ffffff;
+  --surface: #f6f8fa;
+  --border: #d0d7de;
+  --link: #0969da;
+}
+`
+
+const standaloneDarkVars = `:root {
+  color-scheme: dark;
+  --text: #c9d1d9;
+  --muted: #8b949e;
+  --background: #0d1117;
+  --surface: #161b22;
+  --border: #30363d;
+  --link: #58a6ff;
+}
+`
+
+const standaloneBaseCSS = `body {
+  margin: 0;
+  color: var(--text);
+  background: var(--background);
+  font: 16px/1.6 -apple-system, BlinkMacSystemFont, "Segoe UI", "Noto Sans", Helvetica, Arial, sans-serif;
+}
+.markdown-body { max-width: 860px; margin: 0 auto; padding: 32px 24px; word-wrap: break-word; }
+.with-toc .markdown-body { margin-left: 300px; }
+.toc { position: fixed; top: 0; bottom: 0; left: 0; width: 260px; overflow-y: auto; padding: 24px 16px; box-sizing: border-box; background: var(--surface); border-right: 1px solid var(--border); font-size: 14px; }
+.toc ul { margin: 0; padding-left: 16px; list-style: none; }
+.toc > ul { padding-left: 0; }
+.toc a { color: var(--muted); }
+a { color: var(--link); text-decoration: none; }
+a:hover { text-decoration: underline; }
+h1, h2 { padding-bottom: .3em; border-bottom: 1px solid var(--border); }
+img { max-width: 100%; }
+figure { margin: 1em 0; text-align: center; }
+figcaption { color: var(--muted); font-size: 14px; }
+blockquote { margin: 0; padding: 0 1em; color: var(--muted); border-left: .25em solid var(--border); }
+code { padding: .2em .4em; font-size: 85%; background: var(--surface); border-radius: 6px; font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+pre { padding: 16px; overflow: auto; background: var(--surface); border-radius: 6px; }
+pre code { padding: 0; background: transparent; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { padding: 6px 13px; border: 1px solid var(--border); }
+hr { height: 1px; border: 0; background: var(--border); }
+@media print { .toc { display: none; } .with-toc .markdown-body { margin-left: auto; } }
+@media (max-width: 960px) { .toc { position: static; width: auto; border-right: 0; } .with-toc .markdown-body { margin-left: auto; } }
+`
@@ -0,0 +1,31 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+//go:build !javascript
+// +build !javascript
+
+package lute
+
+import (
+	"embed"
+	"errors"
+)
+
+//go:embed chroma-styles/*.css
+var chromaStyles embed.FS
+
+// chromaStyle 返回内置的代码高亮样式，样式不存在时返回错误。
+func chromaStyle(name string) (css string, err error) {
+	data, err := chromaStyles.ReadFile("chroma-styles/" + name + ".css")
+	if nil != err {
+		return "", errors.New("not found code style [" + name + "]")
+	}
+	return string(data), nil
+}
@@ -0,0 +1,23 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+//go:build javascript
+// +build javascript
+
+package lute
+
+import (
+	"errors"
+)
+
+// chromaStyle 在 JavaScript 端不内置代码高亮样式，以减小 lute.min.js 的体积，总是返回错误。
+func chromaStyle(name string) (css string, err error) {
+	return "", errors.New("code style [" + name + "] is not available in the JavaScript build")
+}
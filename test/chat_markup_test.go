@@ -0,0 +1,79 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var md2SlackTests = []parseTest{
+
+	{"6", "a ``x`y`` and `` `z` `` and `p`\n", "a ```x`y``` and ``` `z` ``` and `p`\n"},
+	{"5", "| h1 | 名字 |\n| :-: | -: |\n| c1 | c2 |\n", "```\nh1 | 名字\n---+-----\nc1 |   c2\n```\n"},
+	{"4", "```go\nfunc main() { `x` }\n```\n\n> quote\n>\n> more\n", "```\nfunc main() { `x` }\n```\n\n> quote\n>\n> more\n"},
+	{"3", "- [ ] todo\n- [X] done\n", "☐ todo\n☑ done\n"},
+	{"2", "- a\n  - b\n    1. c\n", "• a\n    • b\n        1. c\n"},
+	{"1", "1.5 a|b <tag> & x_y! {z} #1 - 2\n", "1.5 a|b &lt;tag&gt; &amp; x_y! {z} #1 - 2\n"},
+	{"0", "# Title\n\n**b** *e* ~~d~~ `c` [link](https://x.com/a_(b)) ![alt](https://x.com/b.png) <https://auto.com>\n", "*Title*\n\n*b* _e_ ~d~ `c` <https://x.com/a_(b)|link> <https://x.com/b.png|alt> <https://auto.com>\n"},
+}
+
+func TestMd2Slack(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range md2SlackTests {
+		msg := luteEngine.Md2Slack(test.from)
+		if test.to != msg {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, msg, test.from)
+		}
+	}
+}
+
+var md2TelegramTests = []parseTest{
+
+	{"5", "| h1 | 名字 |\n| :-: | -: |\n| c1 | c2 |\n", "```\nh1 | 名字\n---+-----\nc1 |   c2\n```\n"},
+	{"4", "```go\nfunc main() { `x` }\n```\n\n> quote\n>\n> more\n", "```go\nfunc main() { \\`x\\` }\n```\n\n>quote\n>\n>more\n"},
+	{"3", "- [ ] todo\n- [X] done\n", "☐ todo\n☑ done\n"},
+	{"2", "- a\n  - b\n    1. c\n", "• a\n    • b\n        1\\. c\n"},
+	{"1", "1.5 a|b <tag> & x_y! {z} #1 - 2\n", "1\\.5 a\\|b <tag\\> & x\\_y\\! \\{z\\} \\#1 \\- 2\n"},
+	{"0", "# Title\n\n**b** *e* ~~d~~ `c` [link](https://x.com/a_(b)) ![alt](https://x.com/b.png) <https://auto.com>\n", "*Title*\n\n*b* _e_ ~d~ `c` [link](https://x.com/a_(b\\)) [alt](https://x.com/b.png) https://auto\\.com\n"},
+}
+
+func TestMd2Telegram(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range md2TelegramTests {
+		msg := luteEngine.Md2Telegram(test.from)
+		if test.to != msg {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, msg, test.from)
+		}
+	}
+}
+
+var md2DiscordTests = []parseTest{
+
+	{"6", "a ``x`y`` and `` `z` `` and `p`\n", "a ``x`y`` and `` `z` `` and `p`\n"},
+	{"5", "| h1 | 名字 |\n| :-: | -: |\n| c1 | c2 |\n", "```\nh1 | 名字\n---+-----\nc1 |   c2\n```\n"},
+	{"4", "```go\nfunc main() { `x` }\n```\n\n> quote\n>\n> more\n", "```go\nfunc main() { `x` }\n```\n\n> quote\n>\n> more\n"},
+	{"3", "- [ ] todo\n- [X] done\n", "☐ todo\n☑ done\n"},
+	{"2", "- a\n  - b\n    1. c\n", "- a\n  - b\n    1. c\n"},
+	{"1", "1.5 a|b <tag> & x_y! {z} #1 - 2\n", "1.5 a\\|b <tag> & x\\_y! {z} #1 - 2\n"},
+	{"0", "# Title\n\n**b** *e* ~~d~~ `c` [link](https://x.com/a_(b)) ![alt](https://x.com/b.png) <https://auto.com>\n", "**Title**\n\n**b** *e* ~~d~~ `c` [link](https://x.com/a_(b)) [alt](https://x.com/b.png) https://auto.com\n"},
+}
+
+func TestMd2Discord(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range md2DiscordTests {
+		msg := luteEngine.Md2Discord(test.from)
+		if test.to != msg {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, msg, test.from)
+		}
+	}
+}
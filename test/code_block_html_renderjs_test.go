@@ -20,6 +20,7 @@ import (
 
 var jsCodeBlockTests = []parseTest{
 
+	{"1", "```go {2} title=\"main.go\" linenostart=10\nvar lute\n```\n", "<div class=\"code-block-title\">main.go</div><pre data-line=\"2\" data-start=\"10\"><code class=\"language-go\">var lute\n</code></pre>\n"},
 	{"0", "```go\nvar lute\n```\n", "<pre><code class=\"language-go\">var lute\n</code></pre>\n"},
 }
 
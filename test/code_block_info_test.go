@@ -0,0 +1,56 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var codeBlockInfoTests = []parseTest{
+
+	{"3", "```go title=\"a&amp;b\"\nx\n```\n", "<div class=\"code-block-title\">a&amp;b</div><pre><code class=\"language-go highlight-chroma\"><span class=\"highlight-line\"><span class=\"highlight-cl\"><span class=\"highlight-nx\">x</span>\n</span></span></code></pre>\n"},
+	{"2", "```go  {3,5-7}   title=\"a b.go\"\nx\n```\n", "<div class=\"code-block-title\">a b.go</div><pre><code class=\"language-go highlight-chroma\"><span class=\"highlight-line\"><span class=\"highlight-cl\"><span class=\"highlight-nx\">x</span>\n</span></span></code></pre>\n"},
+	{"1", "```diff-go\n package main\n-var a = 1\n+var a = 2\n```\n", "<pre><code class=\"language-go highlight-chroma\"><span class=\"highlight-line\"><span class=\"highlight-cl\"> <span class=\"highlight-kn\">package</span> <span class=\"highlight-nx\">main</span>\n</span></span><span class=\"highlight-line\"><span class=\"highlight-cl\"><span class=\"highlight-gd\">-</span><span class=\"highlight-kd\">var</span> <span class=\"highlight-nx\">a</span> <span class=\"highlight-p\">=</span> <span class=\"highlight-mi\">1</span>\n</span></span><span class=\"highlight-line\"><span class=\"highlight-cl\"><span class=\"highlight-gi\">+</span><span class=\"highlight-kd\">var</span> <span class=\"highlight-nx\">a</span> <span class=\"highlight-p\">=</span> <span class=\"highlight-mi\">2</span>\n</span></span></code></pre>\n"},
+	{"0", "```go {2} title=\"main.go\" linenostart=10\npackage main\n\nfunc main() {}\n```\n", "<div class=\"code-block-title\">main.go</div><pre><code class=\"language-go highlight-chroma\"><span class=\"highlight-line\"><span class=\"highlight-ln\">10</span><span class=\"highlight-cl\"><span class=\"highlight-kn\">package</span> <span class=\"highlight-nx\">main</span>\n</span></span><span class=\"highlight-line highlight-hl\"><span class=\"highlight-ln\">11</span><span class=\"highlight-cl\">\n</span></span><span class=\"highlight-line\"><span class=\"highlight-ln\">12</span><span class=\"highlight-cl\"><span class=\"highlight-kd\">func</span> <span class=\"highlight-nf\">main</span><span class=\"highlight-p\">()</span> <span class=\"highlight-p\">{}</span>\n</span></span></code></pre>\n"},
+}
+
+func TestCodeBlockInfo(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range codeBlockInfoTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+		formatted := luteEngine.FormatStr(test.name, test.from)
+		if test.from != formatted {
+			t.Fatalf("test case [%s] format failed\nexpected\n\t%q\ngot\n\t%q", test.name, test.from, formatted)
+		}
+	}
+}
+
+var codeBlockInfoNoHighlightTests = []parseTest{
+
+	{"1", "```diff-go\n package main\n-var a = 1\n+var a = 2\n```\n", "<pre><code class=\"language-diff-go\"> package main\n-var a = 1\n+var a = 2\n</code></pre>\n"},
+	{"0", "```go {2} title=\"main.go\" linenostart=10\npackage main\n\nfunc main() {}\n```\n", "<div class=\"code-block-title\">main.go</div><pre data-line=\"2\" data-start=\"10\"><code class=\"language-go\">package main\n\nfunc main() {}\n</code></pre>\n"},
+}
+
+func TestCodeBlockInfoNoHighlight(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetCodeSyntaxHighlight(false)
+	for _, test := range codeBlockInfoNoHighlightTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var emlTests = []struct {
+	name        string
+	eml         string
+	markdown    string
+	attachments []string // 文件名|MIME 类型|Content-ID|内容
+}{
+
+	{"3", "From: a@example.com\nTo: b@example.com\nSubject: Pics\nDate: Fri, 8 Mar 2024 10:00:00 +0000\nMIME-Version: 1.0\nContent-Type: multipart/related; boundary=\"r\"\n\n--r\nContent-Type: text/html; charset=utf-8\n\n<p><img src=\"cid:img10\" alt=\"b\"> see img1.png</p>\n--r\nContent-Type: image/png\nContent-ID: <img1>\nContent-Transfer-Encoding: base64\n\nUE5H\n--r\nContent-Type: image/png\nContent-ID: <img10>\nContent-Transfer-Encoding: base64\n\nUE5H\n--r--\n", "---\nfrom: \"a@example.com\"\nto: \"b@example.com\"\ndate: \"2024-03-08T10:00:00Z\"\nsubject: \"Pics\"\n---\n![b](img10.png) see img1.png\n\n* [img1.png](img1.png)\n", []string{"img1.png|image/png|img1|PNG", "img10.png|image/png|img10|PNG"}},
+	{"2", "From: =?UTF-8?B?5byg5LiJ?= <zhang@example.com>\r\nTo: Bob <bob@example.com>, carol@example.com\r\nSubject: =?UTF-8?Q?Re:_Quarterly_report_=E2=9C=93?=\r\nDate: Tue, 5 Mar 2024 09:30:00 +0800\r\nMIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=\"mixed\"\r\n\r\n--mixed\r\nContent-Type: multipart/related; boundary=\"related\"\r\n\r\n--related\r\nContent-Type: multipart/alternative; boundary=\"alt\"\r\n\r\n--alt\r\nContent-Type: text/plain; charset=utf-8\r\n\r\nPlain version\r\n--alt\r\nContent-Type: text/html; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n<html><body><p>Hi Bob,</p><p>See the chart: <img src=3D\"cid:chart@example.com\" al=\r\nt=3D\"chart\"></p><div class=3D\"gmail_quote\"><div class=3D\"gmail_attr\">On Mon, Bob wrote:</div><blockquote class=3D\"gmail_quote\"><p>Can you send the =E2=80=9Creport=E2=80=9D?</p></blockquote></div></body></html>\r\n--alt--\r\n--related\r\nContent-Type: image/png\r\nContent-Transfer-Encoding: base64\r\nContent-ID: <chart@example.com>\r\nContent-Disposition: inline\r\n\r\niVBORw0KGgo=\r\n--related--\r\n--mixed\r\nContent-Type: application/pdf; name=\"report Q1.pdf\"\r\nContent-Disposition: attachment; filename=\"report Q1.pdf\"\r\nContent-Transfer-Encoding: base64\r\n\r\nJVBERi0xLjQ=\r\n--mixed--\r\n", "---\nfrom: \"张三 <zhang@example.com>\"\nto: \"Bob <bob@example.com>, carol@example.com\"\ndate: \"2024-03-05T09:30:00+08:00\"\nsubject: \"Re: Quarterly report ✓\"\n---\nHi Bob,\n\nSee the chart: ![chart](chart.png)\n\nOn Mon, Bob wrote:\n\n> Can you send the “report”?\n\n* [report Q1.pdf](report%20Q1.pdf)\n", []string{"chart.png|image/png|chart@example.com|\x89PNG\r\n\x1a\n", "report Q1.pdf|application/pdf||%PDF-1.4"}},
+	{"1", "From: alice@example.com\nTo: bob@example.com\nSubject: Lunch\nDate: Wed, 6 Mar 2024 12:00:00 +0000\nContent-Type: text/plain; charset=iso-8859-1; format=flowed\nContent-Transfer-Encoding: quoted-printable\n\nSure, let's meet at the caf=E9 at noon. It has *great* =\ncoffee and the =\nmenu is=20\nlong.\n\nOn Tue, Bob wrote:\n> Want to grab lunch?\n>> Earlier message\n", "---\nfrom: \"alice@example.com\"\nto: \"bob@example.com\"\ndate: \"2024-03-06T12:00:00Z\"\nsubject: \"Lunch\"\n---\nSure, let's meet at the café at noon. It has \\*great\\* coffee and the menu is long.\n\nOn Tue, Bob wrote:\n\n> Want to grab lunch?\n>\n>> Earlier message\n>>\n", nil},
+	{"0", "From: Dan <dan@example.com>\nTo: eve@example.com\nSubject: FW: Budget\nDate: Thu, 7 Mar 2024 08:00:00 -0500\nContent-Type: text/plain; charset=utf-8\n\nPlease review.\n\n-----Original Message-----\nFrom: Finance\nSent: Wednesday\n\nBudget attached.\n", "---\nfrom: \"Dan <dan@example.com>\"\nto: \"eve@example.com\"\ndate: \"2024-03-07T08:00:00-05:00\"\nsubject: \"FW: Budget\"\n---\nPlease review.\n\n> From: Finance\n> Sent: Wednesday\n>\n> Budget attached.\n", nil},
+}
+
+func TestEML2Markdown(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range emlTests {
+		md, attachments, err := luteEngine.EML2Markdown(strings.NewReader(test.eml))
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.markdown != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal eml\n\t%q", test.name, test.markdown, md, test.eml)
+		}
+		var got []string
+		for _, attachment := range attachments {
+			got = append(got, attachment.Name+"|"+attachment.ContentType+"|"+attachment.ContentID+"|"+string(attachment.Data))
+		}
+		if strings.Join(test.attachments, "\n") != strings.Join(got, "\n") {
+			t.Fatalf("test case [%s] failed\nexpected attachments\n\t%q\ngot\n\t%q", test.name, test.attachments, got)
+		}
+	}
+}
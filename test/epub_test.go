@@ -0,0 +1,108 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/88250/lute"
+)
+
+func TestMarkdown2EPUB(t *testing.T) {
+	baseDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(baseDir, "img"), 0755); nil != err {
+		t.Fatal(err)
+	}
+	png := []byte("\x89PNG\r\n\x1a\n")
+	if err := os.WriteFile(filepath.Join(baseDir, "img", "a.png"), png, 0644); nil != err {
+		t.Fatal(err)
+	}
+
+	luteEngine := lute.New()
+	docs := []string{
+		"Intro.\n\n# One\n\nHello ![a](img/a.png) x[^1]\n\n## Sub\n\n[^1]: Note.\n",
+		"# Two &amp; `2`\n\n![b](img/a.png) ![r](https://x.com/r.png)\n",
+	}
+	meta := &lute.EPUBMeta{Identifier: "urn:isbn:1", Title: "Book", Authors: []string{"D"}, Modified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), BaseDir: baseDir}
+	epub, err := luteEngine.Markdown2EPUB(docs, meta)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(epub), int64(len(epub)))
+	if nil != err {
+		t.Fatal(err)
+	}
+	if "mimetype" != reader.File[0].Name || zip.Store != reader.File[0].Method {
+		t.Fatalf("mimetype must be the first stored entry")
+	}
+	entries := map[string]string{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if nil != err {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		entries[f.Name] = string(data)
+	}
+
+	expected := map[string][]string{
+		"mimetype":               {"application/epub+zip"},
+		"META-INF/container.xml": {"full-path=\"OEBPS/content.opf\""},
+		"OEBPS/content.opf": {
+			"<dc:identifier id=\"uid\">urn:isbn:1</dc:identifier>",
+			"<meta property=\"dcterms:modified\">2024-01-02T03:04:05Z</meta>",
+			"<item id=\"chapter-3\" href=\"chapter-3.xhtml\" media-type=\"application/xhtml+xml\" properties=\"remote-resources\"/>",
+			"<item id=\"image-1\" href=\"images/1.png\" media-type=\"image/png\"/>",
+			"<itemref idref=\"chapter-1\"/>\n<itemref idref=\"chapter-2\"/>\n<itemref idref=\"chapter-3\"/>",
+		},
+		"OEBPS/nav.xhtml": {
+			"<ol><li><a href=\"chapter-1.xhtml\">Book</a></li><li><a href=\"chapter-2.xhtml#One\">One</a><ol><li><a href=\"chapter-2.xhtml#Sub\">Sub</a></li></ol></li><li><a href=\"chapter-3.xhtml#Two--\">Two &amp; <code>2</code></a></li></ol>",
+		},
+		"OEBPS/chapter-1.xhtml": {"<body>\n<p>Intro.</p>\n</body>"},
+		"OEBPS/chapter-2.xhtml": {
+			"<title>One</title>",
+			"<p>Hello <img src=\"images/1.png\" alt=\"a\"/> x<sup",
+			"<li id=\"footnotes-def-1\"><p>Note.",
+		},
+		"OEBPS/chapter-3.xhtml": {"<title>Two &amp; 2</title>", "<p><img src=\"images/1.png\" alt=\"b\"/> <img src=\"https://x.com/r.png\" alt=\"r\"/></p>"},
+		"OEBPS/images/1.png":    {string(png)},
+	}
+	if len(expected) != len(entries) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for name, parts := range expected {
+		content, ok := entries[name]
+		if !ok {
+			t.Fatalf("entry [%s] not found", name)
+		}
+		for _, part := range parts {
+			if !strings.Contains(content, part) {
+				t.Fatalf("entry [%s] does not contain\n\t%q\ngot\n\t%q", name, part, content)
+			}
+		}
+	}
+
+	for _, dest := range []string{"../a.png", "sub/../../a.png", "%2E%2E/a.png", filepath.ToSlash(filepath.Join(baseDir, "img", "a.png"))} {
+		meta.BaseDir = filepath.Join(baseDir, "img", "sub")
+		if _, err = luteEngine.Markdown2EPUB([]string{"![a](" + dest + ")\n"}, meta); nil == err {
+			t.Fatalf("image [%s] outside the base directory should fail", dest)
+		}
+	}
+}
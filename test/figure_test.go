@@ -0,0 +1,82 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+	"github.com/88250/lute/ast"
+)
+
+var figureTests = []parseTest{
+
+	{"2", "text ![a](a.png)\n", "<p>text <img src=\"a.png\" alt=\"a\" /></p>\n"},
+	{"1", "| a |\n| - |\n| b |\n\nTable: Users *list* {#tbl:users}\n", "<table id=\"tbl:users\">\n<caption>Users <em>list</em></caption>\n<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>b</td>\n</tr>\n</tbody>\n</table>\n"},
+	{"0", "![Architecture](arch.png \"System architecture\") {#fig:arch}\n\n![b](b.png)\n\nSee @fig:arch.\n", "<figure id=\"fig:arch\"><img src=\"arch.png\" alt=\"Architecture\" title=\"System architecture\" /><figcaption>System architecture</figcaption></figure>\n<figure><img src=\"b.png\" alt=\"b\" /><figcaption>b</figcaption></figure>\n<p>See @fig:arch.</p>\n"},
+}
+
+func TestFigure(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetFigure(true)
+
+	for _, test := range figureTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
+
+var figureNumberingTests = []parseTest{
+
+	{"4", "- ![a](a.png)\n- b\n\n![c](c.png) {#fig:c}\n\nSee @fig:c.\n", "<ul>\n<li id=\"20060102150405-1a2b3c4\"><img src=\"a.png\" alt=\"a\" /></li>\n<li id=\"20060102150405-1a2b3c4\">b</li>\n</ul>\n<figure id=\"fig:c\"><img src=\"c.png\" alt=\"c\" /><figcaption>Figure 1: c</figcaption></figure>\n<p>See <a href=\"#fig:c\" class=\"cross-ref\">Figure 1</a>.</p>\n"},
+	{"3", "| a |\n| - |\n| b |\n\nTable: Between\n\n| c |\n| - |\n| d |\n", "<table>\n<caption>Table 1: Between</caption>\n<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>b</td>\n</tr>\n</tbody>\n</table>\n<table>\n<thead>\n<tr>\n<th>c</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>d</td>\n</tr>\n</tbody>\n</table>\n"},
+	{"2", "$$\nE=mc^2\n$$\n{: id=\"eq:e\"}\n\nBy @eq:e.\n", "<div class=\"equation\"><div class=\"language-math\" id=\"eq:e\" data-equation-number=\"1\">E=mc^2</div><span class=\"equation-number\">(1)</span></div>\n<p>By <a href=\"#eq:e\" class=\"cross-ref\">Equation 1</a>.</p>\n"},
+	{"1", "| a |\n| - |\n| b |\n\nTable: Users *list* {#tbl:users}\n\nTable: Before\n\n| c |\n| - |\n| d |\n\nRefer to @tbl:users.\n", "<table id=\"tbl:users\">\n<caption>Table 1: Users <em>list</em></caption>\n<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>b</td>\n</tr>\n</tbody>\n</table>\n<table>\n<caption>Table 2: Before</caption>\n<thead>\n<tr>\n<th>c</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>d</td>\n</tr>\n</tbody>\n</table>\n<p>Refer to <a href=\"#tbl:users\" class=\"cross-ref\">Table 1</a>.</p>\n"},
+	{"0", "![Architecture](arch.png \"System architecture\") {#fig:arch}\n\n![b](b.png)\n\nSee @fig:arch, @tbl:users, @eq:e and @fig:none.\n", "<figure id=\"fig:arch\"><img src=\"arch.png\" alt=\"Architecture\" title=\"System architecture\" /><figcaption>Figure 1: System architecture</figcaption></figure>\n<figure><img src=\"b.png\" alt=\"b\" /><figcaption>Figure 2: b</figcaption></figure>\n<p>See <a href=\"#fig:arch\" class=\"cross-ref\">Figure 1</a>, @tbl:users, @eq:e and @fig:none.</p>\n"},
+}
+
+func TestFigureNumbering(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetKramdownBlockIAL(true)
+	luteEngine.SetFigure(true)
+	luteEngine.SetFigureNumbering(true)
+
+	ast.Testing = true
+	for _, test := range figureNumberingTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+	ast.Testing = false
+}
+
+var figureNumberingWithoutFigureTests = []parseTest{
+
+	{"0", "![cat](cat.png){#fig:cat}\n\n$$\nx\n$$\n{: id=\"eq:x\"}\n\nSee @fig:cat and @eq:x.\n", "<p><img src=\"cat.png\" alt=\"cat\" />{#fig:cat}</p>\n<div class=\"equation\"><div class=\"language-math\" id=\"eq:x\" data-equation-number=\"1\">x</div><span class=\"equation-number\">(1)</span></div>\n<p>See @fig:cat and <a href=\"#eq:x\" class=\"cross-ref\">Equation 1</a>.</p>\n"},
+}
+
+func TestFigureNumberingWithoutFigure(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetKramdownBlockIAL(true)
+	luteEngine.SetFigureNumbering(true)
+
+	ast.Testing = true
+	for _, test := range figureNumberingWithoutFigureTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+	ast.Testing = false
+}
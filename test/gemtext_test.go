@@ -0,0 +1,36 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var md2GemtextTests = []parseTest{
+
+	{"4", "\\# not heading\n\nx[^1]\n\n[^1]: note.\n", " # not heading\n\nx[1]\n\n---\n\n[1] note.\n"},
+	{"3", "| h1 | h2 |\n| - | - |\n| c1 | c2 |\n", "```\nh1 | h2\n---+---\nc1 | c2\n```\n"},
+	{"2", "```go\nfunc main() {}\n```\n\n> quote [q](https://q.com)\n>\n> more\n", "```go\nfunc main() {}\n```\n\n> quote q\n>\n> more\n=> https://q.com q\n"},
+	{"1", "- a [l](https://a.com)\n  - b\n    1. c\n- [ ] todo\n", "* a l\n=> https://a.com l\n* b\n* 1. c\n\n* [ ] todo\n"},
+	{"0", "# Title\n\n#### Deep *x*\n\nSee [the site](https://x.com) and <https://auto.com>,\nthen **more**.\n\n![pic](img/a.png)\n", "# Title\n\n### Deep x\n\nSee the site and https://auto.com, then more.\n=> https://x.com the site\n=> https://auto.com\n\n=> img/a.png pic\n"},
+}
+
+func TestMd2Gemtext(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range md2GemtextTests {
+		gemtext := luteEngine.Md2Gemtext(test.from)
+		if test.to != gemtext {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, gemtext, test.from)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var html2MdArticleTests = []struct {
+	name    string
+	baseURL string
+	from    string
+	to      string
+}{
+
+	{"3", "https://example.com/a.html", "<html><head><title>雨季 | 站点</title></head><body><div class=\"nav\"><a href=\"/\">首页</a></div><div><div class=\"post-body\"><p>今年的雨季来得特别早，连续几周的降雨让城市里的河道水位不断上涨，防汛部门已经启动了应急预案，提醒市民注意出行安全，尽量避免前往低洼地带。</p><p>气象专家表示，这种天气形势还将持续一段时间，预计下周中期才会逐渐好转，届时气温也会随之回升，农业生产需要提前做好排涝准备。</p></div><p>短评：注意安全。</p><p>分享</p></div></body></html>", "---\ntitle: \"雨季 | 站点\"\n---\n今年的雨季来得特别早，连续几周的降雨让城市里的河道水位不断上涨，防汛部门已经启动了应急预案，提醒市民注意出行安全，尽量避免前往低洼地带。\n\n气象专家表示，这种天气形势还将持续一段时间，预计下周中期才会逐渐好转，届时气温也会随之回升，农业生产需要提前做好排涝准备。\n\n短评：注意安全。\n"},
+	{"2", "https://example.com/posts/go-slices", "<!DOCTYPE html>\n<html><head>\n<meta charset=\"utf-8\">\n<title>Understanding Go Slices - Example Blog</title>\n<meta property=\"og:title\" content=\"Understanding Go Slices\">\n<meta name=\"author\" content=\"Jane Doe\">\n<meta property=\"article:published_time\" content=\"2023-05-01T10:00:00Z\">\n<base href=\"/blog/\">\n<script>var x = 1;</script>\n<style>body{}</style>\n</head><body>\n<header class=\"site-header\"><a href=\"/\">Example Blog</a><nav><a href=\"/about\">About</a> <a href=\"/posts\">Posts</a></nav></header>\n<div id=\"cookie-banner\">We use cookies to improve your experience. <button>Accept</button></div>\n<div class=\"layout\">\n<aside class=\"sidebar\"><h3>Recent posts</h3><ul><li><a href=\"/a\">Post A</a></li><li><a href=\"/b\">Post B</a></li></ul></aside>\n<article class=\"post\">\n<h1>Understanding Go Slices</h1>\n<p class=\"byline\">By Jane Doe</p>\n<p>Slices are one of the most important data structures in Go, and understanding how they work, how they grow, and how they share memory is essential.</p>\n<p>A slice is a descriptor of an array segment. It consists of a pointer to the array, the length of the segment, and its capacity, which is the maximum length of the segment.</p>\n<img src=\"images/slice.png\" alt=\"Slice diagram\">\n<pre><code class=\"language-go\">s := make([]int, 0, 10)</code></pre>\n<p>See the <a href=\"../spec#Slice_types\">language specification</a> for more details, and the <a href=\"https://go.dev/blog/slices\">official blog</a>, which covers the topic in depth.</p>\n<ul class=\"share-buttons\"><li><a href=\"https://twitter.com/share\">Twitter</a></li><li><a href=\"https://facebook.com/share\">Facebook</a></li></ul>\n</article>\n</div>\n<footer><p>Copyright 2023 Example Blog. All rights reserved, and then some more text here.</p></footer>\n</body></html>", "---\ntitle: \"Understanding Go Slices\"\nauthor: \"Jane Doe\"\ndate: \"2023-05-01T10:00:00Z\"\n---\nSlices are one of the most important data structures in Go, and understanding how they work, how they grow, and how they share memory is essential.\n\nA slice is a descriptor of an array segment. It consists of a pointer to the array, the length of the segment, and its capacity, which is the maximum length of the segment.\n\n![Slice diagram](https://example.com/blog/images/slice.png)\n\n```go\ns := make([]int, 0, 10)\n```\n\nSee the [language specification](https://example.com/spec#Slice_types) for more details, and the [official blog](https://go.dev/blog/slices), which covers the topic in depth.\n"},
+	{"1", "https://news.example.org/2024/rain.html", "<html><head><title>Ignored | Site</title><script type=\"application/ld+json\">{\"@context\":\"https://schema.org\",\"@graph\":[{\"@type\":\"WebSite\",\"name\":\"Site\"},{\"@type\":\"NewsArticle\",\"headline\":\"Rain expected, city prepares\",\"author\":[{\"@type\":\"Person\",\"name\":\"A. Writer\"},{\"@type\":\"Person\",\"name\":\"B. Editor\"}],\"datePublished\":\"2024-02-03\"}]}</script></head><body><div id=\"main-content\"><div class=\"story\"><p>Heavy rain is expected across the region this weekend, officials said on Friday, urging residents to prepare.</p><p>The city has opened three shelters, distributed sandbags, and cleared storm drains in low-lying areas.</p></div><div class=\"related-links\"><a href=\"/x\">Other story</a></div></div></body></html>", "---\ntitle: \"Rain expected, city prepares\"\nauthor: \"A. Writer, B. Editor\"\ndate: \"2024-02-03\"\n---\nHeavy rain is expected across the region this weekend, officials said on Friday, urging residents to prepare.\n\nThe city has opened three shelters, distributed sandbags, and cleared storm drains in low-lying areas.\n"},
+	{"0", "https://my.example.net/notes/testing.html", "<html><head><title>Notes on Testing | My Site</title></head><body><div class=\"menu\"><a href=\"/\">Home</a></div><div class=\"entry-content\"><h1>Notes on Testing</h1><p>Table-driven tests keep each case small, and they make it easy to add a regression case when a bug is fixed.</p><p><img src=\"data:image/gif;base64,R0lGOD\" data-src=\"/img/table.png\" alt=\"table\"></p><p>Run them with <code>go test</code>, or <a href=\"javascript:void(0)\">click here</a> to <a href=\"more.html\">read more</a> about it.</p></div></body></html>", "---\ntitle: \"Notes on Testing\"\n---\nTable-driven tests keep each case small, and they make it easy to add a regression case when a bug is fixed.\n\n![table](https://my.example.net/img/table.png)\n\nRun them with `go test`, or click here to [read more](https://my.example.net/notes/more.html) about it.\n"},
+}
+
+func TestHTML2MarkdownArticle(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range html2MdArticleTests {
+		md, err := luteEngine.HTML2MarkdownArticle(test.from, test.baseURL)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal html\n\t%q", test.name, test.to, md, test.from)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var html2MdCodeTests = []parseTest{
+
+	{"13", "<pre><code class=\"lang-go\">package main</code></pre>", "```go\npackage main\n```\n"},
+	{"12", "<pre><code class=\"hljs go\"><span class=\"hljs-keyword\">package</span> main</code></pre>", "```go\npackage main\n```\n"},
+	{"11", "<div class=\"highlight highlight-source-go notranslate position-relative overflow-auto\"><pre><span class=\"pl-k\">package</span> <span class=\"pl-s1\">main</span></pre></div>", "```go\npackage main\n```\n"},
+	{"10", "<div class=\"highlight-python notranslate\"><div class=\"highlight\"><pre><span></span><span class=\"kn\">import</span> <span class=\"nn\">os</span>\n<span class=\"nb\">print</span><span class=\"p\">(</span><span class=\"n\">os</span><span class=\"p\">)</span>\n</pre></div></div>", "```python\nimport os\nprint(os)\n```\n"},
+	{"9", "<div class=\"highlight-python notranslate\"><table class=\"highlighttable\"><tr><td class=\"linenos\"><div class=\"linenodiv\"><pre>1\n2</pre></div></td><td class=\"code\"><div class=\"highlight\"><pre><span class=\"k\">def</span> <span class=\"nf\">f</span><span class=\"p\">():</span>\n    <span class=\"k\">pass</span>\n</pre></div></td></tr></table></div>", "```python\ndef f():\n    pass\n```\n"},
+	{"8", "<div class=\"highlight\"><pre><span></span><span class=\"linenos\">1</span><span class=\"k\">def</span> <span class=\"nf\">f</span><span class=\"p\">():</span>\n<span class=\"linenos\">2</span>    <span class=\"k\">pass</span>\n</pre></div>", "```\ndef f():\n    pass\n```\n"},
+	{"7", "<div class=\"highlight\"><pre class=\"chroma\"><code class=\"language-go\" data-lang=\"go\"><span class=\"line\"><span class=\"ln\">1</span><span class=\"cl\"><span class=\"kn\">package</span> <span class=\"nx\">main</span>\n</span></span><span class=\"line\"><span class=\"ln\">2</span><span class=\"cl\"><span class=\"kd\">func</span> <span class=\"nx\">main</span><span class=\"p\">()</span> <span class=\"p\">{}</span></span></span></code></pre></div>", "```go\npackage main\nfunc main() {}\n```\n"},
+	{"6", "<div class=\"highlight\"><div class=\"chroma\"><table class=\"lntable\"><tr><td class=\"lntd\"><pre tabindex=\"0\" class=\"chroma\"><code><span class=\"lnt\">1\n</span><span class=\"lnt\">2\n</span></code></pre></td><td class=\"lntd\"><pre tabindex=\"0\" class=\"chroma\"><code class=\"language-rust\" data-lang=\"rust\"><span class=\"line\"><span class=\"cl\"><span class=\"k\">fn</span> <span class=\"nf\">main</span>() {\n</span></span><span class=\"line\"><span class=\"cl\">}</span></span></code></pre></td></tr></table></div></div>", "```rust\nfn main() {\n}\n```\n"},
+	{"5", "<div class=\"Box-body p-0 blob-wrapper data type-go\"><table class=\"highlight tab-size js-file-line-container\"><tr><td id=\"L1\" class=\"blob-num js-line-number\" data-line-number=\"1\"></td><td id=\"LC1\" class=\"blob-code blob-code-inner js-file-line\"><span class=\"pl-k\">package</span> main</td></tr><tr><td id=\"L2\" class=\"blob-num js-line-number\" data-line-number=\"2\"></td><td id=\"LC2\" class=\"blob-code blob-code-inner js-file-line\">\n</td></tr><tr><td id=\"L3\" class=\"blob-num js-line-number\" data-line-number=\"3\"></td><td id=\"LC3\" class=\"blob-code blob-code-inner js-file-line\"><span class=\"pl-k\">func</span> <span class=\"pl-en\">main</span>() {}</td></tr></table></div>", "```go\npackage main\n\nfunc main() {}\n```\n"},
+	{"4", "<pre class=\"brush: js; gutter: false\">var a = 1;</pre>", "```js\nvar a = 1;\n```\n"},
+	{"3", "<pre class=\"sourceCode numberSource python numberLines\"><code class=\"sourceCode python\"><span id=\"cb1-1\"><a href=\"#cb1-1\"></a><span class=\"bu\">print</span>(<span class=\"dv\">1</span>)</span></code></pre>", "```python\nprint(1)\n```\n"},
+	{"2", "<pre data-language=\"ruby\"><code>puts 1</code></pre>", "```ruby\nputs 1\n```\n"},
+	{"1", "<pre><code class=\"nohighlight\">foo</code></pre>", "```\nfoo\n```\n"},
+	{"0", "<table><tr><td>1</td><td>foo</td></tr></table>", "| 1 | foo |\n| --- | ----- |\n"},
+}
+
+func TestHTML2MdCode(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range html2MdCodeTests {
+		md, err := luteEngine.HTML2Markdown(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal html\n\t%q", test.name, test.to, md, test.from)
+		}
+	}
+}
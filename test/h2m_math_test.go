@@ -0,0 +1,54 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var html2MdMathTests = []parseTest{
+
+	{"13", "<p><math><mi>x</mi></math> <math><mi>y</mi></math> and <span class=\"katex\"><math><mi>a</mi></math></span> <span class=\"katex\"><math><mi>b</mi></math></span></p>", "$x$ $y$ and $a$ $b$\n"},
+	{"12", "<table><tr><th>a</th></tr><tr><td><math><mi>x</mi><mo>|</mo><mi>y</mi></math></td></tr></table>", "| a      |\n| -------- |\n| $x\\|y$ |\n"},
+	{"11", "<table><tr><th>a</th></tr><tr><td><span class=\"katex-display\"><span class=\"katex\"><math display=\"block\"><semantics><mi>x</mi><annotation encoding=\"application/x-tex\">x^2</annotation></semantics></math></span></span></td></tr></table>", "| a     |\n| ------- |\n| $x^2$ |\n"},
+	{"10", "<math><mover accent=\"true\"><mi>x</mi><mo>^</mo></mover><mo>+</mo><mi>sin</mi><mo>&#x2061;</mo><mi>θ</mi><mo>+</mo><mi>ℝ</mi><mo>+</mo><mi mathvariant=\"double-struck\">Z</mi><mo>+</mo><mroot><mi>x</mi><mn>3</mn></mroot><mo>+</mo><munder><mi>lim</mi><mrow><mi>n</mi><mo>→</mo><mi mathvariant=\"normal\">∞</mi></mrow></munder><mtext>if x</mtext></math>", "$\\hat{x}+\\sin\\theta+\\mathbb{R}+\\mathbb{Z}+\\sqrt[3]{x}+\\lim_{n\\to\\infty}\\text{if x}$\n"},
+	{"9", "<p>Raw <math><mrow><mi mathvariant=\"normal\">Γ</mi><mo stretchy=\"false\">(</mo><mi>n</mi><mo stretchy=\"false\">)</mo><mo>=</mo><mo stretchy=\"false\">(</mo><mi>n</mi><mo>−</mo><mn>1</mn><mo stretchy=\"false\">)</mo><mo>!</mo></mrow></math> and <math display=\"block\"><mrow><mo fence=\"true\">(</mo><mtable><mtr><mtd><mi>a</mi></mtd><mtd><mi>b</mi></mtd></mtr><mtr><mtd><mi>c</mi></mtd><mtd><mi>d</mi></mtd></mtr></mtable><mo fence=\"true\">)</mo></mrow></math></p>", "Raw $\\Gamma(n)=(n-1)!$ and\n\n$$\n\\left(\\begin{matrix}a & b \\\\ c & d\\end{matrix}\\right)\n$$\n"},
+	{"8", "<p>Wiki <span class=\"mwe-math-element\"><span class=\"mwe-math-mathml-inline mwe-math-mathml-a11y\" style=\"display: none;\"><math xmlns=\"http://www.w3.org/1998/Math/MathML\" alttext=\"{\\displaystyle x^{2}}\"><semantics><mrow class=\"MJX-TeXAtom-ORD\"><mstyle displaystyle=\"true\" scriptlevel=\"0\"><msup><mi>x</mi><mn>2</mn></msup></mstyle></mrow><annotation encoding=\"application/x-tex\">{\\displaystyle x^{2}}</annotation></semantics></math></span><img src=\"https://wikimedia.org/api/rest_v1/media/math/render/svg/x\" class=\"mwe-math-fallback-image-inline\" aria-hidden=\"true\" alt=\"{\\displaystyle x^{2}}\"></span> text</p>", "Wiki $x^{2}$ text\n"},
+	{"7", "<mjx-container class=\"MathJax\" jax=\"CHTML\" display=\"true\"><mjx-math display=\"true\"></mjx-math><mjx-assistive-mml display=\"block\"><math xmlns=\"http://www.w3.org/1998/Math/MathML\" display=\"block\"><mrow><munderover><mo data-mjx-texclass=\"OP\">∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></munderover><msup><mi>i</mi><mn>2</mn></msup><mo>≤</mo><msqrt><mi>α</mi></msqrt></mrow></math></mjx-assistive-mml></mjx-container>", "$$\n\\sum_{i=1}^n i^2\\leq\\sqrt{\\alpha}\n$$\n"},
+	{"6", "<p>v3 <mjx-container class=\"MathJax CtxtMenu_Attached_0\" jax=\"CHTML\"><mjx-math class=\"MJX-TEX\"><mjx-mi>x</mjx-mi></mjx-math><mjx-assistive-mml unselectable=\"on\" display=\"inline\"><math xmlns=\"http://www.w3.org/1998/Math/MathML\"><mfrac><mn>1</mn><mn>2</mn></mfrac><mo>+</mo><msub><mi>x</mi><mi>i</mi></msub></math></mjx-assistive-mml></mjx-container> end</p>", "v3 $\\frac{1}{2}+x_i$ end\n"},
+	{"5", "<p>copied <span class=\"MathJax\" data-mathml=\"&lt;math xmlns=&quot;http://www.w3.org/1998/Math/MathML&quot;&gt;&lt;msqrt&gt;&lt;mi&gt;x&lt;/mi&gt;&lt;/msqrt&gt;&lt;/math&gt;\"><span>junk</span></span> here</p>", "copied $\\sqrt{x}$ here\n"},
+	{"4", "<div class=\"MathJax_Display\"><span class=\"MathJax\" id=\"MathJax-Element-2-Frame\"><span>junk</span></span></div><script type=\"math/tex; mode=display\">\\sum_{i=1}^n i</script>", "$$\n\\sum_{i=1}^n i\n$$\n"},
+	{"3", "<p>Mass <span class=\"MathJax_Preview\" style=\"color: inherit;\"></span><span class=\"MathJax\" id=\"MathJax-Element-1-Frame\" tabindex=\"0\"><nobr><span class=\"math\">junk E</span></nobr></span><script type=\"math/tex\" id=\"MathJax-Element-1\">E=mc^2</script> energy</p>", "Mass $E=mc^2$ energy\n"},
+	{"2", "<p>text <span class=\"katex-display\"><span class=\"katex\"><span class=\"katex-mathml\"><math display=\"block\"><semantics><mrow><mi>x</mi></mrow><annotation encoding=\"application/x-tex\">a=b</annotation></semantics></math></span></span></span> more</p>", "text\n\n$$\na=b\n$$\n\nmore\n"},
+	{"1", "<p>before</p><p><span class=\"katex-display\"><span class=\"katex\"><span class=\"katex-mathml\"><math display=\"block\"><semantics><mrow><mi>x</mi></mrow><annotation encoding=\"application/x-tex\">\\int_0^1 x\\,dx</annotation></semantics></math></span><span class=\"katex-html\">junk</span></span></span></p><p>after</p>", "before\n\n$$\n\\int_0^1 x\\,dx\n$$\n\nafter\n"},
+	{"0", "<p>Euler <span class=\"katex\"><span class=\"katex-mathml\"><math xmlns=\"http://www.w3.org/1998/Math/MathML\"><semantics><mrow><msup><mi>e</mi><mrow><mi>i</mi><mi>π</mi></mrow></msup><mo>+</mo><mn>1</mn><mo>=</mo><mn>0</mn></mrow><annotation encoding=\"application/x-tex\">e^{i\\pi}+1=0</annotation></semantics></math></span><span class=\"katex-html\" aria-hidden=\"true\"><span class=\"base\"><span class=\"mord\">e</span></span></span></span> done.</p>", "Euler $e^{i\\pi}+1=0$ done.\n"},
+}
+
+func TestHTML2MdMath(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range html2MdMathTests {
+		md, err := luteEngine.HTML2Markdown(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal html\n\t%q", test.name, test.to, md, test.from)
+		}
+	}
+
+	blockDOM := luteEngine.HTML2BlockDOM(html2MdMathTests[len(html2MdMathTests)-1].from)
+	if !strings.Contains(blockDOM, "data-type=\"inline-math\" data-subtype=\"math\" data-content=\"e^{i\\pi}+1=0\"") {
+		t.Fatalf("html2 block dom failed, got\n\t%q", blockDOM)
+	}
+}
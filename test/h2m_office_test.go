@@ -0,0 +1,37 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var html2MdOfficeTests = []parseTest{
+
+	{"2", "<table class=MsoTableGrid><tr><td colspan=2 width=200 style='border:solid'><p class=MsoNormal>A</p></td></tr><tr><td class=x><p class=MsoNormal>B</p></td><td><p class=MsoNormal>C</p></td></tr></table>", "| A |   |\n| --- | --- |\n| B | C |\n"},
+	{"1", "<meta charset=\"utf-8\"><b style=\"font-weight:normal;\" id=\"docs-internal-guid-1234abcd-7fff-e6a2-1234-abcdef012345\"><h1 dir=\"ltr\" style=\"line-height:1.38;margin-top:20pt;\"><span style=\"font-size:20pt;font-family:Arial;color:#000000;font-weight:400;\">Title</span></h1><p dir=\"ltr\" style=\"line-height:1.38;margin-top:0pt;margin-bottom:0pt;\"><span style=\"font-size:11pt;font-family:Arial;font-weight:400;font-style:normal;\">Plain </span><span style=\"font-size:11pt;font-family:Arial;font-weight:700;font-style:normal;\">bold</span><span style=\"font-size:11pt;font-weight:400;\"> and </span><span style=\"font-size:11pt;font-weight:400;font-style:italic;\">italic</span><span style=\"text-decoration:line-through;\">gone</span><span style=\"vertical-align:super;\">2</span></p><ul style=\"margin-top:0;margin-bottom:0;padding-inline-start:48px;\"><li dir=\"ltr\" style=\"list-style-type:disc;\" aria-level=\"1\"><p dir=\"ltr\" role=\"presentation\"><span style=\"font-weight:400;\">item</span></p></li></ul></b>", "# Title\n\nPlain **bold** and *italic*~gone~^2^\n\n* item\n"},
+	{"0", "<html xmlns:o=\"urn:schemas-microsoft-com:office:office\"><head><style>p.MsoNormal{margin:0}</style></head><body lang=EN-US><!--StartFragment-->\n<p class=MsoTitle>Report<o:p></o:p></p>\n<p class=MsoNormal>Some <span class=SpellE>speling</span> text with <b>bold</b> and <i>italic</i>.<o:p></o:p></p>\n<p class=MsoNormal><o:p>&nbsp;</o:p></p>\n<p class=MsoListParagraphCxSpFirst style='text-indent:-18.0pt;mso-list:l0 level1 lfo1'><![if !supportLists]><span style='font-family:Symbol;mso-fareast-font-family:Symbol'><span style='mso-list:Ignore'>·<span style='font:7.0pt \"Times New Roman\"'>&nbsp;&nbsp;&nbsp;&nbsp;&nbsp;&nbsp; </span></span></span><![endif]>First<o:p></o:p></p>\n<p class=MsoListParagraphCxSpMiddle style='margin-left:72.0pt;mso-add-space:auto;text-indent:-18.0pt;mso-list:l0 level2 lfo1'><![if !supportLists]><span style='font-family:\"Courier New\"'><span style='mso-list:Ignore'>o<span style='font:7.0pt \"Times New Roman\"'>&nbsp;&nbsp; </span></span></span><![endif]>Nested<o:p></o:p></p>\n<p class=MsoListParagraphCxSpLast style='text-indent:-18.0pt;mso-list:l0 level1 lfo1'><![if !supportLists]><span style='font-family:Symbol'><span style='mso-list:Ignore'>·<span style='font:7.0pt \"Times New Roman\"'>&nbsp;&nbsp; </span></span></span><![endif]>Second<o:p></o:p></p>\n<p class=MsoNormal>Between<o:p></o:p></p>\n<p class=MsoListParagraphCxSpFirst style='text-indent:-18.0pt;mso-list:l1 level1 lfo2'><![if !supportLists]><span><span style='mso-list:Ignore'>1.<span style='font:7.0pt \"Times New Roman\"'>&nbsp;&nbsp; </span></span></span><![endif]>One<o:p></o:p></p>\n<p class=MsoListParagraphCxSpLast style='text-indent:-18.0pt;mso-list:l1 level1 lfo2'><![if !supportLists]><span><span style='mso-list:Ignore'>2.<span style='font:7.0pt \"Times New Roman\"'>&nbsp;&nbsp; </span></span></span><![endif]>Two<o:p></o:p></p>\n<p class=MsoHeading2>Section<o:p></o:p></p>\n<table class=MsoTableGrid border=1 cellspacing=0 cellpadding=0 style='border-collapse:collapse;mso-yfti-tbllook:1184'><tr style='mso-yfti-irow:0'><td width=300 valign=top style='width:225.4pt;padding:0cm 5.4pt'><p class=MsoNormal><b>Name<o:p></o:p></b></p></td><td width=300 valign=top><p class=MsoNormal><b>Value<o:p></o:p></b></p></td></tr><tr><td width=300 valign=top><p class=MsoNormal>a<o:p></o:p></p><p class=MsoNormal>b<o:p></o:p></p></td><td width=300 valign=top><p class=MsoNormal>1<o:p></o:p></p></td></tr></table>\n<!--EndFragment--></body></html>", "# Report\n\nSome speling text with **bold** and \u200b*italic*\u200b.\n\n* First\n  * Nested\n* Second\n\nBetween\n\n1. One\n1. Two\n\n## Section\n\n| **Name** | **Value** |\n| ----------- | ------------ |\n| a<br/>b       | 1          |\n"},
+}
+
+func TestHTML2MdOffice(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range html2MdOfficeTests {
+		md, err := luteEngine.HTML2Markdown(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal html\n\t%q", test.name, test.to, md, test.from)
+		}
+	}
+}
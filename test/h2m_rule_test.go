@@ -0,0 +1,65 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+	"github.com/88250/lute/ast"
+	"github.com/88250/lute/html"
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/util"
+)
+
+var html2MdRuleTests = []parseTest{
+
+	{"4", "<div class=\"callout\"><p>foo</p></div><pre class=\"mermaid\">graph TD;</pre>", "> foo\n\n```mermaid\ngraph TD;\n```\n"},
+	{"3", "<div class=\"callout\"><p>note <strong>this</strong></p></div>", "> note **this**\n"},
+	{"2", "<p>foo <span class=\"badge\">new</span> bar</p>", "foo <span class=\"badge\">new</span> bar\n"},
+	{"1", "<div class=\"widget x\"><b>w</b></div><p>bar</p>", "<div class=\"widget x\"><b>w</b></div>\n\nbar\n"},
+	{"0", "<p>foo</p><div data-ad=\"1\"><p>buy</p></div><p>bar</p>", "foo\n\nbar\n"},
+}
+
+func TestHTML2MdRules(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetHTML2MdRules([]lute.HTML2MdRule{
+		lute.NewHTML2MdRemoveRule(&lute.HTML2MdMatcher{Attr: func(n *html.Node) bool { return "" != util.DomAttrValue(n, "data-ad") }}),
+		lute.NewHTML2MdKeepRule(&lute.HTML2MdMatcher{Tag: "div", Class: "widget"}),
+		lute.NewHTML2MdKeepRule(&lute.HTML2MdMatcher{Tag: "span", Class: "badge"}),
+		&lute.HTML2MdFuncRule{HTML2MdMatcher: &lute.HTML2MdMatcher{Tag: "div", Class: "callout"}, Handler: func(n *html.Node, tree *parse.Tree, convertChildren func()) {
+			blockquote := &ast.Node{Type: ast.NodeBlockquote}
+			blockquote.AppendChild(&ast.Node{Type: ast.NodeBlockquoteMarker, Tokens: []byte(">")})
+			tree.Context.Tip.AppendChild(blockquote)
+			tree.Context.Tip = blockquote
+			convertChildren()
+			tree.Context.ParentTip()
+		}},
+		&lute.HTML2MdFuncRule{HTML2MdMatcher: &lute.HTML2MdMatcher{Tag: "pre", Class: "mermaid"}, Handler: func(n *html.Node, tree *parse.Tree, convertChildren func()) {
+			code := &ast.Node{Type: ast.NodeCodeBlock, IsFencedCodeBlock: true}
+			code.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceOpenMarker, Tokens: []byte("```"), CodeBlockFenceLen: 3})
+			code.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceInfoMarker, CodeBlockInfo: []byte("mermaid")})
+			code.AppendChild(&ast.Node{Type: ast.NodeCodeBlockCode, Tokens: []byte(util.DomText(n))})
+			code.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceCloseMarker, Tokens: []byte("```"), CodeBlockFenceLen: 3})
+			tree.Context.Tip.AppendChild(code)
+		}},
+	})
+
+	for _, test := range html2MdRuleTests {
+		md, err := luteEngine.HTML2Markdown(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal html\n\t%q", test.name, test.to, md, test.from)
+		}
+	}
+}
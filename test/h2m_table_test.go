@@ -0,0 +1,83 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var html2MdTableExpandTests = []parseTest{
+
+	{"3", "<table><caption>Quarterly <b>sales</b></caption><thead><tr><th rowspan=\"2\">Region</th><th colspan=\"2\">2023</th></tr><tr><th>Q1</th><th>Q2</th></tr></thead><tbody><tr><th scope=\"row\">North</th><td>1</td><td>2</td></tr><tr><th scope=\"row\">South</th><td colspan=\"2\">n/a</td></tr></tbody><tfoot><tr><td>Total</td><td>1</td><td>2</td></tr></tfoot></table>", "Quarterly **sales**\n\n| Region          | 2023         |              |\n| ----------------- | -------------- | -------------- |\n|                 | **Q1** | **Q2** |\n| **North** | 1            | 2            |\n| **South** | n/a          |              |\n| Total           | 1            | 2            |\n"},
+	{"2", "<table><tr><th>A</th><th>B</th><th>C</th></tr><tr><td rowspan=\"2\">x</td><td>1</td><td rowspan=\"3\">y</td></tr><tr><td>2</td></tr><tr><td>3</td><td>4</td></tr></table>", "| A | B | C |\n| --- | --- | --- |\n| x | 1 | y |\n|   | 2 |   |\n| 3 | 4 |   |\n"},
+	{"1", "<table><tr><th>Item</th><th>Notes</th></tr><tr><td>a</td><td><p>first</p><p>second</p><ol><li>one</li><li><p>two</p></li></ol></td></tr></table>", "| Item | Notes                      |\n| ------ | ---------------------------- |\n| a    | first<br/>second<br/>1. one<br/>2. two |\n"},
+	{"0", "<table class=\"x\" style=\"width:100%\"><tr><th>Code</th></tr><tr><td><pre><code>a := 1</code></pre><script>alert(1)</script></td></tr></table>", "<table><tbody><tr><th>Code</th></tr><tr><td><pre><code>a := 1</code></pre>  </td></tr></tbody></table>\n"},
+}
+
+func TestHTML2MdTableExpand(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetHTML2MdTableMode(lute.HTML2MdTableExpand)
+	luteEngine.SetKramdownSpanIAL(true)
+	for _, test := range html2MdTableExpandTests {
+		md, err := luteEngine.HTML2Markdown(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal html\n\t%q", test.name, test.to, md, test.from)
+		}
+	}
+}
+
+var html2MdTableIALTests = []parseTest{
+
+	{"1", "<table><caption>Quarterly <b>sales</b></caption><thead><tr><th rowspan=\"2\">Region</th><th colspan=\"2\">2023</th></tr><tr><th>Q1</th><th>Q2</th></tr></thead><tbody><tr><th scope=\"row\">North</th><td>1</td><td>2</td></tr><tr><th scope=\"row\">South</th><td colspan=\"2\">n/a</td></tr></tbody><tfoot><tr><td>Total</td><td>1</td><td>2</td></tr></tfoot></table>", "Quarterly **sales**\n\n| {: colspan=\"1\" rowspan=\"2\"}Region | {: colspan=\"2\" rowspan=\"1\"}2023 | {: class=\"fn__none\"} |\n| ----------------------------------- | --------------------------------- | ---------------------- |\n| {: class=\"fn__none\"}              | **Q1**                    | **Q2**         |\n| **North**                   | 1                               | 2                    |\n| **South**                   | {: colspan=\"2\" rowspan=\"1\"}n/a  | {: class=\"fn__none\"} |\n| Total                             | 1                               | 2                    |\n"},
+	{"0", "<table><tr><th>A</th><th>B</th><th>C</th></tr><tr><td rowspan=\"2\">x</td><td>1</td><td rowspan=\"3\">y</td></tr><tr><td>2</td></tr><tr><td>3</td><td>4</td></tr></table>", "| A                            | B | C                            |\n| ------------------------------ | --- | ------------------------------ |\n| {: colspan=\"1\" rowspan=\"2\"}x | 1 | {: colspan=\"1\" rowspan=\"3\"}y |\n| {: class=\"fn__none\"}         | 2 | {: class=\"fn__none\"}         |\n| 3                            | 4 | {: class=\"fn__none\"}         |\n"},
+}
+
+func TestHTML2MdTableIAL(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetHTML2MdTableMode(lute.HTML2MdTableIAL)
+	luteEngine.SetKramdownSpanIAL(true)
+	for _, test := range html2MdTableIALTests {
+		md, err := luteEngine.HTML2Markdown(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal html\n\t%q", test.name, test.to, md, test.from)
+		}
+	}
+}
+
+var html2MdTableHTMLTests = []parseTest{
+
+	{"2", "<table><caption>Quarterly <b>sales</b></caption><thead><tr><th rowspan=\"2\">Region</th><th colspan=\"2\">2023</th></tr><tr><th>Q1</th><th>Q2</th></tr></thead><tbody><tr><th scope=\"row\">North</th><td>1</td><td>2</td></tr><tr><th scope=\"row\">South</th><td colspan=\"2\">n/a</td></tr></tbody><tfoot><tr><td>Total</td><td>1</td><td>2</td></tr></tfoot></table>", "<table><caption>Quarterly <b>sales</b></caption><thead><tr><th rowspan=\"2\">Region</th><th colspan=\"2\">2023</th></tr><tr><th>Q1</th><th>Q2</th></tr></thead><tbody><tr><th scope=\"row\">North</th><td>1</td><td>2</td></tr><tr><th scope=\"row\">South</th><td colspan=\"2\">n/a</td></tr></tbody><tfoot><tr><td>Total</td><td>1</td><td>2</td></tr></tfoot></table>\n"},
+	{"1", "<table><tr><th>A</th><th>B</th><th>C</th></tr><tr><td rowspan=\"2\">x</td><td>1</td><td rowspan=\"3\">y</td></tr><tr><td>2</td></tr><tr><td>3</td><td>4</td></tr></table>", "<table><tbody><tr><th>A</th><th>B</th><th>C</th></tr><tr><td rowspan=\"2\">x</td><td>1</td><td rowspan=\"3\">y</td></tr><tr><td>2</td></tr><tr><td>3</td><td>4</td></tr></tbody></table>\n"},
+	{"0", "<table><tr><th>Item</th><th>Notes</th></tr><tr><td>a</td><td><p>first</p><p>second</p><ol><li>one</li><li><p>two</p></li></ol></td></tr></table>", "<table><tbody><tr><th>Item</th><th>Notes</th></tr><tr><td>a</td><td><p>first</p><p>second</p><ol><li><p>one</p></li><li><p>two</p></li></ol></td></tr></tbody></table>\n"},
+}
+
+func TestHTML2MdTableHTML(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetHTML2MdTableMode(lute.HTML2MdTableHTML)
+	luteEngine.SetKramdownSpanIAL(true)
+	for _, test := range html2MdTableHTMLTests {
+		md, err := luteEngine.HTML2Markdown(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal html\n\t%q", test.name, test.to, md, test.from)
+		}
+	}
+}
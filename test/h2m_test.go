@@ -55,7 +55,7 @@ var html2MdTests = []parseTest{
 	{"44", "<table border=\"0\" cellpadding=\"0\" cellspacing=\"0\" width=\"72\">\n <colgroup><col width=\"72\">\n </colgroup><tbody><tr height=\"36\">\n\n  <td height=\"36\" class=\"xl65\" width=\"72\">foo<br>\n    bar</td>\n\n </tr>\n</tbody></table>", "| foo<br/>bar |\n| --------- |\n"},
 	{"43", "<!--StartFragment-->foo<strong>bar.</strong><span>baz</span><!--EndFragment-->", "foo**\u200bbar.\u200b**baz\n"},
 	{"42", "\n<!--StartFragment--><img class=\"rich_pages img_loading\" data-ratio=\"0.5625\" data-s=\"300,640\" data-src=\"https://foo\" data-type=\"jpeg\" data-w=\"1280\" data-backw=\"578\" data-backh=\"326\" _width=\"100%\" src=\"data:image/gif;base64,dataimge\" crossorigin=\"anonymous\" alt=\"图片\">", "![图片](https://foo)\n"},
-	{"41", "<section class=\"code-snippet__fix code-snippet__js\"><pre class=\"code-snippet__js\" data-lang=\"makefile\"><code><span class=\"code-snippet_outer\">foo</span></code><code><span class=\"code-snippet_outer\">bar</span></code></pre></section>", "```\nfoo\nbar\n```\n"},
+	{"41", "<section class=\"code-snippet__fix code-snippet__js\"><pre class=\"code-snippet__js\" data-lang=\"makefile\"><code><span class=\"code-snippet_outer\">foo</span></code><code><span class=\"code-snippet_outer\">bar</span></code></pre></section>", "```makefile\nfoo\nbar\n```\n"},
 	{"40", "<!--StartFragment--><strong>foo.</strong><span>bar</span><!--EndFragment-->", "**​foo.​**bar\n"},
 	{"39", "<!--StartFragment--><p><strong>Js版</strong></p><pre>&lt;script&gt;\n&nbsp;&nbsp;&nbsp;&nbsp; test = \"你好abc\"\n&nbsp;&nbsp;&nbsp;&nbsp; str = \"\"\n&nbsp;&nbsp;&nbsp;&nbsp; for( i=0;&nbsp;&nbsp;&nbsp; i&lt;test.length; i++ )\n&nbsp;&nbsp;&nbsp;&nbsp; {\n&nbsp;&nbsp;&nbsp;&nbsp;  temp = test.charCodeAt(i).toString(16);\n&nbsp;&nbsp;&nbsp;&nbsp;  str&nbsp;&nbsp;&nbsp; += \"\\\\u\"+ new Array(5-String(temp).length).join(\"0\") +temp;\n&nbsp;&nbsp;&nbsp;&nbsp; }\n&nbsp;&nbsp;&nbsp;&nbsp; document.write (str)\n&lt;/script&gt;</pre><br><!--EndFragment-->", "**Js 版**\n\n```\n<script>\n\u00a0\u00a0\u00a0\u00a0 test = \"你好abc\"\n\u00a0\u00a0\u00a0\u00a0 str = \"\"\n\u00a0\u00a0\u00a0\u00a0 for( i=0;\u00a0\u00a0\u00a0 i<test.length; i++ )\n\u00a0\u00a0\u00a0\u00a0 {\n\u00a0\u00a0\u00a0\u00a0  temp = test.charCodeAt(i).toString(16);\n\u00a0\u00a0\u00a0\u00a0  str\u00a0\u00a0\u00a0 += \"\\\\u\"+ new Array(5-String(temp).length).join(\"0\") +temp;\n\u00a0\u00a0\u00a0\u00a0 }\n\u00a0\u00a0\u00a0\u00a0 document.write (str)\n</script>\n```\n"},
 	{"38", "<!--StartFragment--><table width=\"778\"><tbody><tr><td class=\"key\">ú</td><td>&amp;uacute;</td><td>&amp;#250;</td><td class=\"key\">û</td><td>&amp;ucirc;</td><td>&amp;#251;</td><td class=\"key\">ü</td><td>&amp;uuml;</td><td>&amp;#252;</td><td class=\"key\">ý</td><td>&amp;yacute;</td><td>&amp;#253;</td><td class=\"key\">þ</td><td>&amp;thorn;</td><td>&amp;#254;</td></tr><tr><td class=\"key\">ÿ</td><td>&amp;yuml;</td></tr></tbody></table><!--EndFragment-->\n", "| ú | &uacute; | &#250; | û | &ucirc; | &#251; | ü | &uuml; | &#252; | ý | &yacute; | &#253; | þ | &thorn; | &#254; |\n| ---- | ---------- | -------- | ---- | --------- | -------- | ---- | -------- | -------- | ---- | ---------- | -------- | ---- | --------- | -------- |\n| ÿ | &yuml;   |\n"},
@@ -109,7 +109,7 @@ var html2MdTests = []parseTest{
     </table>
 </body>
 </html>`, "| Month    | Savings |\n| ---------- | --------- |\n| January  | \\$100   |\n| February | \\$80    |\n"},
-	{"26", "<table class=\"markdown-reference\"><thead><tr><th>Type</th><th class=\"second-example\">Or</th><th>… to Get</th></tr></thead><tbody><tr><td class=\"preformatted\">*Italic*</td><td class=\"preformatted second-example\">_Italic_</td><td><em>Italic</em></td></tr><tr><td class=\"preformatted\">**Bold**</td><td class=\"preformatted second-example\">__Bold__</td><td><strong>Bold</strong></td></tr><tr><td class=\"preformatted\"># Heading 1</td><td class=\"preformatted second-example\">Heading 1<br>=========</td><td><h1 class=\"smaller-h1\">Heading 1</h1></td></tr><tr><td class=\"preformatted\">## Heading 2</td><td class=\"preformatted second-example\">Heading 2<br>---------</td><td><h2 class=\"smaller-h2\">Heading 2</h2></td></tr><tr><td class=\"preformatted\">[Link](http://a.com)</td><td class=\"preformatted second-example\">[Link][1]<br>⋮<br>[1]: http://b.org</td><td><a href=\"https://commonmark.org/\">Link</a></td></tr><tr><td class=\"preformatted\">![Image](http://url/a.png)</td><td class=\"preformatted second-example\">![Image][1]<br>⋮<br>[1]: http://url/b.jpg</td><td><img src=\"https://commonmark.org/help/images/favicon.png\" width=\"36\" height=\"36\" alt=\"Markdown\"></td></tr><tr><td class=\"preformatted\">&gt; Blockquote</td><td class=\"preformatted second-example\">&nbsp;</td><td><blockquote>Blockquote</blockquote></td></tr><tr><td class=\"preformatted\"><p>* List<br>* List<br>* List</p></td><td class=\"preformatted second-example\"><p>- List<br>- List<br>- List<br></p></td><td><ul><li>List</li><li>List</li><li>List</li></ul></td></tr></tbody></table>", "| Type                       | Or                                   | … to Get                                                 |\n| ---------------------------- | -------------------------------------- | ----------------------------------------------------------- |\n| \\*Italic\\*                 | \\_Italic\\_                           | *Italic*                                                |\n| \\*\\*Bold\\*\\*               | \\_\\_Bold\\_\\_                         | **Bold**                                            |\n| # Heading 1                | Heading 1<br/>=========                  | # Heading 1                                              |\n| ## Heading 2               | Heading 2<br/>---------                  | ## Heading 2                                             |\n| [Link](http://a.com)       | [Link][1]<br/>⋮<br/>[1]: http://b.org       | [Link](https://commonmark.org/)                              |\n| ![Image](http://url/a.png) | ![Image][1]<br/>⋮<br/>[1]: http://url/b.jpg | ![Markdown](https://commonmark.org/help/images/favicon.png) |\n| > Blockquote               |                                      | > Blockquote                                     |\n| \\* List<br/>\\* List<br/>\\* List    | - List<br/>- List<br/>- List<br/>                | * List* List* List                                      |\n"},
+	{"26", "<table class=\"markdown-reference\"><thead><tr><th>Type</th><th class=\"second-example\">Or</th><th>… to Get</th></tr></thead><tbody><tr><td class=\"preformatted\">*Italic*</td><td class=\"preformatted second-example\">_Italic_</td><td><em>Italic</em></td></tr><tr><td class=\"preformatted\">**Bold**</td><td class=\"preformatted second-example\">__Bold__</td><td><strong>Bold</strong></td></tr><tr><td class=\"preformatted\"># Heading 1</td><td class=\"preformatted second-example\">Heading 1<br>=========</td><td><h1 class=\"smaller-h1\">Heading 1</h1></td></tr><tr><td class=\"preformatted\">## Heading 2</td><td class=\"preformatted second-example\">Heading 2<br>---------</td><td><h2 class=\"smaller-h2\">Heading 2</h2></td></tr><tr><td class=\"preformatted\">[Link](http://a.com)</td><td class=\"preformatted second-example\">[Link][1]<br>⋮<br>[1]: http://b.org</td><td><a href=\"https://commonmark.org/\">Link</a></td></tr><tr><td class=\"preformatted\">![Image](http://url/a.png)</td><td class=\"preformatted second-example\">![Image][1]<br>⋮<br>[1]: http://url/b.jpg</td><td><img src=\"https://commonmark.org/help/images/favicon.png\" width=\"36\" height=\"36\" alt=\"Markdown\"></td></tr><tr><td class=\"preformatted\">&gt; Blockquote</td><td class=\"preformatted second-example\">&nbsp;</td><td><blockquote>Blockquote</blockquote></td></tr><tr><td class=\"preformatted\"><p>* List<br>* List<br>* List</p></td><td class=\"preformatted second-example\"><p>- List<br>- List<br>- List<br></p></td><td><ul><li>List</li><li>List</li><li>List</li></ul></td></tr></tbody></table>", "| Type                       | Or                                   | … to Get                                                 |\n| ---------------------------- | -------------------------------------- | ----------------------------------------------------------- |\n| \\*Italic\\*                 | \\_Italic\\_                           | *Italic*                                                |\n| \\*\\*Bold\\*\\*               | \\_\\_Bold\\_\\_                         | **Bold**                                            |\n| # Heading 1                | Heading 1<br/>=========                  | # Heading 1                                              |\n| ## Heading 2               | Heading 2<br/>---------                  | ## Heading 2                                             |\n| [Link](http://a.com)       | [Link][1]<br/>⋮<br/>[1]: http://b.org       | [Link](https://commonmark.org/)                              |\n| ![Image](http://url/a.png) | ![Image][1]<br/>⋮<br/>[1]: http://url/b.jpg | ![Markdown](https://commonmark.org/help/images/favicon.png) |\n| > Blockquote               |                                      | > Blockquote                                     |\n| \\* List<br/>\\* List<br/>\\* List    | - List<br/>- List<br/>- List<br/>                | - List<br/>- List<br/>- List                                      |\n"},
 	{"25", "<table class=\"table table-bordered\"><thead class=\"thead-light\"><tr><th>Element</th><th>Markdown Syntax</th></tr></thead><tbody><tr><td><a href=\"https://www.markdownguide.org/extended-syntax/#tables\">Table</a></td><td><code>| Syntax | Description |<br>| ----------- | ----------- |<br>| Header | Title |<br>| Paragraph | Text |</code></td></tr><tr><td><a href=\"https://www.markdownguide.org/extended-syntax/#fenced-code-blocks\">Fenced Code Block</a></td><td><code>```<br>{<br>&nbsp;&nbsp;\"firstName\": \"John\",<br>&nbsp;&nbsp;\"lastName\": \"Smith\",<br>&nbsp;&nbsp;\"age\": 25<br>}<br>```</code></td></tr></tbody></table>", "| Element                                                                             | Markdown Syntax                                                                                                  |\n| ------------------------------------------------------------------------------------- | ------------------------------------------------------------------------------------------------------------------ |\n| [Table](https://www.markdownguide.org/extended-syntax/#tables)                         | `\\| Syntax \\| Description \\|\\| ----------- \\| ----------- \\|\\| Header \\| Title \\|\\| Paragraph \\| Text \\|` |\n| [Fenced Code Block](https://www.markdownguide.org/extended-syntax/#fenced-code-blocks) | ````{\u00a0\u00a0\"firstName\": \"John\",\u00a0\u00a0\"lastName\": \"Smith\",\u00a0\u00a0\"age\": 25}````        |\n"},
 	{"24", "<table><thead><tr><th>Element</th><th>Markdown Syntax</th></tr></thead><tbody><tr><td>Table</td><td><code>| Syntax | Description |<br>| ----------- | ----------- |<br>| Header | Title |<br>| Paragraph | Text |</code></td></tr></tbody></table>", "| Element | Markdown Syntax                                                                                                  |\n| --------- | ------------------------------------------------------------------------------------------------------------------ |\n| Table   | `\\| Syntax \\| Description \\|\\| ----------- \\| ----------- \\|\\| Header \\| Title \\|\\| Paragraph \\| Text \\|` |\n"},
 	{"23", "<h2 style=\"box-sizing: border-box; margin-top: 24px; margin-bottom: 16px; font-weight: 600; font-size: 1.5em; line-height: 1.25; padding-bottom: 0.3em; border-bottom: 1px solid rgb(234, 236, 239); color: rgb(36, 41, 46); font-family: -apple-system, BlinkMacSystemFont, &quot;Segoe UI&quot;, Helvetica, Arial, sans-serif, &quot;Apple Color Emoji&quot;, &quot;Segoe UI Emoji&quot;; font-style: normal; font-variant-ligatures: normal; font-variant-caps: normal; letter-spacing: normal; orphans: 2; text-align: start; text-indent: 0px; text-transform: none; white-space: normal; widows: 2; word-spacing: 0px; -webkit-text-stroke-width: 0px; background-color: rgb(255, 255, 255); text-decoration-style: initial; text-decoration-color: initial;\"><g-emoji class=\"g-emoji\" alias=\"m\" fallback-src=\"https://github.githubassets.com/images/icons/emoji/unicode/24c2.png\" style=\"box-sizing: border-box; font-family: &quot;Apple Color Emoji&quot;, &quot;Segoe UI&quot;, &quot;Segoe UI Emoji&quot;, &quot;Segoe UI Symbol&quot;; font-size: 1.2em; font-weight: 400; line-height: 20px; vertical-align: middle; font-style: normal !important;\">Ⓜ️</g-emoji><span> </span>Markdown User Guide</h2>", "## Ⓜ️ Markdown User Guide\n"},
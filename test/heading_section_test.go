@@ -0,0 +1,37 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var headingSectionsTests = []parseTest{
+
+	{"2", "## Usage\n{: id=\"20210101000000-abcdefg\" class=\"api\" data-x=\"1\"}\n\ntext\n", "<section id=\"20210101000000-abcdefg\" data-x=\"1\" class=\"level2 api\" aria-labelledby=\"Usage\">\n<h2 id=\"Usage\">Usage</h2>\n<p>text</p>\n</section>\n"},
+	{"1", "# A\n\nx[^1]\n\n[^1]: note\n", "<section id=\"section-A\" class=\"level1\" aria-labelledby=\"A\">\n<h1 id=\"A\">A</h1>\n<p>x<sup class=\"footnotes-ref\" id=\"footnotes-ref-1\"><a href=\"#footnotes-def-1\">1</a></sup></p>\n</section>\n<div class=\"footnotes-defs-div\"><hr class=\"footnotes-defs-hr\" />\n<ol class=\"footnotes-defs-ol\"><li id=\"footnotes-def-1\"><p>note <a href=\"#footnotes-ref-1\" class=\"vditor-footnotes__goto-ref\">↩</a></p>\n</li>\n</ol></div>"},
+	{"0", "Intro\n\n# A\n\ntext\n\n## B\n\n> # quoted\n\n### C\n\n## D\n\n# E\n", "<p>Intro</p>\n<section id=\"section-A\" class=\"level1\" aria-labelledby=\"A\">\n<h1 id=\"A\">A</h1>\n<p>text</p>\n<section id=\"section-B\" class=\"level2\" aria-labelledby=\"B\">\n<h2 id=\"B\">B</h2>\n<blockquote>\n<h1 id=\"quoted\">quoted</h1>\n</blockquote>\n<section id=\"section-C\" class=\"level3\" aria-labelledby=\"C\">\n<h3 id=\"C\">C</h3>\n</section>\n</section>\n<section id=\"section-D\" class=\"level2\" aria-labelledby=\"D\">\n<h2 id=\"D\">D</h2>\n</section>\n</section>\n<section id=\"section-E\" class=\"level1\" aria-labelledby=\"E\">\n<h1 id=\"E\">E</h1>\n</section>\n"},
+}
+
+func TestHeadingSections(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetKramdownBlockIAL(true)
+	luteEngine.SetHeadingSections(true)
+
+	for _, test := range headingSectionsTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+	"github.com/88250/lute/render"
+)
+
+var headingSlugTests = []struct {
+	name    string
+	slugger render.HeadingSlugger
+	from    string
+	to      string
+}{
+	{"6", &render.TransliterateSlugger{}, "# 中文标题 Title\n\n## 绿色の森\n", "<h1 id=\"zhong-wen-biao-ti-title\">中文标题 Title</h1>\n<h2 id=\"lu-se-の-sen\">绿色の森</h2>\n"},
+	{"5", &render.GitHubSlugger{}, "# A {#custom}\n\n# A {#custom}\n", "<h1 id=\"custom\">A</h1>\n<h1 id=\"custom-1\">A</h1>\n"},
+	{"4", &render.GitHubSlugger{}, "[toc]\n\n# A\n\n## A\n", "<div class=\"vditor-toc\" data-block=\"0\" data-type=\"toc-block\" contenteditable=\"false\"><ul><li><span data-target-id=\"a\">A</span><ul><li><span data-target-id=\"a-1\">A</span></li></ul></li></ul></div>\n<h1 id=\"a\">A</h1>\n<h2 id=\"a-1\">A</h2>\n"},
+	{"3", &render.TransliterateSlugger{Transliterate: func(r rune) string { return map[rune]string{'中': "zhong", '文': "wen"}[r] }}, "# Crème Brûlée\n\n## 中文 标题\n\n## 中文\n", "<h1 id=\"creme-brulee\">Crème Brûlée</h1>\n<h2 id=\"zhong-wen-标-题\">中文 标题</h2>\n<h2 id=\"zhong-wen\">中文</h2>\n"},
+	{"2", &render.HugoSlugger{}, "# My Heading\n\n## My Heading\n\n## Héllo_World: *x*\n", "<h1 id=\"my-heading\">My Heading</h1>\n<h2 id=\"my-heading-1\">My Heading</h2>\n<h2 id=\"héllo_world-x\">Héllo_World: <em>x</em></h2>\n"},
+	{"1", &render.GitLabSlugger{}, "# My -- Heading\n\n## My -- Heading\n\n## Hello, World!\n", "<h1 id=\"my-heading\">My -- Heading</h1>\n<h2 id=\"my-heading-1\">My -- Heading</h2>\n<h2 id=\"hello-world\">Hello, World!</h2>\n"},
+	{"0", &render.GitHubSlugger{}, "# My Heading\n\n## My Heading\n\n## My Heading\n\n### Héllo, `code` & World!\n\n## 中文 标题\n", "<h1 id=\"my-heading\">My Heading</h1>\n<h2 id=\"my-heading-1\">My Heading</h2>\n<h2 id=\"my-heading-2\">My Heading</h2>\n<h3 id=\"héllo-code--world\">Héllo, <code>code</code> &amp; World!</h3>\n<h2 id=\"中文-标题\">中文 标题</h2>\n"},
+}
+
+func TestHeadingSlugger(t *testing.T) {
+	for _, test := range headingSlugTests {
+		luteEngine := lute.New()
+		luteEngine.SetHeadingID(true)
+		luteEngine.SetToC(true)
+		luteEngine.SetHeadingSlugger(test.slugger)
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/88250/lute"
+	"github.com/88250/lute/ast"
+)
+
+var ipynbTests = []struct {
+	name     string
+	ipynb    string
+	markdown string
+	assets   []string // 文件名|内容
+}{
+
+	{"4", "{\"cells\":[{\"cell_type\":\"code\",\"execution_count\":1,\"metadata\":{},\"outputs\":[{\"data\":{\"text/html\":[\"<b>hi</b>\"],\"text/plain\":[\"'hi'\"]},\"execution_count\":1,\"metadata\":{},\"output_type\":\"execute_result\"}],\"source\":\"h\"}],\"metadata\":{},\"nbformat\":4,\"nbformat_minor\":4}", "---\njupyter: \"{\\\"metadata\\\":{},\\\"nbformat\\\":4,\\\"nbformat_minor\\\":4}\"\n---\n```\nh\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"code\" custom-jupyter-execution-count=\"1\"}\n\n<div>\n<b>hi</b>\n</div>\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"execute_result\" custom-jupyter-text-plain=\"'hi'\" custom-jupyter-execution-count=\"1\"}\n", nil},
+	{"3", "{\"cells\":[null,{\"cell_type\":\"code\",\"execution_count\":null,\"metadata\":{},\"outputs\":[null,{\"name\":\"stdout\",\"output_type\":\"stream\",\"text\":\"x\"}],\"source\":\"x\"}],\"metadata\":{},\"nbformat\":4,\"nbformat_minor\":4}", "---\njupyter: \"{\\\"metadata\\\":{},\\\"nbformat\\\":4,\\\"nbformat_minor\\\":4}\"\n---\n```\nx\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"code\"}\n\n```\nx\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"stream\" custom-jupyter-name=\"stdout\"}\n", nil},
+	{"2", "{\"cells\":[{\"cell_type\":\"code\",\"execution_count\":1,\"id\":\"fence\",\"metadata\":{},\"outputs\":[{\"name\":\"stdout\",\"output_type\":\"stream\",\"text\":[\"ok\\n\"]}],\"source\":[\"print('''\\n\",\"  ```\\n\",\"''')\"]}],\"metadata\":{\"kernelspec\":{\"language\":\"python\"}},\"nbformat\":4,\"nbformat_minor\":5}", "---\njupyter: \"{\\\"metadata\\\":{\\\"kernelspec\\\":{\\\"language\\\":\\\"python\\\"}},\\\"nbformat\\\":4,\\\"nbformat_minor\\\":5}\"\n---\n````python\nprint('''\n  ```\n''')\n````\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"code\" custom-jupyter-id=\"fence\" custom-jupyter-execution-count=\"1\"}\n\n```\nok\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"stream\" custom-jupyter-name=\"stdout\"}\n", nil},
+	{"1", "{\n \"cells\": [\n  {\n   \"cell_type\": \"markdown\",\n   \"id\": \"intro\",\n   \"metadata\": {\"tags\": [\"intro\"]},\n   \"source\": [\"# Analysis\\n\", \"\\n\", \"Some *text* here.\\n\", \"\\n\", \"- a\\n\", \"- b\"]\n  },\n  {\n   \"cell_type\": \"code\",\n   \"execution_count\": 1,\n   \"id\": \"c1\",\n   \"metadata\": {},\n   \"outputs\": [\n    {\"name\": \"stdout\", \"output_type\": \"stream\", \"text\": [\"hello\\n\", \"world\\n\"]},\n    {\"data\": {\"text/html\": [\"<table>\\n\", \"\\n\", \"<tr><td>1</td></tr>\\n\", \"</table>\"], \"text/plain\": [\"   a\\n\", \"0  1\"]}, \"execution_count\": 1, \"metadata\": {}, \"output_type\": \"execute_result\"},\n    {\"data\": {\"image/png\": \"iVBORw0KGgo=\\n\", \"text/plain\": [\"<Figure>\"]}, \"metadata\": {\"needs_background\": \"light\"}, \"output_type\": \"display_data\"}\n   ],\n   \"source\": [\"import pandas as pd\\n\", \"print('hello')\"]\n  },\n  {\n   \"cell_type\": \"code\",\n   \"execution_count\": 2,\n   \"id\": \"c2\",\n   \"metadata\": {\"collapsed\": true},\n   \"outputs\": [\n    {\"ename\": \"ZeroDivisionError\", \"evalue\": \"division by zero\", \"output_type\": \"error\", \"traceback\": [\"\\u001b[0;31mZeroDivisionError\\u001b[0m  Traceback\", \"\\u001b[0;32m1/0\\u001b[0m\"]},\n    {\"data\": {\"text/plain\": [\"42\"]}, \"execution_count\": 2, \"metadata\": {}, \"output_type\": \"execute_result\"}\n   ],\n   \"source\": \"1/0\"\n  },\n  {\n   \"cell_type\": \"raw\",\n   \"id\": \"r1\",\n   \"metadata\": {\"format\": \"text/latex\"},\n   \"source\": \"\\\\LaTeX\"\n  },\n  {\n   \"cell_type\": \"code\",\n   \"execution_count\": null,\n   \"id\": \"c3\",\n   \"metadata\": {},\n   \"outputs\": [],\n   \"source\": []\n  }\n ],\n \"metadata\": {\n  \"kernelspec\": {\"display_name\": \"Python 3\", \"language\": \"python\", \"name\": \"python3\"},\n  \"language_info\": {\"name\": \"python\", \"version\": \"3.11.0\"}\n },\n \"nbformat\": 4,\n \"nbformat_minor\": 5\n}\n", "---\njupyter: \"{\\\"metadata\\\":{\\\"kernelspec\\\":{\\\"display_name\\\":\\\"Python 3\\\",\\\"language\\\":\\\"python\\\",\\\"name\\\":\\\"python3\\\"},\\\"language_info\\\":{\\\"name\\\":\\\"python\\\",\\\"version\\\":\\\"3.11.0\\\"}},\\\"nbformat\\\":4,\\\"nbformat_minor\\\":5}\"\n---\n# Analysis\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"markdown\" custom-jupyter-id=\"intro\" custom-jupyter-metadata=\"{&quot;tags&quot;:[&quot;intro&quot;]}\"}\n\nSome *text* here.\n\n- a\n- b\n\n```python\nimport pandas as pd\nprint('hello')\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"code\" custom-jupyter-id=\"c1\" custom-jupyter-execution-count=\"1\"}\n\n```\nhello\nworld\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"stream\" custom-jupyter-name=\"stdout\"}\n\n<div>\n<table>\n<tr><td>1</td></tr>\n</table>\n</div>\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"execute_result\" custom-jupyter-text-plain=\"   a_esc_newline_0  1\" custom-jupyter-execution-count=\"1\"}\n\n![](cell2-output3.png)\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"display_data\" custom-jupyter-text-plain=\"&lt;Figure&gt;\" custom-jupyter-metadata=\"{&quot;needs_background&quot;:&quot;light&quot;}\"}\n\n```python\n1/0\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"code\" custom-jupyter-id=\"c2\" custom-jupyter-metadata=\"{&quot;collapsed&quot;:true}\" custom-jupyter-execution-count=\"2\"}\n\n```\nZeroDivisionError  Traceback\n1/0\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"error\" custom-jupyter-ename=\"ZeroDivisionError\" custom-jupyter-evalue=\"division by zero\"}\n\n```\n42\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"execute_result\" custom-jupyter-execution-count=\"2\"}\n\n```\n\\LaTeX\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"raw\" custom-jupyter-id=\"r1\" custom-jupyter-metadata=\"{&quot;format&quot;:&quot;text/latex&quot;}\"}\n\n```python\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"code\" custom-jupyter-id=\"c3\"}\n", []string{"cell2-output3.png|\x89PNG\r\n\x1a\n"}},
+	{"0", "{\n \"cells\": [\n  {\n   \"cell_type\": \"markdown\",\n   \"metadata\": {},\n   \"source\": [\n    \"Sum of `a` and `b`:\"\n   ]\n  },\n  {\n   \"cell_type\": \"code\",\n   \"execution_count\": 3,\n   \"metadata\": {},\n   \"outputs\": [\n    {\n     \"data\": {\n      \"text/plain\": [\n       \"3\"\n      ]\n     },\n     \"execution_count\": 3,\n     \"metadata\": {},\n     \"output_type\": \"execute_result\"\n    }\n   ],\n   \"source\": [\n    \"a + b\"\n   ]\n  }\n ],\n \"metadata\": {\n  \"kernelspec\": {\n   \"display_name\": \"Go\",\n   \"language\": \"go\",\n   \"name\": \"gophernotes\"\n  }\n },\n \"nbformat\": 4,\n \"nbformat_minor\": 4\n}\n", "---\njupyter: \"{\\\"metadata\\\":{\\\"kernelspec\\\":{\\\"display_name\\\":\\\"Go\\\",\\\"language\\\":\\\"go\\\",\\\"name\\\":\\\"gophernotes\\\"}},\\\"nbformat\\\":4,\\\"nbformat_minor\\\":4}\"\n---\nSum of `a` and `b`:\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"markdown\"}\n\n```go\na + b\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"code\" custom-jupyter-execution-count=\"3\"}\n\n```\n3\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"execute_result\" custom-jupyter-execution-count=\"3\"}\n", []string(nil)},
+}
+
+func TestIpynb2Markdown(t *testing.T) {
+	ast.Testing = true
+	luteEngine := lute.New()
+	for _, test := range ipynbTests {
+		md, assets, err := luteEngine.Ipynb2Markdown([]byte(test.ipynb))
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.markdown != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal ipynb\n\t%q", test.name, test.markdown, md, test.ipynb)
+		}
+		var got []string
+		for _, asset := range assets {
+			got = append(got, asset.Name+"|"+string(asset.Data))
+		}
+		if strings.Join(test.assets, "\n") != strings.Join(got, "\n") {
+			t.Fatalf("test case [%s] failed\nexpected assets\n\t%q\ngot\n\t%q", test.name, test.assets, got)
+		}
+
+		// 还原后再次转换应该得到相同的 Markdown
+		ipynb, err := luteEngine.Markdown2Ipynb(md, assets)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if md, _, err = luteEngine.Ipynb2Markdown(ipynb); nil != err || test.markdown != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\nrebuilt ipynb\n\t%q", test.name, test.markdown, md, ipynb)
+		}
+	}
+	ast.Testing = false
+}
+
+var markdown2IpynbTests = []struct {
+	name     string
+	markdown string
+	ipynb    string
+}{
+
+	{"2", "---\njupyter: \"{\\\"metadata\\\":{},\\\"nbformat\\\":4,\\\"nbformat_minor\\\":4}\"\n---\n```\nh\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"code\" custom-jupyter-execution-count=\"1\"}\n\n<div>\n<b>hi</b>\n</div>\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"execute_result\" custom-jupyter-text-plain=\"'hi'\" custom-jupyter-execution-count=\"1\"}\n", "{\n \"cells\": [\n  {\n   \"cell_type\": \"code\",\n   \"execution_count\": 1,\n   \"metadata\": {},\n   \"outputs\": [\n    {\n     \"data\": {\n      \"text/html\": [\n       \"<b>hi</b>\"\n      ],\n      \"text/plain\": [\n       \"'hi'\"\n      ]\n     },\n     \"execution_count\": 1,\n     \"metadata\": {},\n     \"output_type\": \"execute_result\"\n    }\n   ],\n   \"source\": [\n    \"h\"\n   ]\n  }\n ],\n \"metadata\": {},\n \"nbformat\": 4,\n \"nbformat_minor\": 4\n}\n"},
+	{"1", "---\njupyter: \"{\\\"metadata\\\":{\\\"kernelspec\\\":{\\\"display_name\\\":\\\"Go\\\",\\\"language\\\":\\\"go\\\",\\\"name\\\":\\\"gophernotes\\\"}},\\\"nbformat\\\":4,\\\"nbformat_minor\\\":4}\"\n---\nSum of `a` and `b`:\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"markdown\"}\n\n```go\na + b\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-cell=\"code\" custom-jupyter-execution-count=\"3\"}\n\n```\n3\n```\n{: id=\"20060102150405-1a2b3c4\" custom-jupyter-output=\"execute_result\" custom-jupyter-execution-count=\"3\"}\n", "{\n \"cells\": [\n  {\n   \"cell_type\": \"markdown\",\n   \"metadata\": {},\n   \"source\": [\n    \"Sum of `a` and `b`:\"\n   ]\n  },\n  {\n   \"cell_type\": \"code\",\n   \"execution_count\": 3,\n   \"metadata\": {},\n   \"outputs\": [\n    {\n     \"data\": {\n      \"text/plain\": [\n       \"3\"\n      ]\n     },\n     \"execution_count\": 3,\n     \"metadata\": {},\n     \"output_type\": \"execute_result\"\n    }\n   ],\n   \"source\": [\n    \"a + b\"\n   ]\n  }\n ],\n \"metadata\": {\n  \"kernelspec\": {\n   \"display_name\": \"Go\",\n   \"language\": \"go\",\n   \"name\": \"gophernotes\"\n  }\n },\n \"nbformat\": 4,\n \"nbformat_minor\": 4\n}\n"},
+	{"0", "# Notes\n\n```python\nprint(1)\n```\n", "{\n \"cells\": [\n  {\n   \"cell_type\": \"markdown\",\n   \"id\": \"20060102150405-1a2b3c4\",\n   \"metadata\": {},\n   \"source\": [\n    \"# Notes\\n\",\n    \"\\n\",\n    \"```python\\n\",\n    \"print(1)\\n\",\n    \"```\"\n   ]\n  }\n ],\n \"metadata\": {},\n \"nbformat\": 4,\n \"nbformat_minor\": 5\n}\n"},
+}
+
+func TestMarkdown2Ipynb(t *testing.T) {
+	ast.Testing = true
+	luteEngine := lute.New()
+	for _, test := range markdown2IpynbTests {
+		ipynb, err := luteEngine.Markdown2Ipynb(test.markdown, nil)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.ipynb != string(ipynb) {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown\n\t%q", test.name, test.ipynb, ipynb, test.markdown)
+		}
+	}
+	ast.Testing = false
+}
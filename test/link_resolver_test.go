@@ -0,0 +1,80 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/88250/lute"
+	"github.com/88250/lute/render"
+)
+
+var testLinkResolver = render.LinkResolverFunc(func(dest string, kind render.LinkKind, docPath string) (string, [][]string) {
+	switch kind {
+	case render.LinkKindImage:
+		return "https://cdn.example.com/" + dest + "?sign=" + path.Base(docPath), nil
+	case render.LinkKindBlockRef:
+		return "/blocks/" + dest + ".html", nil
+	case render.LinkKindFileAnnotationRef:
+		return "", nil
+	}
+	if strings.HasPrefix(dest, "http") {
+		return dest, [][]string{{"rel", "noopener nofollow"}, {"target", "_blank"}}
+	}
+	return strings.TrimSuffix(dest, ".md") + ".html", nil
+})
+
+var linkResolverTests = []parseTest{
+
+	{"1", "see ((20200813131152-0wk5akh \"text\"))\n", "<p>see <a href=\"/blocks/20200813131152-0wk5akh.html\">\"text\"</a></p>\n"},
+	{"0", "[guide](docs/guide.md) and [site](https://example.com) ![logo](img/logo.png \"Logo\")\n", "<p><a href=\"docs/guide.html\">guide</a> and <a href=\"https://example.com\" rel=\"noopener nofollow\" target=\"_blank\">site</a> <img src=\"https://cdn.example.com/img/logo.png?sign=readme.md\" alt=\"logo\" title=\"Logo\" /></p>\n"},
+}
+
+func TestLinkResolver(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetBlockRef(true)
+	luteEngine.SetLinkBase("https://b3log.org/")
+	luteEngine.SetLinkResolver(testLinkResolver)
+
+	for _, test := range linkResolverTests {
+		html := luteEngine.MarkdownStr("notes/readme.md", test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
+
+func TestLinkResolverTextMark(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetTextMark(true)
+	luteEngine.SetLinkResolver(testLinkResolver)
+
+	tree := luteEngine.BlockDOM2Tree("<div data-node-id=\"20230101120000-abcdefg\" data-type=\"NodeParagraph\" class=\"p\"><div contenteditable=\"true\" spellcheck=\"false\">see <span data-type=\"a\" data-href=\"docs/a&amp;b&quot;.md\">a</span></div><div class=\"protyle-attr\" contenteditable=\"false\"></div></div>")
+	html := luteEngine.Tree2HTML(tree, luteEngine.RenderOptions)
+	if expected := "<p id=\"20230101120000-abcdefg\">see <a href=\"docs/a&amp;b&quot;.html\">a</a></p>\n"; expected != html {
+		t.Fatalf("text mark link failed\nexpected\n\t%q\ngot\n\t%q", expected, html)
+	}
+}
+
+func TestLinkResolverTextBundle(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetLinkResolver(testLinkResolver)
+
+	textBundle, originalLinks := luteEngine.TextBundleStr("readme.md", "[a](docs/a.md) ![b](https://b3logfile.com/b.png)\n", []string{"https://b3logfile.com"})
+	if expected := "[a](docs/a.html) ![b](assets/b.png)\n"; expected != textBundle {
+		t.Fatalf("text bundle failed\nexpected\n\t%q\ngot\n\t%q", expected, textBundle)
+	}
+	if 1 != len(originalLinks) || "https://b3logfile.com/b.png" != originalLinks[0] {
+		t.Fatalf("original links failed: %q", originalLinks)
+	}
+}
@@ -0,0 +1,59 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/88250/lute"
+	"github.com/88250/lute/parse"
+)
+
+var linkifierTests = []parseTest{
+
+	{"7", "`#123` [#456](https://example.com) ![#789](a.png)\n", "<p><code>#123</code> <a href=\"https://example.com\">#456</a> <img src=\"a.png\" alt=\"#789\" /></p>\n"},
+	{"6", "a#123 #123b 数#123\n", "<p>a#123 #123b 数#123</p>\n"},
+	{"5", "fix 0123abc and 0123abcd\n", "<p>fix <a href=\"https://github.com/88250/lute/commit/0123abc\" data-subtype=\"commit\">0123abc</a> and <a href=\"https://github.com/88250/lute/commit/0123abcd\" data-subtype=\"commit\">0123abcd</a></p>\n"},
+	{"4", "**#1** and _@bob_.\n", "<p><strong><a href=\"https://github.com/88250/lute/issues/1\" data-subtype=\"issue\">#1</a></strong> and <em><a href=\"https://github.com/bob\" data-subtype=\"mention\">@bob</a></em>.</p>\n"},
+	{"3", "| a      |\n| ------ |\n| PROJ-7 |\n", "<table>\n<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td><a href=\"https://jira.example.com/browse/PROJ-7\" data-subtype=\"ticket\">PROJ-7</a></td>\n</tr>\n</tbody>\n</table>\n"},
+	{"2", "# Heading #12\n", "<h1>Heading <a href=\"https://github.com/88250/lute/issues/12\" data-subtype=\"issue\">#12</a></h1>\n"},
+	{"1", "see GH-12, PROJ-456 and @alice\n", "<p>see <a href=\"https://github.com/88250/lute/issues/12\" data-subtype=\"issue\">GH-12</a>, <a href=\"https://jira.example.com/browse/PROJ-456\" data-subtype=\"ticket\">PROJ-456</a> and <a href=\"https://github.com/alice\" data-subtype=\"mention\">@alice</a></p>\n"},
+	{"0", "fixed #123\n", "<p>fixed <a href=\"https://github.com/88250/lute/issues/123\" data-subtype=\"issue\">#123</a></p>\n"},
+}
+
+func testLinkifiers() []*parse.Linkifier {
+	return []*parse.Linkifier{
+		{Name: "issue", Pattern: regexp.MustCompile(`(?:#|GH-)(\d+)`), Template: "https://github.com/88250/lute/issues/$1"},
+		{Name: "mention", Pattern: regexp.MustCompile(`@(?P<user>[\w-]+)`), Template: "https://github.com/${user}"},
+		{Name: "ticket", Pattern: regexp.MustCompile(`[A-Z][A-Z0-9]+-\d+`), Template: "https://jira.example.com/browse/$0"},
+		{Name: "commit", Pattern: regexp.MustCompile(`[0-9a-f]{7,40}`), Template: "https://github.com/88250/lute/commit/$0"},
+	}
+}
+
+func TestLinkifiers(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetLinkifiers(testLinkifiers())
+
+	for _, test := range linkifierTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+
+	// 格式化时保持原文
+	for _, test := range linkifierTests {
+		if formatted := luteEngine.FormatStr(test.name, test.from); test.from != formatted {
+			t.Fatalf("test case [%s] format failed\nexpected\n\t%q\ngot\n\t%q", test.name, test.from, formatted)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var mathMLTests = []parseTest{
+
+	{"2", "$$\n\\foo\n$$\n", "<div class=\"language-math\">\\foo</div>\n"},
+	{"1", "$$\n\\frac{a}{b}\n$$\n", "<div class=\"math\"><math xmlns=\"http://www.w3.org/1998/Math/MathML\" display=\"block\"><semantics><mfrac><mi>a</mi><mi>b</mi></mfrac><annotation encoding=\"application/x-tex\">\\frac{a}{b}</annotation></semantics></math></div>\n"},
+	{"0", "Euler $e^{i\\pi}+1=0$ and $\\foo$.\n", "<p>Euler <math xmlns=\"http://www.w3.org/1998/Math/MathML\"><semantics><mrow><msup><mi>e</mi><mrow><mi>i</mi><mi>π</mi></mrow></msup><mo>+</mo><mn>1</mn><mo>=</mo><mn>0</mn></mrow><annotation encoding=\"application/x-tex\">e^{i\\pi}+1=0</annotation></semantics></math> and <span class=\"language-math\">\\foo</span>.</p>\n"},
+}
+
+func TestMathML(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetMathML(true)
+
+	for _, test := range mathMLTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
+
+var mathMLTruncatedTests = []string{
+	"{\\text{\\",
+	"\\frac{a}{\\sqrt[3]{b}}",
+	"\\begin{pmatrix} a & b \\\\ c & d \\end{pmatrix}",
+	"\\operatorname*{arg\\,max}_{x} \\mathrm{f}(x)",
+	"\\left( \\sum_{i=1}^{n} x_i^{2} \\right)",
+	"\\text{a \\{ b \\} c}",
+}
+
+func TestMathMLTruncated(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetMathML(true)
+
+	// 截断的公式不能导致渲染时 panic
+	for _, tex := range mathMLTruncatedTests {
+		for i := 0; i <= len(tex); i++ {
+			luteEngine.MarkdownStr("", "$$\n"+tex[:i]+"\n$$\n")
+			luteEngine.MarkdownStr("", "$"+tex[:i]+"$\n")
+		}
+	}
+}
+
+func FuzzMathML(f *testing.F) {
+	for _, tex := range mathMLTruncatedTests {
+		f.Add(tex)
+	}
+	luteEngine := lute.New()
+	luteEngine.SetMathML(true)
+	f.Fuzz(func(t *testing.T, tex string) {
+		luteEngine.MarkdownStr("", "$$\n"+tex+"\n$$\n")
+	})
+}
@@ -0,0 +1,69 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/88250/lute"
+	"github.com/88250/lute/parse"
+)
+
+var mediaEmbedTests = []parseTest{
+
+	{"5", "* https://youtu.be/abcdefghijk\n", "<ul>\n<li><a href=\"https://youtu.be/abcdefghijk\">https://youtu.be/abcdefghijk</a></li>\n</ul>\n"},
+	{"4", "see https://youtu.be/abcdefghijk\n", "<p>see <a href=\"https://youtu.be/abcdefghijk\">https://youtu.be/abcdefghijk</a></p>\n"},
+	{"3", "www.example.com/a.MP3\n", "<div class=\"iframe\"><audio controls=\"controls\" src=\"http://www.example.com/a.MP3\"></audio></div>"},
+	{"2", "[My video](https://x.com/a.mp4?x=1 \"t\")\n", "<div class=\"iframe\"><video controls=\"controls\" src=\"https://x.com/a.mp4?x=1\"></video></div>"},
+	{"1", "https://www.bilibili.com/video/BV1xx411c7mD/?p=2\n", "<div class=\"iframe\"><iframe src=\"https://player.bilibili.com/player.html?bvid=BV1xx411c7mD\" border=\"0\" frameborder=\"no\" framespacing=\"0\" allowfullscreen=\"true\"></iframe></div>"},
+	{"0", "<https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=1>\n\n[Vimeo](https://vimeo.com/12345)\n", "<div class=\"iframe\"><iframe src=\"https://www.youtube.com/embed/dQw4w9WgXcQ\" border=\"0\" frameborder=\"no\" framespacing=\"0\" allowfullscreen=\"true\"></iframe></div><div class=\"iframe\"><iframe src=\"https://player.vimeo.com/video/12345\" border=\"0\" frameborder=\"no\" framespacing=\"0\" allowfullscreen=\"true\"></iframe></div>"},
+}
+
+func TestMediaEmbed(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetMediaEmbed(true)
+
+	for _, test := range mediaEmbedTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+
+	// 格式化时嵌入的媒体还原为原始链接
+	for _, md := range []string{"www.example.com/a.MP3\n", "[My video](https://x.com/a.mp4?x=1 \"t\")\n", "<https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=1>\n\n[Vimeo](https://vimeo.com/12345)\n"} {
+		if formatted := luteEngine.FormatStr("", md); md != formatted {
+			t.Fatalf("format failed\nexpected\n\t%q\ngot\n\t%q", md, formatted)
+		}
+	}
+}
+
+var mediaEmbedProvidersTests = []parseTest{
+
+	{"1", "https://youtu.be/abcdefghijk\n", "<p><a href=\"https://youtu.be/abcdefghijk\">https://youtu.be/abcdefghijk</a></p>\n"},
+	{"0", "https://loom.com/share/abc123\n", "<div class=\"iframe\"><iframe src=\"https://www.loom.com/embed/abc123\" border=\"0\" frameborder=\"no\" framespacing=\"0\" allowfullscreen=\"true\"></iframe></div>"},
+}
+
+func TestMediaEmbedProviders(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetMediaEmbed(true)
+	luteEngine.SetMediaEmbedProviders([]*parse.MediaEmbedProvider{
+		{Name: "Loom", Pattern: regexp.MustCompile(`^https://(?:www\.)?loom\.com/share/(?P<id>\w+)`), Template: "https://www.loom.com/embed/${id}"},
+	})
+
+	for _, test := range mediaEmbedProvidersTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var org2MdTests = []parseTest{
+
+	{"8", "-\n- a\n1.\n2. b\n", "-\n- a\n\n1.\n2. b\n"},
+	{"7", "* H\n:PROPERTIES:\n:ID: 20230101120000-abcdefg\n:END:\nsee [[id:20230101120000-hijklmn][H2]]\n", "# H\n{: id=\"20230101120000-abcdefg\"}\n\nsee ((20230101120000-hijklmn \"H2\"))\n"},
+	{"6", "#+TITLE: Notes\n* Project :work:urgent:\n:PROPERTIES:\n:ID: 20230101120000-abcdefg\n:OWNER: bob\n:END:\nfoo\n", "# Project\n{: tags=\"work,urgent\" id=\"20230101120000-abcdefg\" owner=\"bob\"}\n\nfoo\n\n{: title=\"Notes\"}\n"},
+	{"5", "| a | b |\n|---+---|\n| 1 | 2 |\n", "| a | b |\n| - | - |\n| 1 | 2 |\n"},
+	{"4", "#+BEGIN_SRC go\nfunc main() {}\n#+END_SRC\n\n#+BEGIN_QUOTE\nquoted\n#+END_QUOTE\n", "```go\nfunc main() {}\n```\n\n> quoted\n"},
+	{"3", "- [ ] first\n- [X] second\n  continued\n  - nested +gone+\n1. one\n2. two\n", "- [ ] first\n- [X] second\n  continued\n  - nested ~~gone~~\n\n1. one\n2. two\n"},
+	{"2", "Some /italic/, *bold*, _under_ and =code= with [[https://orgmode.org][Org *site*]] and [[file:img.png]].\n", "Some *italic*, **bold**, <u>under</u> and `code` with [Org **site**](https://orgmode.org) and ![](img.png).\n"},
+	{"1", "* TODO Title\nSCHEDULED: <2023-01-01 Sun>\n** Sub\ntext\n-----\n", "# TODO Title\n\n## Sub\n\ntext\n\n---\n"},
+	{"0", "foo\nbar\n", "foo\nbar\n"},
+}
+
+func TestOrg2Md(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetKramdownIAL(true)
+	luteEngine.SetKramdownBlockIAL(true)
+	luteEngine.SetBlockRef(true)
+	for _, test := range org2MdTests {
+		md := luteEngine.Org2Md(test.from)
+		if test.to != md {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal org\n\t%q", test.name, test.to, md, test.from)
+		}
+	}
+}
+
+var md2OrgTests = []parseTest{
+
+	{"5", "# Project\n{: tags=\"work,urgent\" id=\"20230101120000-abcdefg\"}\n\nsee ((20230101120000-hijklmn \"H2\"))\n{: id=\"20230101120000-opqrstu\"}\n", "* Project :work:urgent:\n:PROPERTIES:\n:ID: 20230101120000-abcdefg\n:END:\n\nsee [[id:20230101120000-hijklmn][H2]]\n"},
+	{"4", "| a | b |\n| - | - |\n| 1 | 2 |\n{: id=\"20230101120000-abcdefg\"}\n", "| a | b |\n|---+---|\n| 1 | 2 |\n"},
+	{"3", "```go\nfunc main() {}\n```\n{: id=\"20230101120000-abcdefg\"}\n\n> quoted\n> {: id=\"20230101120000-hijklmn\"}\n{: id=\"20230101120000-opqrstu\"}\n", "#+BEGIN_SRC go\nfunc main() {}\n#+END_SRC\n\n#+BEGIN_QUOTE\nquoted\n#+END_QUOTE\n"},
+	{"2", "- [ ] first\n- [X] second\n  - nested ~~gone~~\n", "- [ ] first\n- [X] second\n  - nested +gone+\n"},
+	{"1", "Some *italic*, **bold**, `code` and $x$ with [Org](https://orgmode.org) and ![](img.png).\n", "Some /italic/, *bold*, ~code~ and \\(x\\) with [[https://orgmode.org][Org]] and [[file:img.png]].\n"},
+	{"0", "## Sub\n\ntext\n\n---\n", "** Sub\n\ntext\n\n-----\n"},
+}
+
+func TestMd2Org(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetKramdownIAL(true)
+	luteEngine.SetKramdownBlockIAL(true)
+	luteEngine.SetBlockRef(true)
+	for _, test := range md2OrgTests {
+		org := luteEngine.Md2Org(test.from)
+		if test.to != org {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, org, test.from)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var md2OPMLTests = []parseTest{
+
+	{"1", "# Plan\n\nIntro *text*.\n\n## Tasks\n\n- [x] Write `spec`\n\n  Second paragraph\n- [ ] Review & ship\n  - sub\n\n## Notes\n\n1. one\n2. two\n", "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<opml version=\"2.0\">\n  <head>\n    <title></title>\n  </head>\n  <body>\n    <outline text=\"Plan\" _note=\"Intro text.\">\n      <outline text=\"Tasks\">\n        <outline text=\"Write spec\" _note=\"Second paragraph\" _status=\"checked\"/>\n        <outline text=\"Review &amp; ship\" _status=\"unchecked\">\n          <outline text=\"sub\"/>\n        </outline>\n      </outline>\n      <outline text=\"Notes\">\n        <outline text=\"one\"/>\n        <outline text=\"two\"/>\n      </outline>\n    </outline>\n  </body>\n</opml>\n"},
+	{"0", "- a\n  - b\n- c \"quoted\"\n", "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<opml version=\"2.0\">\n  <head>\n    <title></title>\n  </head>\n  <body>\n    <outline text=\"a\">\n      <outline text=\"b\"/>\n    </outline>\n    <outline text=\"c &#34;quoted&#34;\"/>\n  </body>\n</opml>\n"},
+}
+
+func TestMd2OPML(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range md2OPMLTests {
+		result := luteEngine.Md2OPML(test.from)
+		if test.to != result {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown\n\t%q", test.name, test.to, result, test.from)
+		}
+	}
+}
+
+var md2FreeMindTests = []parseTest{
+
+	{"1", "- Root\n  - a\n\n    note line\n  - [ ] b\n", "<map version=\"1.0.1\">\n<node TEXT=\"Root\">\n<node TEXT=\"a\">\n<richcontent TYPE=\"NOTE\"><html><head></head><body><p>note line</p></body></html></richcontent>\n</node>\n<node TEXT=\"b\">\n<icon BUILTIN=\"button_cancel\"/>\n</node>\n</node>\n</map>\n"},
+	{"0", "# A\n\nfoo\nbar\n\n# B\n", "<map version=\"1.0.1\">\n<node TEXT=\"\">\n<node TEXT=\"A\">\n<richcontent TYPE=\"NOTE\"><html><head></head><body><p>foo<br/>bar</p></body></html></richcontent>\n</node>\n<node TEXT=\"B\"/>\n</node>\n</map>\n"},
+}
+
+func TestMd2FreeMind(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range md2FreeMindTests {
+		result := luteEngine.Md2FreeMind(test.from)
+		if test.to != result {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown\n\t%q", test.name, test.to, result, test.from)
+		}
+	}
+}
+
+var opml2MdTests = []parseTest{
+
+	{"2", "<opml version=\"2.0\"><head><title></title></head><body><outline text=\"1. not a list\" _note=\"# hash\n- dash\n\n&amp;lt;b&amp;gt; *bold* [x](y) `c` a_b_c\"><outline text=\"&lt;b&gt;tag&lt;/b&gt; 2) two\"/><outline text=\"+ plus | pipe \\ slash $x$ ~s~\"/></outline><outline text=\"{: id=&quot;x&quot;}\"/><outline text=\"===\"/></body></opml>", "- 1\\. not a list\n\n  \\# hash\n  \\- dash\n\n  \\&lt;b\\&gt; \\*bold\\* \\[x\\](y) \\`c\\` a\\_b\\_c\n\n  - \\<b\\>tag\\</b\\> 2) two\n  - \\+ plus \\| pipe \\\\ slash \\$x\\$ \\~s\\~\n- \\{: id=\"x\"}\n- \\===\n"},
+	{"1", "<?xml version=\"1.0\"?>\n<opml version=\"2.0\"><head><title>Reading list</title></head><body><outline text=\"Books\"><outline text=\"Dune\" _complete=\"true\"/><outline text=\"Neuromancer\" _note=\"Borrowed from Ann&#10;Return by May&#10;&#10;Second note\"/></outline></body></opml>", "- Books\n  - [X] Dune\n  - Neuromancer\n\n    Borrowed from Ann\n    Return by May\n\n    Second note\n"},
+	{"0", "<opml version=\"2.0\"><head/><body><outline text=\"Milestones\"><outline text=\"Alpha\" _status=\"checked\"/><outline text=\"Beta\" _status=\"unchecked\"/></outline><outline text=\"Misc\"/></body></opml>", "- Milestones\n  - [X] Alpha\n  - [ ] Beta\n- Misc\n"},
+}
+
+func TestOPML2Md(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range opml2MdTests {
+		result, err := luteEngine.OPML2Md(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != result {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal opml\n\t%q", test.name, test.to, result, test.from)
+		}
+	}
+}
+
+var freeMind2MdTests = []parseTest{
+
+	{"1", "<map version=\"1.0.1\"><node TEXT=\"Project\"><node TEXT=\"Design\"><icon BUILTIN=\"button_ok\"/><richcontent TYPE=\"NOTE\"><html><head></head><body><p>Use the <b>new</b> layout<br/>with dark mode</p><p>Ask Bob</p></body></html></richcontent></node><node><richcontent TYPE=\"NODE\"><html><body><p>Rich   title</p></body></html></richcontent><node TEXT=\"Leaf\"/></node></node></map>", "- Project\n  - [X] Design\n\n    Use the new layout\n    with dark mode\n\n    Ask Bob\n  - Rich title\n\n    - Leaf\n"},
+	{"0", "<map version=\"1.0.1\">\n<node TEXT=\"\">\n<node TEXT=\"A\"/>\n<node TEXT=\"B\"/>\n</node>\n</map>\n", "- A\n- B\n"},
+}
+
+func TestFreeMind2Md(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range freeMind2MdTests {
+		result, err := luteEngine.FreeMind2Md(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != result {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal mm\n\t%q", test.name, test.to, result, test.from)
+		}
+	}
+}
+
+var kityMinderJSON2MdTests = []parseTest{
+
+	{"2", "{\"root\":{\"data\":{\"text\":\"C\"},\"children\":[null,{\"data\":{\"type\":\"NodeListItem\"},\"children\":[null]},{\"data\":{\"text\":\"A\"},\"children\":[null]}]}}", "- C\n  -\n  - A\n"},
+	{"1", "{\"root\":{\"data\":{\"text\":\"Center\"},\"children\":[{\"data\":{\"text\":\"A\",\"note\":\"n1\\n\\nn2\",\"progress\":9},\"children\":[]},{\"data\":{\"text\":\"B\",\"progress\":1},\"children\":[{\"data\":{\"text\":\"B1\"}}]}]},\"template\":\"default\",\"theme\":\"fresh-blue\"}", "- Center\n  - [X] A\n\n    n1\n\n    n2\n  - [ ] B\n\n    - B1\n"},
+	{"0", "{\"root\":{\"data\":{\"layout\":\"right\",\"text\":\"\",\"id\":\"\",\"type\":\"NodeDocument\",\"isContainer\":true},\"children\":[{\"data\":{\"priority\": \"iconList\",\"text\":\"\",\"id\":\"\",\"type\":\"NodeList\",\"isContainer\":true},\"children\":[{\"data\":{\"text\":\"ab\",\"id\":\"\",\"type\":\"NodeListItem\",\"isContainer\":true},\"children\":[{\"data\":{\"text\":\"a\",\"id\":\"\",\"type\":\"NodeParagraph\",\"isContainer\":false},\"children\":[]},{\"data\":{\"priority\": \"iconList\",\"text\":\"\",\"id\":\"\",\"type\":\"NodeList\",\"isContainer\":true},\"children\":[{\"data\":{\"text\":\"b\",\"id\":\"\",\"type\":\"NodeListItem\",\"isContainer\":true},\"children\":[]}]}]},{\"data\":{\"text\":\"c\",\"id\":\"\",\"type\":\"NodeListItem\",\"isContainer\":true},\"children\":[]}]}]}}", "- a\n  - b\n- c\n"},
+}
+
+var opmlRoundTripTests = []parseTest{
+
+	{"0", "<opml version=\"2.0\"><head><title></title></head><body><outline text=\"1. not a list\" _note=\"# hash\n- dash\n\n&amp;lt;b&amp;gt; *bold* [x](y) `c` a_b_c\"><outline text=\"&lt;b&gt;tag&lt;/b&gt; 2) two\"/><outline text=\"+ plus | pipe \\ slash $x$ ~s~\"/></outline><outline text=\"{: id=&quot;x&quot;}\"/><outline text=\"===\"/></body></opml>", "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<opml version=\"2.0\">\n  <head>\n    <title></title>\n  </head>\n  <body>\n    <outline text=\"1. not a list\" _note=\"# hash&#xA;- dash&#xA;&#xA;&amp;lt;b&amp;gt; *bold* [x](y) `c` a_b_c\">\n      <outline text=\"&lt;b&gt;tag&lt;/b&gt; 2) two\"/>\n      <outline text=\"+ plus | pipe \\ slash $x$ ~s~\"/>\n    </outline>\n    <outline text=\"{: id=&#34;x&#34;}\"/>\n    <outline text=\"===\"/>\n  </body>\n</opml>\n"},
+}
+
+func TestOPMLRoundTrip(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range opmlRoundTripTests {
+		markdown, err := luteEngine.OPML2Md(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		result := luteEngine.Md2OPML(markdown)
+		if test.to != result {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal opml\n\t%q", test.name, test.to, result, test.from)
+		}
+	}
+}
+
+var kityMinderJSONRoundTripTests = []parseTest{
+
+	{"1", "- a\n  - b\n\n    note\n  - c\n", "- a\n  - b\n\n    note\n  - c\n"},
+	{"0", "- a\n\n  note 1\n\n  note 2\n\n  - b\n- c\n", "- a\n\n  note 1\n\n  note 2\n\n  - b\n- c\n"},
+}
+
+func TestKityMinderJSONRoundTrip(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range kityMinderJSONRoundTripTests {
+		jsonStr := luteEngine.RenderKityMinderJSON(test.from)
+		result, err := luteEngine.KityMinderJSON2Md(jsonStr)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != result {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\nkityminder json\n\t%q", test.name, test.to, result, jsonStr)
+		}
+	}
+}
+
+func TestKityMinderJSON2Md(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range kityMinderJSON2MdTests {
+		result, err := luteEngine.KityMinderJSON2Md(test.from)
+		if nil != err {
+			t.Fatalf("unexpected: %s", err)
+		}
+		if test.to != result {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal json\n\t%q", test.name, test.to, result, test.from)
+		}
+	}
+}
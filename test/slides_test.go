@@ -0,0 +1,68 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var md2SlidesTests = []parseTest{
+
+	{"2", "a\n\n---\n{: notes=\"<img src=x onerror=alert(1)> &lt;b&gt; & y\"}\n\nb\n", "<div class=\"reveal\">\n<div class=\"slides\">\n<section>\n<p>a</p>\n</section>\n<section>\n<p>b</p>\n<aside class=\"notes\">\n<p>&lt;img src=x onerror=alert(1)&gt; &lt;b&gt; &amp; y</p>\n</aside>\n</section>\n</div>\n</div>\n"},
+	{"1", "intro\n\n---\n{: background=\"#fff\" transition=\"zoom\" notes=\"hi &amp; bye\"}\n\n# T\n{: data-state=\"x\"}\n\nbody\n", "<div class=\"reveal\">\n<div class=\"slides\">\n<section>\n<p>intro</p>\n</section>\n<section data-background=\"#fff\" data-transition=\"zoom\">\n<h1 id=\"T\">T</h1>\n<p>body</p>\n<aside class=\"notes\">\n<p>hi &amp; bye</p>\n</aside>\n</section>\n</div>\n</div>\n"},
+	{"0", "# A\n\nintro\n\n---\n\nsecond\n\nNote: say **this**\n\nmore notes\n", "<div class=\"reveal\">\n<div class=\"slides\">\n<section>\n<h1 id=\"A\">A</h1>\n<p>intro</p>\n</section>\n<section>\n<p>second</p>\n<aside class=\"notes\">\n<p>say <strong>this</strong></p>\n<p>more notes</p>\n</aside>\n</section>\n</div>\n</div>\n"},
+}
+
+func TestMd2Slides(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetKramdownIAL(true)
+	luteEngine.SetKramdownBlockIAL(true)
+	for _, test := range md2SlidesTests {
+		html := luteEngine.Md2Slides(test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
+
+var md2SlidesHeadingLevelTests = []parseTest{
+
+	{"0", "# A\n\n## A1\n\none\n\n## A2\n\ntwo\n\n# B\n\nb[^1]\n\n[^1]: fn\n", "<div class=\"reveal\">\n<div class=\"slides\">\n<section>\n<section>\n<h1>A</h1>\n</section>\n<section>\n<h2>A1</h2>\n<p>one</p>\n</section>\n<section>\n<h2>A2</h2>\n<p>two</p>\n</section>\n</section>\n<section>\n<h1>B</h1>\n<p>b<sup class=\"footnotes-ref\" id=\"footnotes-ref-1\"><a href=\"#footnotes-def-1\">1</a></sup></p>\n</section>\n<section class=\"footnotes\">\n<div class=\"footnotes-defs-div\"><hr class=\"footnotes-defs-hr\" />\n<ol class=\"footnotes-defs-ol\"><li id=\"footnotes-def-1\"><p>fn <a href=\"#footnotes-ref-1\" class=\"vditor-footnotes__goto-ref\">↩</a></p>\n</li>\n</ol></div>\n</section>\n</div>\n</div>\n"},
+}
+
+func TestMd2SlidesHeadingLevel(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetSlidesHeadingLevel(2)
+	for _, test := range md2SlidesHeadingLevelTests {
+		html := luteEngine.Md2Slides(test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
+
+var md2SlidesRevealJSTests = []parseTest{
+
+	{"0", "# A &amp; B\n\nintro\n\n---\n\nsecond\n", "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\">\n<title>A &amp; B</title>\n<link rel=\"stylesheet\" href=\"https://cdn.jsdelivr.net/npm/reveal.js@5/dist/reveal.css\">\n<link rel=\"stylesheet\" href=\"https://cdn.jsdelivr.net/npm/reveal.js@5/dist/theme/white.css\">\n</head>\n<body>\n<div class=\"reveal\">\n<div class=\"slides\">\n<section>\n<h1>A &amp; B</h1>\n<p>intro</p>\n</section>\n<section>\n<p>second</p>\n</section>\n</div>\n</div>\n<script src=\"https://cdn.jsdelivr.net/npm/reveal.js@5/dist/reveal.js\"></script>\n<script src=\"https://cdn.jsdelivr.net/npm/reveal.js@5/plugin/notes/notes.js\"></script>\n<script>Reveal.initialize({hash: true, plugins: [RevealNotes]});</script>\n</body>\n</html>\n"},
+}
+
+func TestMd2SlidesRevealJS(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetSlidesRevealJS("https://cdn.jsdelivr.net/npm/reveal.js@5/")
+	for _, test := range md2SlidesRevealJSTests {
+		html := luteEngine.Md2Slides(test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
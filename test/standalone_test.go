@@ -0,0 +1,69 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+func TestMarkdown2StandaloneHTML(t *testing.T) {
+	baseDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(baseDir, "img"), 0755); nil != err {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, "img", "a.png"), []byte("PNG"), 0644); nil != err {
+		t.Fatal(err)
+	}
+
+	luteEngine := lute.New()
+	md := "---\ntitle: \"Weekly Report\"\nauthor: [Alice, Bob]\ntags:\n  - go\n  - lute\nlang: en\n---\n\n# Overview\n\n![a](img/a.png)\n\n## Details\n\n```go\nfunc main() {}\n```\n"
+	doc, err := luteEngine.Markdown2StandaloneHTML("report.md", md, &lute.StandaloneHTMLOptions{Theme: "auto", ToC: true, BaseDir: baseDir, CSS: ".custom{}"})
+	if nil != err {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"<!DOCTYPE html>\n<html lang=\"en\">",
+		"<title>Weekly Report</title>",
+		"<meta name=\"author\" content=\"Alice, Bob\">",
+		"<meta name=\"keywords\" content=\"go, lute\">",
+		"@media (prefers-color-scheme: dark)",
+		".highlight-chroma",
+		".custom{}",
+		"<nav class=\"toc\"><ul><li><a href=\"#Overview\">Overview</a><ul><li><a href=\"#Details\">Details</a></li></ul></li></ul></nav>",
+		"<img src=\"data:image/png;base64,UE5H\" alt=\"a\" />",
+	} {
+		if !strings.Contains(doc, expected) {
+			t.Fatalf("standalone html should contain %q, got\n%s", expected, doc)
+		}
+	}
+	if strings.Contains(doc, "vditor-yml-front-matter") {
+		t.Fatalf("front matter should not be rendered")
+	}
+
+	if _, err = luteEngine.Markdown2StandaloneHTML("report.md", md, &lute.StandaloneHTMLOptions{Theme: "pink"}); nil == err {
+		t.Fatalf("unknown theme should fail")
+	}
+	// 读取失败或者超出基础目录的图片保留原地址
+	for _, dest := range []string{"missing.png", "../a.png", "%2E%2E/a.png", filepath.ToSlash(filepath.Join(baseDir, "img", "a.png"))} {
+		doc, err = luteEngine.Markdown2StandaloneHTML("report.md", "![a]("+dest+")\n", &lute.StandaloneHTMLOptions{BaseDir: filepath.Join(baseDir, "img", "sub")})
+		if nil != err {
+			t.Fatal(err)
+		}
+		if strings.Contains(doc, "data:image/png") {
+			t.Fatalf("image [%s] should not be embedded", dest)
+		}
+	}
+}
@@ -11,6 +11,7 @@
 package test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/88250/lute"
@@ -33,3 +34,59 @@ func TestToC(t *testing.T) {
 		}
 	}
 }
+
+var tocNavTests = []parseTest{
+
+	{"2", "[toc]\n\nfoo\n", "<p>foo</p>\n"},
+	{"1", "[toc]\n\n# A\n\n## B\n\n### C\n\n> ## D\n\n# E\n", "<nav class=\"toc\"><ol><li><a href=\"#A\">1 A</a><ol><li><a href=\"#B\">1.1 B</a></li><li><a href=\"#D\">1.2 D</a></li></ol></li><li><a href=\"#E\">2 E</a></li></ol></nav>\n<h1 id=\"A\"><span class=\"heading-number\">1</span> A</h1>\n<h2 id=\"B\"><span class=\"heading-number\">1.1</span> B</h2>\n<h3 id=\"C\">C</h3>\n<blockquote>\n<h2 id=\"D\"><span class=\"heading-number\">1.2</span> D</h2>\n</blockquote>\n<h1 id=\"E\"><span class=\"heading-number\">2</span> E</h1>\n"},
+	{"0", "[toc]\n\n# A\n\n## B\n", "<nav class=\"toc\"><ol><li><a href=\"#A\">1 A</a><ol><li><a href=\"#B\">1.1 B</a></li></ol></li></ol></nav>\n<h1 id=\"A\"><span class=\"heading-number\">1</span> A</h1>\n<h2 id=\"B\"><span class=\"heading-number\">1.1</span> B</h2>\n"},
+}
+
+func TestToCNav(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetToC(true)
+	luteEngine.SetToCNav(true)
+	luteEngine.SetToCOrdered(true)
+	luteEngine.SetToCNumbering(true)
+	luteEngine.SetToCNumberHeadings(true)
+	luteEngine.SetToCNestedHeadings(true)
+	luteEngine.SetToCMaxLevel(2)
+
+	for _, test := range tocNavTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
+
+var tocLevelTests = []parseTest{
+
+	{"0", "[toc]\n\n# A\n\n## B\n\n### C\n\n> ## D\n\n# E\n", "<div class=\"vditor-toc\" data-block=\"0\" data-type=\"toc-block\" contenteditable=\"false\"><ul><li><span data-target-id=\"B\">B</span><ul><li><span data-target-id=\"C\">C</span></li></ul></li></ul></div>\n<h1 id=\"A\">A</h1>\n<h2 id=\"B\">B</h2>\n<h3 id=\"C\">C</h3>\n<blockquote>\n<h2 id=\"D\">D</h2>\n</blockquote>\n<h1 id=\"E\">E</h1>\n"},
+}
+
+func TestToCMinLevel(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetToC(true)
+	luteEngine.SetToCMinLevel(2)
+
+	for _, test := range tocLevelTests {
+		html := luteEngine.MarkdownStr(test.name, test.from)
+		if test.to != html {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, html, test.from)
+		}
+	}
+}
+
+func TestLuteToC(t *testing.T) {
+	luteEngine := lute.New()
+	headings := luteEngine.ToC("# A\n\n## B `c`\n\n# C\n")
+	data, err := json.Marshal(headings)
+	if nil != err {
+		t.Fatalf("marshal headings failed: %s", err)
+	}
+	expected := `[{"id":"A","box":"","path":"","hPath":"","content":"A","level":1,"number":"1","children":[{"id":"B-","box":"","path":"","hPath":"","content":"B \u003ccode\u003ec\u003c/code\u003e","level":2,"number":"1.1","children":null}]},{"id":"C","box":"","path":"","hPath":"","content":"C","level":1,"number":"2","children":null}]`
+	if expected != string(data) {
+		t.Fatalf("toc failed\nexpected\n\t%s\ngot\n\t%s", expected, data)
+	}
+}
@@ -0,0 +1,80 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/88250/lute"
+)
+
+var md2ConfluenceTests = []parseTest{
+
+	{"5", "| h1 | h2 |\n| :-: | - |\n| c1 | c2 |\n", "<table><tbody>\n<tr><th style=\"text-align: center;\">h1</th><th>h2</th></tr>\n<tr><td style=\"text-align: center;\">c1</td><td>c2</td></tr>\n</tbody></table>\n"},
+	{"4", "> [!WARNING]\n> be careful\n\n> plain\n", "<ac:structured-macro ac:name=\"warning\"><ac:rich-text-body>\n<p>be careful</p>\n</ac:rich-text-body></ac:structured-macro>\n<blockquote>\n<p>plain</p>\n</blockquote>\n"},
+	{"3", "```go\nfunc main() {}\n```\n", "<ac:structured-macro ac:name=\"code\"><ac:parameter ac:name=\"language\">go</ac:parameter><ac:plain-text-body><![CDATA[func main() {}]]></ac:plain-text-body></ac:structured-macro>\n"},
+	{"2", "- [ ] todo\n- [X] done\n", "<ac:task-list>\n<ac:task><ac:task-status>incomplete</ac:task-status><ac:task-body>todo</ac:task-body></ac:task>\n<ac:task><ac:task-status>complete</ac:task-status><ac:task-body>done</ac:task-body></ac:task>\n</ac:task-list>\n"},
+	{"1", "- a\n  1. b\n", "<ul>\n<li>a\n<ol>\n<li>b</li>\n</ol>\n</li>\n</ul>\n"},
+	{"0", "# Title\n\n**b** [link](https://x.com) ![alt](img/a.png) ![r](https://x.com/b.png)\n", "<h1>Title</h1>\n<p><strong>b</strong> <a href=\"https://x.com\">link</a> <ac:image ac:alt=\"alt\"><ri:attachment ri:filename=\"a.png\" /></ac:image> <ac:image ac:alt=\"r\"><ri:url ri:value=\"https://x.com/b.png\" /></ac:image></p>\n"},
+}
+
+func TestMd2Confluence(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range md2ConfluenceTests {
+		storage := luteEngine.Md2Confluence(test.from)
+		if test.to != storage {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, storage, test.from)
+		}
+	}
+}
+
+var md2JiraTests = []parseTest{
+
+	{"7", "Literal \\-x- +y+ ^z^ \\*b\\* \\_i\\_ and snake_case, well-known, a - b, 1+1, *real*\n", "Literal \\-x\\- \\+y\\+ \\^z\\^ \\*b\\* \\_i\\_ and snake_case, well-known, a - b, 1+1, _real_\n"},
+	{"6", "- a\n\n  b\n\n  c\n- d\n  - e\n\n    f\n", "* a \\\\ b \\\\ c\n* d\n** e \\\\ f\n"},
+	{"5", "| h1 | h2 |\n| - | - |\n| c1 | c\\|2 |\n", "||h1||h2||\n|c1|c\\|2|\n"},
+	{"4", "> [!TIP]\n> try this\n\n> plain\n", "{tip}\ntry this\n{tip}\n\n{quote}\nplain\n{quote}\n"},
+	{"3", "```go\nfunc main() {}\n```\n", "{code:go}\nfunc main() {}\n{code}\n"},
+	{"2", "- a\n  - b\n    1. c\n- [ ] todo\n", "* a\n** b\n**# c\n\n* (off) todo\n"},
+	{"1", "**b** *em* ~~del~~ `code` [link](https://x.com) ![alt](a.png) {brace}\n", "*b* _em_ -del- {{code}} [link|https://x.com] !a.png|alt=alt! \\{brace\\}\n"},
+	{"0", "# Title\n\n## Sub\n", "h1. Title\n\nh2. Sub\n"},
+}
+
+func TestMd2Jira(t *testing.T) {
+	luteEngine := lute.New()
+	for _, test := range md2JiraTests {
+		wiki := luteEngine.Md2Jira(test.from)
+		if test.to != wiki {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, wiki, test.from)
+		}
+	}
+}
+
+var md2MediaWikiTests = []parseTest{
+
+	{"5", "- a\n\n  b\n\n  c\n- d\n  - e\n\n    f\n", "* a<br />b<br />c\n* d\n** e<br />f\n"},
+	{"4", "note[^1]\n\n[^1]: the note\n", "note<ref>the note</ref>\n\n<references />\n"},
+	{"3", "| h1 | h2 |\n| :-: | - |\n| c1 | c2 |\n", "{| class=\"wikitable\"\n! style=\"text-align: center;\" | h1 !! h2\n|-\n| style=\"text-align: center;\" | c1 || c2\n|}\n"},
+	{"2", "```go\nfunc main() {}\n```\n\n```\n<a>\n```\n", "<syntaxhighlight lang=\"go\">\nfunc main() {}\n</syntaxhighlight>\n\n<pre>&lt;a&gt;</pre>\n"},
+	{"1", "- a\n  - b\n    1. c\n", "* a\n** b\n**# c\n"},
+	{"0", "# Title\n\n**b** *em* `code` [link](https://x.com) ![alt](img/a.png)\n", "= Title =\n\n'''b''' ''em'' <code><nowiki>code</nowiki></code> [https://x.com link] [[File:a.png|alt=alt]]\n"},
+}
+
+func TestMd2MediaWiki(t *testing.T) {
+	luteEngine := lute.New()
+	luteEngine.SetFootnotes(true)
+	for _, test := range md2MediaWikiTests {
+		wiki := luteEngine.Md2MediaWiki(test.from)
+		if test.to != wiki {
+			t.Fatalf("test case [%s] failed\nexpected\n\t%q\ngot\n\t%q\noriginal markdown text\n\t%q", test.name, test.to, wiki, test.from)
+		}
+	}
+}
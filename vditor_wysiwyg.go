@@ -1533,7 +1533,8 @@ func (lute *Lute) isInline(n *html.Node) bool {
 		atom.Img == n.DataAtom ||
 		atom.U == n.DataAtom ||
 		atom.Kbd == n.DataAtom ||
-		atom.Span == n.DataAtom
+		atom.Span == n.DataAtom ||
+		atom.Math == n.DataAtom
 }
 
 func (lute *Lute) prefixSpaces(text string) (ret string) {
@@ -0,0 +1,39 @@
+// Lute - 一款结构化的 Markdown 引擎，支持 Go 和 JavaScript
+// Copyright (c) 2019-present, b3log.org
+//
+// Lute is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//         http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package lute
+
+import (
+	"github.com/88250/lute/parse"
+	"github.com/88250/lute/render"
+	"github.com/88250/lute/util"
+)
+
+// Md2Confluence 将 Markdown 转换为 Confluence 存储格式（XHTML）。
+func (lute *Lute) Md2Confluence(markdown string) (storage string) {
+	return lute.md2(markdown, render.NewConfluenceRenderer)
+}
+
+// Md2Jira 将 Markdown 转换为 Jira 维基标记。
+func (lute *Lute) Md2Jira(markdown string) (wiki string) {
+	return lute.md2(markdown, render.NewJiraRenderer)
+}
+
+// Md2MediaWiki 将 Markdown 转换为 MediaWiki 维基标记。
+func (lute *Lute) Md2MediaWiki(markdown string) (wiki string) {
+	return lute.md2(markdown, render.NewMediaWikiRenderer)
+}
+
+// md2 使用 newRenderer 创建的渲染器渲染 Markdown。
+func (lute *Lute) md2(markdown string, newRenderer func(tree *parse.Tree, options *render.Options) render.Renderer) string {
+	tree := parse.Parse("", []byte(markdown), lute.ParseOptions)
+	renderer := newRenderer(tree, lute.RenderOptions)
+	return util.BytesToStr(renderer.Render())
+}